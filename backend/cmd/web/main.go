@@ -1,10 +1,16 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"go-expense-management-system/internal/command"
 	"go-expense-management-system/internal/config"
 	"go-expense-management-system/internal/utils"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 func main() {
@@ -16,7 +22,7 @@ func main() {
 	validate := config.NewValidator()
 	router := config.NewGin(viperConfig)
 
-	config.Bootstrap(&config.BootstrapConfig{
+	workers := config.Bootstrap(&config.BootstrapConfig{
 		DB:       db,
 		Router:   router,
 		Log:      log,
@@ -28,10 +34,36 @@ func main() {
 	if !executor.Execute(log) {
 		return
 	}
+	workers.Start()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
 	webPort := viperConfig.GetInt("PORT")
-	err := router.Run(fmt.Sprintf(":%d", webPort))
-	if err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", webPort),
+		Handler: router,
+	}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	log.Info("Shutting down server")
+
+	shutdownTimeout := time.Duration(viperConfig.GetInt("SHUTDOWN_TIMEOUT_SECONDS")) * time.Second
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Warnf("Failed to shut down server gracefully: %v", err)
+	}
+	if err := workers.Stop(shutdownCtx); err != nil {
+		log.Warnf("Background workers stopped before in-flight work finished: %v", err)
 	}
+	log.Info("Server stopped")
 }
@@ -0,0 +1,65 @@
+package background
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type ApprovalEscalateFunc func(context.Context, int) (int, error)
+
+type ApprovalEscalator struct {
+	*lifecycle
+	log        *logrus.Logger
+	interval   time.Duration
+	batchSize  int
+	escalateFn ApprovalEscalateFunc
+}
+
+func NewApprovalEscalator(interval time.Duration, batchSize int, log *logrus.Logger, escalateFn ApprovalEscalateFunc) *ApprovalEscalator {
+	if batchSize <= 0 {
+		batchSize = 50
+	}
+
+	return &ApprovalEscalator{
+		lifecycle:  newLifecycle(),
+		log:        log,
+		interval:   interval,
+		batchSize:  batchSize,
+		escalateFn: escalateFn,
+	}
+}
+
+func (e *ApprovalEscalator) Start() {
+	if e.interval <= 0 {
+		return
+	}
+
+	e.run(func() {
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				e.RunOnce()
+			case <-e.stop:
+				return
+			}
+		}
+	})
+}
+
+func (e *ApprovalEscalator) RunOnce() {
+	escalated, err := e.escalateFn(e.ctx, e.batchSize)
+	if err != nil {
+		if e.log != nil {
+			e.log.Warnf("Approval escalation failed: %+v", err)
+		}
+		return
+	}
+	if escalated > 0 && e.log != nil {
+		e.log.Infof("Escalated %d overdue approval steps", escalated)
+	}
+}
@@ -0,0 +1,53 @@
+package background
+
+import (
+	"context"
+	"sync"
+)
+
+// lifecycle runs the loop of a periodic worker and stops it: Stop signals the loop,
+// cancels the context of a pass still running at the deadline and returns only
+// after the loop goroutine has exited.
+type lifecycle struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+func newLifecycle() *lifecycle {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &lifecycle{
+		ctx:    ctx,
+		cancel: cancel,
+		stop:   make(chan struct{}),
+	}
+}
+
+func (l *lifecycle) run(loop func()) {
+	l.done = make(chan struct{})
+	go func() {
+		defer close(l.done)
+		loop()
+	}()
+}
+
+func (l *lifecycle) Stop(ctx context.Context) error {
+	l.stopOnce.Do(func() { close(l.stop) })
+	defer l.cancel()
+
+	if l.done == nil {
+		return nil
+	}
+
+	select {
+	case <-l.done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	l.cancel()
+	<-l.done
+	return ctx.Err()
+}
@@ -0,0 +1,126 @@
+package background
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/repository"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type PaymentJobQueue struct {
+	db         *gorm.DB
+	log        *logrus.Logger
+	repository *repository.PaymentJobRepository
+	lease      time.Duration
+}
+
+func NewPaymentJobQueue(db *gorm.DB, log *logrus.Logger, repository *repository.PaymentJobRepository, lease time.Duration) *PaymentJobQueue {
+	if lease <= 0 {
+		lease = time.Minute
+	}
+
+	return &PaymentJobQueue{
+		db:         db,
+		log:        log,
+		repository: repository,
+		lease:      lease,
+	}
+}
+
+func (q *PaymentJobQueue) Enqueue(db *gorm.DB, job model.PaymentJob) error {
+	record := &entity.PaymentJob{
+		ExpenseID:  job.ExpenseID,
+		AmountIDR:  job.AmountIDR,
+		ExternalID: job.ExternalID,
+		Status:     constants.PaymentJobStatusPending,
+		NextRunAt:  time.Now(),
+	}
+
+	if _, err := q.repository.Enqueue(db, record); err != nil {
+		if q.log != nil {
+			q.log.Warnf("Failed to persist payment job for expense %s: %+v", job.ExpenseID, err)
+		}
+		return err
+	}
+	return nil
+}
+
+func (q *PaymentJobQueue) Claim(ctx context.Context, limit int) ([]entity.PaymentJob, error) {
+	var jobs []entity.PaymentJob
+	err := q.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		claimed, err := q.repository.Claim(tx, time.Now(), limit, q.lease)
+		if err != nil {
+			return err
+		}
+		jobs = claimed
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (q *PaymentJobQueue) Complete(ctx context.Context, job *entity.PaymentJob) error {
+	return q.repository.MarkCompleted(q.db.WithContext(ctx), job)
+}
+
+func (q *PaymentJobQueue) Retry(ctx context.Context, job *entity.PaymentJob, nextRunAt time.Time, cause error) error {
+	return q.repository.Reschedule(q.db.WithContext(ctx), job, nextRunAt, errorText(cause))
+}
+
+func (q *PaymentJobQueue) Release(ctx context.Context, job *entity.PaymentJob) error {
+	return q.repository.Release(q.db.WithContext(ctx), job, time.Now())
+}
+
+func (q *PaymentJobQueue) Hold(ctx context.Context, job *entity.PaymentJob, cause error) error {
+	return q.repository.Hold(q.db.WithContext(ctx), job, errorText(cause))
+}
+
+func (q *PaymentJobQueue) Fail(ctx context.Context, job *entity.PaymentJob, cause error) error {
+	return q.repository.MarkFailed(q.db.WithContext(ctx), job, errorText(cause))
+}
+
+func (q *PaymentJobQueue) Rescan(ctx context.Context) (int, error) {
+	statuses := []string{
+		constants.ExpenseStatusApproved,
+		constants.ExpenseStatusAutoApproved,
+		constants.ExpenseStatusPaymentProcessing,
+	}
+	expenses, err := q.repository.ListUnqueuedExpenses(q.db.WithContext(ctx), statuses)
+	if err != nil {
+		return 0, err
+	}
+
+	enqueued := 0
+	for i := range expenses {
+		job := model.PaymentJob{
+			ExpenseID:  expenses[i].ID,
+			AmountIDR:  expenses[i].AmountIDR,
+			ExternalID: expenses[i].ID.String(),
+		}
+		if err := q.Enqueue(q.db.WithContext(ctx), job); err == nil {
+			enqueued++
+		}
+	}
+	return enqueued, nil
+}
+
+func errorText(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	text := err.Error()
+	if cause := errors.Unwrap(err); cause != nil && cause.Error() != text {
+		text = fmt.Sprintf("%s: %s", text, cause.Error())
+	}
+	return text
+}
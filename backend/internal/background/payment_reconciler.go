@@ -0,0 +1,61 @@
+package background
+
+import (
+	"context"
+	"go-expense-management-system/internal/model"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type PaymentReconcileFunc func(context.Context) (model.PaymentReconcileResult, error)
+
+type PaymentReconciler struct {
+	*lifecycle
+	log         *logrus.Logger
+	interval    time.Duration
+	reconcileFn PaymentReconcileFunc
+}
+
+func NewPaymentReconciler(interval time.Duration, log *logrus.Logger, reconcileFn PaymentReconcileFunc) *PaymentReconciler {
+	return &PaymentReconciler{
+		lifecycle:   newLifecycle(),
+		log:         log,
+		interval:    interval,
+		reconcileFn: reconcileFn,
+	}
+}
+
+func (r *PaymentReconciler) Start() {
+	if r.interval <= 0 {
+		return
+	}
+
+	r.run(func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				r.RunOnce()
+			case <-r.stop:
+				return
+			}
+		}
+	})
+}
+
+func (r *PaymentReconciler) RunOnce() {
+	result, err := r.reconcileFn(r.ctx)
+	if err != nil {
+		if r.log != nil {
+			r.log.Warnf("Payment reconciliation failed: %+v", err)
+		}
+		return
+	}
+	if result.Checked > 0 && r.log != nil {
+		r.log.Infof("Payment reconciliation checked %d payments: %d completed, %d failed, %d pending, %d errors",
+			result.Checked, result.Completed, result.Failed, result.Pending, result.Errors)
+	}
+}
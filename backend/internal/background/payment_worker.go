@@ -2,33 +2,71 @@ package background
 
 import (
 	"context"
+	"errors"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/integration/payment"
 	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/model/converter"
+	"go-expense-management-system/internal/repository"
+	"go-expense-management-system/internal/utils"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
 type PaymentProcessorFunc func(context.Context, model.PaymentJob) error
 
+type PaymentFailureFunc func(context.Context, model.PaymentJob, string) error
+
 type PaymentWorker struct {
-	jobs       chan model.PaymentJob
-	log        *logrus.Logger
-	retryCount int
-	retryDelay time.Duration
-	timeout    time.Duration
-	processFn  PaymentProcessorFunc
+	queue         *PaymentJobQueue
+	log           *logrus.Logger
+	workers       int
+	batchSize     int
+	retryCount    int
+	retryDelay    time.Duration
+	maxRetryDelay time.Duration
+	timeout       time.Duration
+	pollInterval  time.Duration
+	processFn     PaymentProcessorFunc
+	failFn        PaymentFailureFunc
+	random        func(int64) int64
+	active        atomic.Int64
+	ctx           context.Context
+	cancel        context.CancelFunc
+	wake          chan struct{}
+	stop          chan struct{}
+	stopOnce      sync.Once
+	done          chan struct{}
+	inflight      sync.WaitGroup
+	mu            sync.Mutex
+	running       map[uuid.UUID]entity.PaymentJob
 }
 
 func NewPaymentWorker(
-	buffer int,
+	queue *PaymentJobQueue,
+	workers int,
+	batchSize int,
 	retryCount int,
 	retryDelay time.Duration,
+	maxRetryDelay time.Duration,
 	timeout time.Duration,
+	pollInterval time.Duration,
 	log *logrus.Logger,
 	processFn PaymentProcessorFunc,
+	failFn PaymentFailureFunc,
 ) *PaymentWorker {
-	if buffer <= 0 {
-		buffer = 100
+	if workers <= 0 {
+		workers = 1
+	}
+	if batchSize <= 0 {
+		batchSize = 10
 	}
 	if retryCount <= 0 {
 		retryCount = 3
@@ -36,56 +74,321 @@ func NewPaymentWorker(
 	if retryDelay <= 0 {
 		retryDelay = time.Second
 	}
+	if maxRetryDelay < retryDelay {
+		maxRetryDelay = retryDelay
+	}
 	if timeout <= 0 {
 		timeout = 10 * time.Second
 	}
+	if pollInterval <= 0 {
+		pollInterval = 5 * time.Second
+	}
 
+	ctx, cancel := context.WithCancel(context.Background())
 	return &PaymentWorker{
-		jobs:       make(chan model.PaymentJob, buffer),
-		log:        log,
-		retryCount: retryCount,
-		retryDelay: retryDelay,
-		timeout:    timeout,
-		processFn:  processFn,
+		queue:         queue,
+		log:           log,
+		workers:       workers,
+		batchSize:     batchSize,
+		retryCount:    retryCount,
+		retryDelay:    retryDelay,
+		maxRetryDelay: maxRetryDelay,
+		timeout:       timeout,
+		pollInterval:  pollInterval,
+		processFn:     processFn,
+		failFn:        failFn,
+		random:        rand.Int64N,
+		ctx:           ctx,
+		cancel:        cancel,
+		wake:          make(chan struct{}, 1),
+		stop:          make(chan struct{}),
+		running:       make(map[uuid.UUID]entity.PaymentJob),
 	}
 }
 
 func (w *PaymentWorker) Start() {
+	w.done = make(chan struct{})
+
+	go func() {
+		defer close(w.done)
+
+		w.rescan()
+
+		ticker := time.NewTicker(w.pollInterval)
+		defer ticker.Stop()
+
+		for {
+			w.drain()
+
+			select {
+			case <-ticker.C:
+			case <-w.wake:
+			case <-w.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (w *PaymentWorker) Stop(ctx context.Context) error {
+	w.stopOnce.Do(func() { close(w.stop) })
+
+	finished := make(chan struct{})
 	go func() {
-		for job := range w.jobs {
-			w.handleJob(job)
+		if w.done != nil {
+			<-w.done
 		}
+		w.inflight.Wait()
+		close(finished)
 	}()
+
+	select {
+	case <-finished:
+		w.cancel()
+		return nil
+	case <-ctx.Done():
+	}
+
+	// cancel the payment calls still running and hand their jobs back only once
+	// every job goroutine has returned, so none of them can record a result later
+	w.cancel()
+	<-finished
+	w.release()
+	return ctx.Err()
 }
 
-func (w *PaymentWorker) Enqueue(job model.PaymentJob) bool {
+func (w *PaymentWorker) stopped() bool {
 	select {
-	case w.jobs <- job:
+	case <-w.stop:
 		return true
 	default:
+		return false
+	}
+}
+
+func (w *PaymentWorker) release() {
+	w.mu.Lock()
+	jobs := make([]entity.PaymentJob, 0, len(w.running))
+	for _, job := range w.running {
+		jobs = append(jobs, job)
+	}
+	w.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+
+	released := 0
+	for i := range jobs {
+		if err := w.queue.Release(ctx, &jobs[i]); err != nil {
+			if w.log != nil {
+				w.log.Warnf("Failed to release unfinished payment job %s: %+v", jobs[i].ID, err)
+			}
+			continue
+		}
+		released++
+	}
+	if released > 0 && w.log != nil {
+		w.log.Infof("Released %d unfinished payment jobs back to the queue", released)
+	}
+}
+
+func (w *PaymentWorker) Enqueue(db *gorm.DB, job model.PaymentJob) error {
+	return w.queue.Enqueue(db, job)
+}
+
+func (w *PaymentWorker) Notify() {
+	w.signal()
+}
+
+func (w *PaymentWorker) signal() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (w *PaymentWorker) rescan() {
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+
+	enqueued, err := w.queue.Rescan(ctx)
+	if err != nil {
 		if w.log != nil {
-			w.log.Warnf("Payment queue full, dropping job for expense %s", job.ExpenseID)
+			w.log.Warnf("Failed to rescan approved expenses for payment: %+v", err)
 		}
-		return false
+		return
+	}
+	if enqueued > 0 && w.log != nil {
+		w.log.Infof("Recovered %d approved expenses into the payment queue", enqueued)
 	}
 }
 
-func (w *PaymentWorker) handleJob(job model.PaymentJob) {
-	for attempt := 1; attempt <= w.retryCount; attempt++ {
+func (w *PaymentWorker) drain() {
+	for {
+		if w.stopped() {
+			return
+		}
+
+		idle := w.workers - int(w.active.Load())
+		if idle <= 0 {
+			return
+		}
+
 		ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
-		err := w.processFn(ctx, job)
+		jobs, err := w.queue.Claim(ctx, min(idle, w.batchSize))
 		cancel()
 
-		if err == nil {
+		if err != nil {
+			if w.log != nil {
+				w.log.Warnf("Failed to claim payment jobs: %+v", err)
+			}
+			return
+		}
+		if len(jobs) == 0 {
 			return
 		}
 
+		for _, job := range jobs {
+			w.active.Add(1)
+			w.inflight.Add(1)
+			w.mu.Lock()
+			w.running[job.ID] = job
+			w.mu.Unlock()
+
+			go func(job entity.PaymentJob) {
+				defer func() {
+					w.active.Add(-1)
+					w.inflight.Done()
+					w.signal()
+				}()
+				if w.handleJob(job) {
+					w.mu.Lock()
+					delete(w.running, job.ID)
+					w.mu.Unlock()
+				}
+			}(job)
+		}
+	}
+}
+
+// handleJob reports false when Stop interrupted the job, which leaves it to be released.
+func (w *PaymentWorker) handleJob(job entity.PaymentJob) bool {
+	ctx, cancel := context.WithTimeout(w.ctx, w.timeout)
+	err := w.processFn(ctx, converter.PaymentJobToModel(&job))
+	cancel()
+
+	if err != nil && w.ctx.Err() != nil {
+		return false
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+
+	if err == nil {
+		if err := w.queue.Complete(ctx, &job); err != nil {
+			w.warnUpdate(job, "completed", err)
+		}
+		return true
+	}
+
+	// a held payout waits for its precondition instead of using up retries
+	var held interface{ Held() bool }
+	if errors.As(err, &held) && held.Held() {
 		if w.log != nil {
-			w.log.Warnf("Payment job failed (attempt %d/%d) for %s: %+v", attempt, w.retryCount, job.ExpenseID, err)
+			w.log.Infof("Holding payment job for %s: %+v", job.ExpenseID, err)
+		}
+		if err := w.queue.Hold(ctx, &job, err); err != nil {
+			w.warnUpdate(job, "held", err)
 		}
+		return true
+	}
+
+	if w.log != nil {
+		w.log.Warnf("Payment job failed (attempt %d/%d) for %s: %+v", job.Attempts, w.retryCount, job.ExpenseID, err)
+	}
+
+	if !IsRetryablePaymentError(err) {
+		if w.log != nil {
+			w.log.Warnf("Payment job for %s failed permanently, not retrying", job.ExpenseID)
+		}
+		w.deadLetter(ctx, job, err)
+		return true
+	}
+	if job.Attempts >= w.retryCount {
+		w.deadLetter(ctx, job, err)
+		return true
+	}
+
+	nextRunAt := time.Now().Add(RetryBackoff(w.retryDelay, w.maxRetryDelay, job.Attempts, w.random))
+	if err := w.queue.Retry(ctx, &job, nextRunAt, err); err != nil {
+		w.warnUpdate(job, "rescheduled", err)
+	}
+	return true
+}
+
+func (w *PaymentWorker) deadLetter(ctx context.Context, job entity.PaymentJob, cause error) {
+	if err := w.queue.Fail(ctx, &job, cause); err != nil {
+		w.warnUpdate(job, "failed", err)
+		if errors.Is(err, repository.ErrLeaseLost) {
+			return
+		}
+	}
+
+	if w.failFn == nil {
+		return
+	}
+	if err := w.failFn(ctx, converter.PaymentJobToModel(&job), errorText(cause)); err != nil && w.log != nil {
+		w.log.Warnf("Failed to mark expense %s payment failed: %+v", job.ExpenseID, err)
+	}
+}
+
+func (w *PaymentWorker) warnUpdate(job entity.PaymentJob, state string, err error) {
+	if w.log == nil {
+		return
+	}
+	if errors.Is(err, repository.ErrLeaseLost) {
+		w.log.Warnf("Payment job %s was taken over by another worker, not marking it %s", job.ID, state)
+		return
+	}
+	w.log.Warnf("Failed to mark payment job %s %s: %+v", job.ID, state, err)
+}
+
+func RetryBackoff(base, maxDelay time.Duration, attempt int, random func(int64) int64) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	delay := base
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + time.Duration(random(int64(delay-half)+1))
+}
+
+func IsRetryablePaymentError(err error) bool {
+	// provider responses are classified by the API error alone
+	var apiErr *payment.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Temporary()
+	}
 
-		if attempt < w.retryCount {
-			time.Sleep(w.retryDelay * time.Duration(attempt))
+	var httpErr utils.HTTPError
+	if errors.As(err, &httpErr) {
+		switch status := httpErr.Status(); status {
+		case http.StatusRequestTimeout, http.StatusConflict, http.StatusTooManyRequests:
+			// a conflict here is a concurrent change to the expense, the next attempt reloads it
+			return true
+		default:
+			return status >= http.StatusInternalServerError
 		}
 	}
+	return true
 }
@@ -0,0 +1,115 @@
+package background
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type PayoutRunFunc func(context.Context) error
+
+type PayoutSyncFunc func(context.Context) (int, error)
+
+type PayoutScheduler struct {
+	*lifecycle
+	log          *logrus.Logger
+	hour         int
+	minute       int
+	syncInterval time.Duration
+	timeout      time.Duration
+	runFn        PayoutRunFunc
+	syncFn       PayoutSyncFunc
+}
+
+func NewPayoutScheduler(runAt string, syncInterval, timeout time.Duration, log *logrus.Logger, runFn PayoutRunFunc, syncFn PayoutSyncFunc) (*PayoutScheduler, error) {
+	hour, minute, err := ParseDailyTime(runAt)
+	if err != nil {
+		return nil, err
+	}
+	if syncInterval <= 0 {
+		syncInterval = time.Minute
+	}
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+
+	return &PayoutScheduler{
+		lifecycle:    newLifecycle(),
+		log:          log,
+		hour:         hour,
+		minute:       minute,
+		syncInterval: syncInterval,
+		timeout:      timeout,
+		runFn:        runFn,
+		syncFn:       syncFn,
+	}, nil
+}
+
+func (s *PayoutScheduler) Start() {
+	s.run(func() {
+		next := NextDailyRun(time.Now(), s.hour, s.minute)
+		if s.log != nil {
+			s.log.Infof("Next payout run scheduled at %s", next.Format(time.RFC3339))
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		defer timer.Stop()
+		ticker := time.NewTicker(s.syncInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-timer.C:
+				s.RunOnce()
+				timer.Reset(time.Until(NextDailyRun(time.Now(), s.hour, s.minute)))
+			case <-ticker.C:
+				s.SyncOnce()
+			case <-s.stop:
+				return
+			}
+		}
+	})
+}
+
+func (s *PayoutScheduler) RunOnce() {
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+
+	if err := s.runFn(ctx); err != nil && s.log != nil {
+		s.log.Warnf("Scheduled payout run failed: %+v", err)
+	}
+}
+
+func (s *PayoutScheduler) SyncOnce() {
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+
+	finished, err := s.syncFn(ctx)
+	if err != nil {
+		if s.log != nil {
+			s.log.Warnf("Payout batch sync failed: %+v", err)
+		}
+		return
+	}
+	if finished > 0 && s.log != nil {
+		s.log.Infof("Payout batch sync finished %d batch(es)", finished)
+	}
+}
+
+func ParseDailyTime(value string) (int, int, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid daily time %q, use HH:MM", value)
+	}
+	return parsed.Hour(), parsed.Minute(), nil
+}
+
+func NextDailyRun(now time.Time, hour, minute int) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+	if !next.After(now) {
+		next = time.Date(now.Year(), now.Month(), now.Day()+1, hour, minute, 0, 0, now.Location())
+	}
+	return next
+}
@@ -0,0 +1,58 @@
+package background
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+type Workers struct {
+	PaymentWorker     *PaymentWorker
+	PayoutScheduler   *PayoutScheduler
+	PaymentReconciler *PaymentReconciler
+	ApprovalEscalator *ApprovalEscalator
+}
+
+func (w *Workers) Start() {
+	if w.PaymentWorker != nil {
+		w.PaymentWorker.Start()
+	}
+	if w.PayoutScheduler != nil {
+		w.PayoutScheduler.Start()
+	}
+	if w.PaymentReconciler != nil {
+		w.PaymentReconciler.Start()
+	}
+	if w.ApprovalEscalator != nil {
+		w.ApprovalEscalator.Start()
+	}
+}
+
+func (w *Workers) Stop(ctx context.Context) error {
+	stoppers := make([]func(context.Context) error, 0, 4)
+	if w.PaymentWorker != nil {
+		stoppers = append(stoppers, w.PaymentWorker.Stop)
+	}
+	if w.PayoutScheduler != nil {
+		stoppers = append(stoppers, w.PayoutScheduler.Stop)
+	}
+	if w.PaymentReconciler != nil {
+		stoppers = append(stoppers, w.PaymentReconciler.Stop)
+	}
+	if w.ApprovalEscalator != nil {
+		stoppers = append(stoppers, w.ApprovalEscalator.Stop)
+	}
+
+	// stop them side by side so one slow worker does not use up the others' deadline
+	errs := make([]error, len(stoppers))
+	var wg sync.WaitGroup
+	for i, stop := range stoppers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = stop(ctx)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
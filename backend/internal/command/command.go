@@ -1,7 +1,9 @@
 package command
 
 import (
+	"context"
 	"fmt"
+	"go-expense-management-system/internal/config"
 	"go-expense-management-system/internal/migrations"
 	"os"
 	"strings"
@@ -38,8 +40,14 @@ func (ce *CommandExecutor) Execute(logger *logrus.Logger) bool {
 			ce.handleMigrate(logger)
 		case "--seed":
 			ce.handleSeed(logger)
+		case "--reconcile-payments":
+			ce.handleReconcilePayments(logger)
 		case "--run":
 			run = true
+		default:
+			if email, ok := strings.CutPrefix(arg, "--verify-bank-account="); ok {
+				ce.handleVerifyBankAccount(logger, email)
+			}
 		}
 	}
 
@@ -60,6 +68,24 @@ func (ce *CommandExecutor) handleSeed(logger *logrus.Logger) {
 	logger.Println("Seeder completed")
 }
 
+func (ce *CommandExecutor) handleReconcilePayments(logger *logrus.Logger) {
+	reconciler := config.NewPaymentReconcileUseCase(ce.Viper, ce.DB, logger)
+	result, err := reconciler.Reconcile(context.Background())
+	if err != nil {
+		logger.Fatalf("Payment reconciliation failed: %v", err)
+	}
+	logger.Printf("Payment reconciliation completed: %d checked, %d completed, %d failed, %d pending, %d errors\n",
+		result.Checked, result.Completed, result.Failed, result.Pending, result.Errors)
+}
+
+func (ce *CommandExecutor) handleVerifyBankAccount(logger *logrus.Logger, email string) {
+	bankAccounts := config.NewBankAccountUseCase(ce.DB, logger)
+	if _, err := bankAccounts.Verify(context.Background(), email); err != nil {
+		logger.Fatalf("Bank account verification failed: %v", err)
+	}
+	logger.Printf("Bank account of %s verified\n", email)
+}
+
 func (ce *CommandExecutor) handleDropTable(logger *logrus.Logger) {
 	tables := ce.Viper.GetString("DROP_TABLE_NAMES")
 	if tables == "" {
@@ -2,11 +2,11 @@ package config
 
 import (
 	"go-expense-management-system/internal/background"
+	"go-expense-management-system/internal/constants"
 	"go-expense-management-system/internal/delivery/http"
 	"go-expense-management-system/internal/delivery/http/middleware"
 	"go-expense-management-system/internal/delivery/http/route"
 	"go-expense-management-system/internal/integration/email"
-	"go-expense-management-system/internal/integration/payment"
 	"go-expense-management-system/internal/repository"
 	"go-expense-management-system/internal/usecase"
 	"go-expense-management-system/internal/utils"
@@ -27,21 +27,67 @@ type BootstrapConfig struct {
 	Config   *viper.Viper
 }
 
-func Bootstrap(config *BootstrapConfig) {
+func Bootstrap(config *BootstrapConfig) *background.Workers {
 	// Setup repositories
 	userRepository := repository.NewUserRepository(config.Log)
 	expenseRepository := repository.NewExpenseRepository(config.Log)
 	approvalRepository := repository.NewApprovalRepository(config.Log)
 	historyRepository := repository.NewExpenseStatusHistoryRepository(config.Log)
+	categoryRepository := repository.NewExpenseCategoryRepository(config.Log)
+	receiptRepository := repository.NewExpenseReceiptRepository(config.Log)
+	paymentJobRepository := repository.NewPaymentJobRepository(config.Log)
+	idempotencyRepository := repository.NewIdempotencyKeyRepository(config.Log)
+	budgetRepository := repository.NewBudgetRepository(config.Log)
+	reportRepository := repository.NewReportRepository(config.Log)
+	paymentRepository := repository.NewPaymentRepository(config.Log)
+	webhookEventRepository := repository.NewPaymentWebhookEventRepository(config.Log)
+	bankAccountRepository := repository.NewUserBankAccountRepository(config.Log)
+	payoutBatchRepository := repository.NewPayoutBatchRepository(config.Log)
 
 	// Setup integrations
 	paymentCfg := buildPaymentConfig(config.Config)
-	paymentClient := payment.NewClient(paymentCfg.BaseURL, paymentCfg.Timeout, config.Log)
+	paymentProvider := buildPaymentProvider(paymentCfg, config.Log)
+	payoutCfg := buildPayoutConfig(config.Config, config.Log)
 
 	emailClient := email.NewClient(buildSMTPConfig(config.Config), config.Log)
 
+	storageCfg := buildStorageConfig(config.Config)
+	receiptStorage := buildReceiptStorage(storageCfg, config.Log)
+
 	// Setup use cases
 	userUseCase := usecase.NewUserUseCase(config.DB, config.Log, config.JWT, userRepository)
+	bankAccountUseCase := usecase.NewBankAccountUseCase(config.DB, config.Log, bankAccountRepository, userRepository, paymentJobRepository)
+	budgetTracker := usecase.NewBudgetTracker(config.Log, budgetRepository, userRepository)
+	budgetUseCase := usecase.NewBudgetUseCase(config.DB, config.Log, budgetTracker)
+	reportUseCase := usecase.NewReportUseCase(config.DB, config.Log, reportRepository)
+	paymentWebhookUseCase := usecase.NewPaymentWebhookUseCase(
+		config.DB,
+		config.Log,
+		expenseRepository,
+		historyRepository,
+		paymentRepository,
+		webhookEventRepository,
+		paymentCfg.WebhookSecret,
+		paymentCfg.WebhookTolerance,
+	)
+	payoutUseCase := usecase.NewPayoutUseCase(
+		config.DB,
+		config.Log,
+		expenseRepository,
+		historyRepository,
+		paymentRepository,
+		bankAccountRepository,
+		payoutBatchRepository,
+		paymentProvider,
+		payoutCfg.Mode == constants.PayoutModeBatch,
+		payoutCfg.MaxItems,
+		payoutCfg.Timeout,
+	)
+	categoryUseCase := usecase.NewExpenseCategoryUseCase(config.DB, config.Log, categoryRepository, expenseRepository)
+	duplicatePolicy := buildDuplicateExpensePolicy(config.Config)
+	idempotencyCfg := buildIdempotencyConfig(config.Config)
+	idempotencyUseCase := usecase.NewIdempotencyUseCase(config.DB, config.Log, idempotencyRepository, idempotencyCfg.TTL, idempotencyCfg.LockTimeout)
+	receiptUseCase := usecase.NewExpenseReceiptUseCase(config.DB, config.Log, expenseRepository, receiptRepository, receiptStorage, storageCfg.MaxReceiptBytes, duplicatePolicy)
 	expenseUseCase := usecase.NewExpenseUseCase(
 		config.DB,
 		config.Log,
@@ -49,36 +95,86 @@ func Bootstrap(config *BootstrapConfig) {
 		approvalRepository,
 		historyRepository,
 		userRepository,
+		categoryRepository,
+		receiptRepository,
+		paymentRepository,
+		bankAccountRepository,
+		budgetTracker,
 		emailClient,
 		nil,
-		paymentClient,
+		paymentProvider,
+		buildApprovalPolicy(config.Config, config.Log),
+		buildSplitExpensePolicy(config.Config),
+		duplicatePolicy,
 	)
 
 	// Setup controllers
 	userController := http.NewUserController(userUseCase, config.Log, config.Validate)
+	bankAccountController := http.NewBankAccountController(bankAccountUseCase, config.Log, config.Validate)
 	expenseController := http.NewExpenseController(expenseUseCase, config.Log, config.Validate)
+	categoryController := http.NewExpenseCategoryController(categoryUseCase, config.Log, config.Validate)
+	receiptController := http.NewExpenseReceiptController(receiptUseCase, config.Log)
+	budgetController := http.NewBudgetController(budgetUseCase, config.Log)
+	reportController := http.NewReportController(reportUseCase, config.Log)
+	paymentWebhookController := http.NewPaymentWebhookController(paymentWebhookUseCase, config.Log)
+	payoutController := http.NewPayoutController(payoutUseCase, config.Log)
 
 	// Setup middleware
 	authMiddleware := middleware.NewAuth(userUseCase)
+	idempotencyMiddleware := middleware.NewIdempotency(idempotencyUseCase)
 
 	// Setup background workers
+	paymentQueue := background.NewPaymentJobQueue(config.DB, config.Log, paymentJobRepository, paymentCfg.QueueLease)
 	paymentWorker := background.NewPaymentWorker(
-		paymentCfg.QueueBuffer,
+		paymentQueue,
+		paymentCfg.WorkerCount,
+		paymentCfg.QueueBatchSize,
 		paymentCfg.RetryCount,
 		paymentCfg.RetryDelay,
+		paymentCfg.RetryMaxDelay,
 		paymentCfg.Timeout,
+		paymentCfg.QueuePoll,
 		config.Log,
 		expenseUseCase.ProcessPayment,
+		expenseUseCase.MarkPaymentFailed,
 	)
-	paymentWorker.Start()
-	expenseUseCase.PaymentQueue = paymentWorker
+	workers := &background.Workers{}
+	if payoutCfg.Mode == constants.PayoutModeBatch {
+		payoutScheduler, err := background.NewPayoutScheduler(payoutCfg.RunAt, payoutCfg.SyncInterval, payoutCfg.Timeout, config.Log, payoutUseCase.CreateScheduled, payoutUseCase.Sync)
+		if err != nil {
+			config.Log.Fatalf("Invalid PAYOUT_BATCH_TIME: %+v", err)
+		}
+		workers.PayoutScheduler = payoutScheduler
+	} else {
+		workers.PaymentWorker = paymentWorker
+		expenseUseCase.PaymentQueue = paymentWorker
+	}
+
+	paymentReconcileUseCase := NewPaymentReconcileUseCase(config.Config, config.DB, config.Log)
+	workers.PaymentReconciler = background.NewPaymentReconciler(paymentCfg.ReconcileInterval, config.Log, paymentReconcileUseCase.Reconcile)
+
+	escalationCfg := buildApprovalEscalationConfig(config.Config)
+	expenseUseCase.EscalationAfter = escalationCfg.After
+	if escalationCfg.After > 0 {
+		workers.ApprovalEscalator = background.NewApprovalEscalator(escalationCfg.Interval, escalationCfg.BatchSize, config.Log, expenseUseCase.EscalateApprovals)
+	}
 
 	// Setup routes
 	routeConfig := route.RouteConfig{
-		Router:            config.Router,
-		UserController:    userController,
-		ExpenseController: expenseController,
-		AuthMiddleware:    authMiddleware,
+		Router:                   config.Router,
+		UserController:           userController,
+		BankAccountController:    bankAccountController,
+		ExpenseController:        expenseController,
+		CategoryController:       categoryController,
+		ReceiptController:        receiptController,
+		BudgetController:         budgetController,
+		ReportController:         reportController,
+		PaymentWebhookController: paymentWebhookController,
+		PayoutController:         payoutController,
+		AuthMiddleware:           authMiddleware,
+		IdempotencyMiddleware:    idempotencyMiddleware,
 	}
 	routeConfig.Setup()
+
+	return workers
 }
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"go-expense-management-system/internal/usecase"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+func buildApprovalPolicy(config *viper.Viper, log *logrus.Logger) *usecase.ApprovalPolicy {
+	tiers, err := usecase.ParseApprovalTiers(config.GetString("APPROVAL_TIERS"))
+	if err != nil {
+		log.Warnf("Invalid APPROVAL_TIERS, falling back to default tiers: %v", err)
+		tiers = nil
+	}
+	policy := usecase.NewApprovalPolicy(tiers)
+
+	openRoles, err := usecase.ParseApprovalRoles(config.GetString("APPROVAL_OPEN_ROLES"))
+	if err != nil {
+		log.Fatalf("Invalid APPROVAL_OPEN_ROLES: %v", err)
+	}
+	policy.OpenRoles = openRoles
+	return policy
+}
+
+type approvalEscalationConfig struct {
+	After     time.Duration
+	Interval  time.Duration
+	BatchSize int
+}
+
+func buildApprovalEscalationConfig(config *viper.Viper) approvalEscalationConfig {
+	return approvalEscalationConfig{
+		After:     time.Duration(config.GetInt("APPROVAL_ESCALATION_HOURS")) * time.Hour,
+		Interval:  time.Duration(config.GetInt("APPROVAL_ESCALATION_INTERVAL_MINUTES")) * time.Minute,
+		BatchSize: config.GetInt("APPROVAL_ESCALATION_BATCH_SIZE"),
+	}
+}
+
+func buildSplitExpensePolicy(config *viper.Viper) *usecase.SplitExpensePolicy {
+	return &usecase.SplitExpensePolicy{
+		Window:           time.Duration(config.GetInt("SPLIT_EXPENSE_WINDOW_HOURS")) * time.Hour,
+		SameCategory:     config.GetBool("SPLIT_EXPENSE_SAME_CATEGORY"),
+		MatchDescription: config.GetBool("SPLIT_EXPENSE_MATCH_DESCRIPTION"),
+		MinSimilarity:    config.GetFloat64("SPLIT_EXPENSE_MIN_SIMILARITY"),
+	}
+}
+
+func buildDuplicateExpensePolicy(config *viper.Viper) *usecase.DuplicateExpensePolicy {
+	return &usecase.DuplicateExpensePolicy{
+		Window:        time.Duration(config.GetInt("DUPLICATE_EXPENSE_WINDOW_DAYS")) * 24 * time.Hour,
+		MinSimilarity: config.GetFloat64("DUPLICATE_EXPENSE_MIN_SIMILARITY"),
+	}
+}
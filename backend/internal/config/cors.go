@@ -19,8 +19,8 @@ func NewCORS(v *viper.Viper) gin.HandlerFunc {
 	return cors.New(cors.Config{
 		AllowOrigins:     allowOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS", "HEAD"},
-		AllowHeaders:     []string{"Origin", "Authorization", "Content-Type", "Accept", "X-Requested-With", "Access-Control-Request-Method", "Access-Control-Request-Headers", "X-CSRF-Token", "X-Request-ID"},
-		ExposeHeaders:    []string{"Content-Length", "Content-Type", "X-Requested-With", "X-CSRF-Token", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Authorization", "Content-Type", "Accept", "X-Requested-With", "Access-Control-Request-Method", "Access-Control-Request-Headers", "X-CSRF-Token", "X-Request-ID", "If-Match", "Idempotency-Key"},
+		ExposeHeaders:    []string{"Content-Length", "Content-Type", "X-Requested-With", "X-CSRF-Token", "Authorization", "ETag", "Idempotent-Replayed"},
 		AllowCredentials: allowCredentials,
 		MaxAge:           24 * time.Hour,
 	})
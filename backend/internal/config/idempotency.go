@@ -0,0 +1,19 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type idempotencyConfig struct {
+	TTL         time.Duration
+	LockTimeout time.Duration
+}
+
+func buildIdempotencyConfig(config *viper.Viper) idempotencyConfig {
+	return idempotencyConfig{
+		TTL:         time.Duration(config.GetInt("IDEMPOTENCY_TTL_HOURS")) * time.Hour,
+		LockTimeout: time.Duration(config.GetInt("IDEMPOTENCY_LOCK_SECONDS")) * time.Second,
+	}
+}
@@ -1,25 +1,110 @@
 package config
 
 import (
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/integration/payment"
+	"go-expense-management-system/internal/repository"
+	"go-expense-management-system/internal/usecase"
+	"strings"
 	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
+	"gorm.io/gorm"
 )
 
 type paymentConfig struct {
-	BaseURL     string
-	Timeout     time.Duration
-	RetryCount  int
-	RetryDelay  time.Duration
-	QueueBuffer int
+	Provider            string
+	BaseURL             string
+	APIKey              string
+	FakeStatus          string
+	Timeout             time.Duration
+	WorkerCount         int
+	RetryCount          int
+	RetryDelay          time.Duration
+	RetryMaxDelay       time.Duration
+	QueueBatchSize      int
+	QueuePoll           time.Duration
+	QueueLease          time.Duration
+	WebhookSecret       string
+	WebhookTolerance    time.Duration
+	ReconcileInterval   time.Duration
+	ReconcileStaleAfter time.Duration
+	ReconcileBatchSize  int
 }
 
 func buildPaymentConfig(config *viper.Viper) paymentConfig {
 	return paymentConfig{
-		BaseURL:     config.GetString("PAYMENT_BASE_URL"),
-		Timeout:     time.Duration(config.GetInt("PAYMENT_TIMEOUT_SECONDS")) * time.Second,
-		RetryCount:  config.GetInt("PAYMENT_RETRY_COUNT"),
-		RetryDelay:  time.Duration(config.GetInt("PAYMENT_RETRY_DELAY_SECONDS")) * time.Second,
-		QueueBuffer: config.GetInt("PAYMENT_QUEUE_BUFFER"),
+		Provider:            strings.ToLower(strings.TrimSpace(config.GetString("PAYMENT_PROVIDER"))),
+		BaseURL:             config.GetString("PAYMENT_BASE_URL"),
+		APIKey:              config.GetString("PAYMENT_API_KEY"),
+		FakeStatus:          strings.ToLower(strings.TrimSpace(config.GetString("PAYMENT_FAKE_STATUS"))),
+		Timeout:             time.Duration(config.GetInt("PAYMENT_TIMEOUT_SECONDS")) * time.Second,
+		WorkerCount:         config.GetInt("PAYMENT_WORKER_COUNT"),
+		RetryCount:          config.GetInt("PAYMENT_RETRY_COUNT"),
+		RetryDelay:          time.Duration(config.GetInt("PAYMENT_RETRY_DELAY_SECONDS")) * time.Second,
+		RetryMaxDelay:       time.Duration(config.GetInt("PAYMENT_RETRY_MAX_DELAY_SECONDS")) * time.Second,
+		QueueBatchSize:      config.GetInt("PAYMENT_QUEUE_BATCH_SIZE"),
+		QueuePoll:           time.Duration(config.GetInt("PAYMENT_QUEUE_POLL_INTERVAL_SECONDS")) * time.Second,
+		QueueLease:          time.Duration(config.GetInt("PAYMENT_QUEUE_LEASE_SECONDS")) * time.Second,
+		WebhookSecret:       config.GetString("PAYMENT_WEBHOOK_SECRET"),
+		WebhookTolerance:    time.Duration(config.GetInt("PAYMENT_WEBHOOK_TOLERANCE_SECONDS")) * time.Second,
+		ReconcileInterval:   time.Duration(config.GetInt("PAYMENT_RECONCILE_INTERVAL_SECONDS")) * time.Second,
+		ReconcileStaleAfter: time.Duration(config.GetInt("PAYMENT_RECONCILE_STALE_MINUTES")) * time.Minute,
+		ReconcileBatchSize:  config.GetInt("PAYMENT_RECONCILE_BATCH_SIZE"),
 	}
 }
+
+func NewPaymentReconcileUseCase(config *viper.Viper, db *gorm.DB, log *logrus.Logger) *usecase.PaymentReconcileUseCase {
+	paymentCfg := buildPaymentConfig(config)
+	return usecase.NewPaymentReconcileUseCase(
+		db,
+		log,
+		repository.NewExpenseRepository(log),
+		repository.NewExpenseStatusHistoryRepository(log),
+		repository.NewPaymentRepository(log),
+		buildPaymentProvider(paymentCfg, log),
+		paymentCfg.ReconcileStaleAfter,
+		paymentCfg.ReconcileBatchSize,
+	)
+}
+
+func NewBankAccountUseCase(db *gorm.DB, log *logrus.Logger) *usecase.BankAccountUseCase {
+	return usecase.NewBankAccountUseCase(
+		db,
+		log,
+		repository.NewUserBankAccountRepository(log),
+		repository.NewUserRepository(log),
+		repository.NewPaymentJobRepository(log),
+	)
+}
+
+type paymentProviderFactory func(config paymentConfig, log *logrus.Logger) usecase.PaymentProvider
+
+var paymentProviders = map[string]paymentProviderFactory{
+	constants.PaymentProviderHTTP: func(config paymentConfig, log *logrus.Logger) usecase.PaymentProvider {
+		client := payment.NewClient(config.BaseURL, config.Timeout, log)
+		client.APIKey = config.APIKey
+		return client
+	},
+	constants.PaymentProviderFake: func(config paymentConfig, _ *logrus.Logger) usecase.PaymentProvider {
+		return payment.NewFakeProvider(config.FakeStatus)
+	},
+	constants.PaymentProviderManual: func(_ paymentConfig, log *logrus.Logger) usecase.PaymentProvider {
+		return payment.NewManualProvider(log)
+	},
+}
+
+func buildPaymentProvider(config paymentConfig, log *logrus.Logger) usecase.PaymentProvider {
+	name := config.Provider
+	if name == "" {
+		name = constants.PaymentProviderHTTP
+	}
+
+	factory, ok := paymentProviders[name]
+	if !ok {
+		log.Fatalf("Unsupported PAYMENT_PROVIDER %q", config.Provider)
+		return nil
+	}
+	return factory(config, log)
+}
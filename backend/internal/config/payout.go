@@ -0,0 +1,36 @@
+package config
+
+import (
+	"go-expense-management-system/internal/constants"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type payoutConfig struct {
+	Mode         string
+	RunAt        string
+	MaxItems     int
+	Timeout      time.Duration
+	SyncInterval time.Duration
+}
+
+func buildPayoutConfig(config *viper.Viper, log *logrus.Logger) payoutConfig {
+	mode := strings.ToLower(strings.TrimSpace(config.GetString("PAYOUT_MODE")))
+	if mode == "" {
+		mode = constants.PayoutModeImmediate
+	}
+	if mode != constants.PayoutModeImmediate && mode != constants.PayoutModeBatch {
+		log.Fatalf("Unsupported PAYOUT_MODE %q", mode)
+	}
+
+	return payoutConfig{
+		Mode:         mode,
+		RunAt:        strings.TrimSpace(config.GetString("PAYOUT_BATCH_TIME")),
+		MaxItems:     config.GetInt("PAYOUT_BATCH_MAX_ITEMS"),
+		Timeout:      time.Duration(config.GetInt("PAYOUT_TIMEOUT_SECONDS")) * time.Second,
+		SyncInterval: time.Duration(config.GetInt("PAYOUT_SYNC_INTERVAL_SECONDS")) * time.Second,
+	}
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"go-expense-management-system/internal/integration/storage"
+	"go-expense-management-system/internal/usecase"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type storageConfig struct {
+	Driver          string
+	LocalDir        string
+	MaxReceiptBytes int64
+}
+
+func buildStorageConfig(config *viper.Viper) storageConfig {
+	return storageConfig{
+		Driver:          strings.ToLower(strings.TrimSpace(config.GetString("STORAGE_DRIVER"))),
+		LocalDir:        config.GetString("STORAGE_LOCAL_DIR"),
+		MaxReceiptBytes: config.GetInt64("RECEIPT_MAX_SIZE_MB") << 20,
+	}
+}
+
+func buildReceiptStorage(config storageConfig, log *logrus.Logger) usecase.ReceiptStorage {
+	switch config.Driver {
+	case "", "local":
+		localStorage, err := storage.NewLocalStorage(config.LocalDir, log)
+		if err != nil {
+			log.Fatalf("Failed to initialize local receipt storage: %v", err)
+		}
+		return localStorage
+	default:
+		log.Fatalf("Unsupported STORAGE_DRIVER %q", config.Driver)
+		return nil
+	}
+}
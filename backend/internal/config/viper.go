@@ -12,6 +12,7 @@ func NewViper() *viper.Viper {
 
 	config.SetDefault("APP_NAME", "go-app")
 	config.SetDefault("PORT", 8080)
+	config.SetDefault("SHUTDOWN_TIMEOUT_SECONDS", 30)
 	config.SetDefault("LOG_LEVEL", "info")
 	config.SetDefault("DB_HOST", "localhost")
 	config.SetDefault("DB_PORT", 5432)
@@ -22,12 +23,45 @@ func NewViper() *viper.Viper {
 	config.SetDefault("JWT_ISSUER", "go-issuer")
 	config.SetDefault("JWT_AUDIENCE", "go-audience")
 	config.SetDefault("JWT_EXPIRES_MINUTES", 1440)
-	config.SetDefault("DROP_TABLE_NAMES", "users,expenses,approvals,expense_status_histories")
+	config.SetDefault("DROP_TABLE_NAMES", "departments,users,budgets,expense_categories,expenses,expense_receipts,approvals,expense_status_histories,user_bank_accounts,payment_jobs,payments,payment_webhook_events,payout_batches,payout_batch_items,idempotency_keys")
+	config.SetDefault("PAYMENT_PROVIDER", "http")
 	config.SetDefault("PAYMENT_BASE_URL", "https://1620e98f-7759-431c-a2aa-f449d591150b.mock.pstmn.io")
+	config.SetDefault("PAYMENT_API_KEY", "")
+	config.SetDefault("PAYMENT_FAKE_STATUS", "completed")
 	config.SetDefault("PAYMENT_TIMEOUT_SECONDS", 10)
+	config.SetDefault("PAYMENT_WORKER_COUNT", 4)
 	config.SetDefault("PAYMENT_RETRY_COUNT", 3)
 	config.SetDefault("PAYMENT_RETRY_DELAY_SECONDS", 2)
-	config.SetDefault("PAYMENT_QUEUE_BUFFER", 100)
+	config.SetDefault("PAYMENT_RETRY_MAX_DELAY_SECONDS", 300)
+	config.SetDefault("PAYMENT_QUEUE_BATCH_SIZE", 10)
+	config.SetDefault("PAYMENT_QUEUE_POLL_INTERVAL_SECONDS", 5)
+	config.SetDefault("PAYMENT_QUEUE_LEASE_SECONDS", 60)
+	config.SetDefault("PAYMENT_WEBHOOK_SECRET", "")
+	config.SetDefault("PAYMENT_WEBHOOK_TOLERANCE_SECONDS", 300)
+	config.SetDefault("PAYMENT_RECONCILE_INTERVAL_SECONDS", 300)
+	config.SetDefault("PAYMENT_RECONCILE_STALE_MINUTES", 15)
+	config.SetDefault("PAYMENT_RECONCILE_BATCH_SIZE", 50)
+	config.SetDefault("PAYOUT_MODE", "immediate")
+	config.SetDefault("PAYOUT_BATCH_TIME", "15:00")
+	config.SetDefault("PAYOUT_BATCH_MAX_ITEMS", 500)
+	config.SetDefault("PAYOUT_TIMEOUT_SECONDS", 60)
+	config.SetDefault("PAYOUT_SYNC_INTERVAL_SECONDS", 60)
+	config.SetDefault("APPROVAL_TIERS", "")
+	config.SetDefault("APPROVAL_OPEN_ROLES", "finance")
+	config.SetDefault("APPROVAL_ESCALATION_HOURS", 48)
+	config.SetDefault("APPROVAL_ESCALATION_INTERVAL_MINUTES", 15)
+	config.SetDefault("APPROVAL_ESCALATION_BATCH_SIZE", 50)
+	config.SetDefault("SPLIT_EXPENSE_WINDOW_HOURS", 72)
+	config.SetDefault("SPLIT_EXPENSE_SAME_CATEGORY", false)
+	config.SetDefault("SPLIT_EXPENSE_MATCH_DESCRIPTION", false)
+	config.SetDefault("SPLIT_EXPENSE_MIN_SIMILARITY", 0.6)
+	config.SetDefault("DUPLICATE_EXPENSE_WINDOW_DAYS", 30)
+	config.SetDefault("DUPLICATE_EXPENSE_MIN_SIMILARITY", 0.8)
+	config.SetDefault("STORAGE_DRIVER", "local")
+	config.SetDefault("STORAGE_LOCAL_DIR", "./storage")
+	config.SetDefault("RECEIPT_MAX_SIZE_MB", 5)
+	config.SetDefault("IDEMPOTENCY_TTL_HOURS", 24)
+	config.SetDefault("IDEMPOTENCY_LOCK_SECONDS", 60)
 	config.SetDefault("CORS_ALLOW_ORIGINS", "*")
 	config.SetDefault("CORS_ALLOW_CREDENTIALS", false)
 	config.SetDefault("RATE_LIMIT", "100-M")
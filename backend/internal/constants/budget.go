@@ -0,0 +1,16 @@
+package constants
+
+const (
+	BudgetPeriodMonthly   = "monthly"
+	BudgetPeriodQuarterly = "quarterly"
+)
+
+const (
+	BudgetScopeUser       = "user"
+	BudgetScopeDepartment = "department"
+)
+
+const (
+	BudgetEnforcementBlock           = "block"
+	BudgetEnforcementRequireApproval = "require_approval"
+)
@@ -1,20 +1,36 @@
 package constants
 
 const (
-	MinExpenseAmount  int64 = 10000
-	MaxExpenseAmount  int64 = 50000000
-	ApprovalThreshold int64 = 1000000
+	ApprovalThreshold         int64 = 1000000
+	FinanceApprovalThreshold  int64 = 5000000
+	DirectorApprovalThreshold int64 = 20000000
 )
 
 const (
-	ExpenseStatusAwaitingApproval = "awaiting_approval"
-	ExpenseStatusApproved         = "approved"
-	ExpenseStatusRejected         = "rejected"
-	ExpenseStatusAutoApproved     = "auto_approved"
-	ExpenseStatusCompleted        = "completed"
+	ExpenseStatusAwaitingApproval  = "awaiting_approval"
+	ExpenseStatusApproved          = "approved"
+	ExpenseStatusRejected          = "rejected"
+	ExpenseStatusAutoApproved      = "auto_approved"
+	ExpenseStatusCompleted         = "completed"
+	ExpenseStatusPaymentProcessing = "payment_processing"
+	ExpenseStatusPaymentFailed     = "payment_failed"
+	ExpenseStatusCancelled         = "cancelled"
 )
 
+var ExpenseStatuses = []string{
+	ExpenseStatusAwaitingApproval,
+	ExpenseStatusAutoApproved,
+	ExpenseStatusApproved,
+	ExpenseStatusRejected,
+	ExpenseStatusCancelled,
+	ExpenseStatusPaymentProcessing,
+	ExpenseStatusPaymentFailed,
+	ExpenseStatusCompleted,
+}
+
 const (
+	ApprovalStatusPending  = "pending"
 	ApprovalStatusApproved = "approved"
 	ApprovalStatusRejected = "rejected"
+	ApprovalStatusSkipped  = "skipped"
 )
@@ -0,0 +1,6 @@
+package constants
+
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+)
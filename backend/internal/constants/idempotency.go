@@ -0,0 +1,13 @@
+package constants
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+	IdempotencyKeyMaxLength   = 255
+	IdempotencyMaxBodyBytes   = 1 << 20
+)
+
+const (
+	IdempotencyStatusProcessing = "processing"
+	IdempotencyStatusCompleted  = "completed"
+)
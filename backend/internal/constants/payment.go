@@ -0,0 +1,30 @@
+package constants
+
+const (
+	PaymentJobStatusPending    = "pending"
+	PaymentJobStatusProcessing = "processing"
+	PaymentJobStatusCompleted  = "completed"
+	PaymentJobStatusFailed     = "failed"
+	PaymentJobStatusHeld       = "held"
+)
+
+const (
+	PaymentProviderStatusPending    = "pending"
+	PaymentProviderStatusProcessing = "processing"
+	PaymentProviderStatusCompleted  = "completed"
+	PaymentProviderStatusFailed     = "failed"
+
+	PaymentProviderStatusAwaitingManualTransfer = "awaiting_manual_transfer"
+)
+
+const (
+	PaymentProviderHTTP   = "http"
+	PaymentProviderFake   = "fake"
+	PaymentProviderManual = "manual"
+)
+
+const (
+	PaymentWebhookSignatureHeader = "X-Payment-Signature"
+	PaymentWebhookTimestampHeader = "X-Payment-Timestamp"
+	PaymentWebhookMaxBodyBytes    = 1 << 20
+)
@@ -0,0 +1,22 @@
+package constants
+
+const (
+	PayoutModeImmediate = "immediate"
+	PayoutModeBatch     = "batch"
+)
+
+const (
+	PayoutBatchStatusDraft           = "draft"
+	PayoutBatchStatusProcessing      = "processing"
+	PayoutBatchStatusCompleted       = "completed"
+	PayoutBatchStatusPartiallyFailed = "partially_failed"
+	PayoutBatchStatusFailed          = "failed"
+	PayoutBatchStatusCancelled       = "cancelled"
+)
+
+const (
+	PayoutItemStatusPending   = "pending"
+	PayoutItemStatusCompleted = "completed"
+	PayoutItemStatusFailed    = "failed"
+	PayoutItemStatusSkipped   = "skipped"
+)
@@ -3,4 +3,6 @@ package constants
 const (
 	RoleEmployee = "employee"
 	RoleManager  = "manager"
+	RoleFinance  = "finance"
+	RoleDirector = "director"
 )
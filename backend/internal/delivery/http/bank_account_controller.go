@@ -0,0 +1,75 @@
+package http
+
+import (
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/usecase"
+	"go-expense-management-system/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type BankAccountController struct {
+	Log      *logrus.Logger
+	UseCase  *usecase.BankAccountUseCase
+	Validate *validator.Validate
+}
+
+func NewBankAccountController(useCase *usecase.BankAccountUseCase, logger *logrus.Logger, validate *validator.Validate) *BankAccountController {
+	return &BankAccountController{
+		Log:      logger,
+		UseCase:  useCase,
+		Validate: validate,
+	}
+}
+
+func (c *BankAccountController) Me(ctx *gin.Context) {
+	auth, ok := getAuthOrAbort(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := c.UseCase.Me(ctx.Request.Context(), auth)
+	if err != nil {
+		c.Log.Warnf("Failed to get bank account: %+v", err)
+		utils.HandleHTTPError(ctx, err)
+		return
+	}
+
+	res := utils.SuccessResponse(messages.BankAccountFetched, response)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *BankAccountController) Update(ctx *gin.Context) {
+	auth, ok := getAuthOrAbort(ctx)
+	if !ok {
+		return
+	}
+
+	request := new(model.UpdateBankAccountRequest)
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		c.Log.Warnf("Failed to parse request body: %+v", err)
+		utils.HandleHTTPError(ctx, utils.Error(messages.FailedDataFromBody, http.StatusBadRequest, err))
+		return
+	}
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Validation failed: %+v", err)
+		message := utils.TranslateValidationError(c.Validate, err)
+		utils.HandleHTTPError(ctx, utils.Error(message, http.StatusBadRequest, err))
+		return
+	}
+
+	response, err := c.UseCase.Update(ctx.Request.Context(), auth, request)
+	if err != nil {
+		c.Log.Warnf("Failed to update bank account: %+v", err)
+		utils.HandleHTTPError(ctx, err)
+		return
+	}
+
+	res := utils.SuccessResponse(messages.BankAccountUpdated, response)
+	ctx.JSON(http.StatusOK, res)
+}
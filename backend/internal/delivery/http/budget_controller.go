@@ -0,0 +1,40 @@
+package http
+
+import (
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/usecase"
+	"go-expense-management-system/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type BudgetController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.BudgetUseCase
+}
+
+func NewBudgetController(useCase *usecase.BudgetUseCase, logger *logrus.Logger) *BudgetController {
+	return &BudgetController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *BudgetController) Me(ctx *gin.Context) {
+	auth, ok := getAuthOrAbort(ctx)
+	if !ok {
+		return
+	}
+
+	responses, err := c.UseCase.Me(ctx.Request.Context(), auth)
+	if err != nil {
+		c.Log.Warnf("Failed to get budgets: %+v", err)
+		utils.HandleHTTPError(ctx, err)
+		return
+	}
+
+	res := utils.SuccessResponse(messages.BudgetsFetched, responses)
+	ctx.JSON(http.StatusOK, res)
+}
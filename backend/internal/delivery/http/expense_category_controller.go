@@ -0,0 +1,159 @@
+package http
+
+import (
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/usecase"
+	"go-expense-management-system/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type ExpenseCategoryController struct {
+	Log      *logrus.Logger
+	UseCase  *usecase.ExpenseCategoryUseCase
+	Validate *validator.Validate
+}
+
+func NewExpenseCategoryController(useCase *usecase.ExpenseCategoryUseCase, logger *logrus.Logger, validate *validator.Validate) *ExpenseCategoryController {
+	return &ExpenseCategoryController{
+		Log:      logger,
+		UseCase:  useCase,
+		Validate: validate,
+	}
+}
+
+func (c *ExpenseCategoryController) List(ctx *gin.Context) {
+	auth, ok := getAuthOrAbort(ctx)
+	if !ok {
+		return
+	}
+
+	responses, err := c.UseCase.List(ctx.Request.Context(), auth)
+	if err != nil {
+		c.Log.Warnf("Failed to list expense categories: %+v", err)
+		utils.HandleHTTPError(ctx, err)
+		return
+	}
+
+	res := utils.SuccessResponse(messages.ExpenseCategoryListed, responses)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *ExpenseCategoryController) Get(ctx *gin.Context) {
+	auth, ok := getAuthOrAbort(ctx)
+	if !ok {
+		return
+	}
+
+	categoryID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.HandleHTTPError(ctx, utils.Error(messages.ErrInvalidIDFormat, http.StatusBadRequest, err))
+		return
+	}
+
+	response, err := c.UseCase.Get(ctx.Request.Context(), auth, categoryID)
+	if err != nil {
+		c.Log.Warnf("Failed to fetch expense category: %+v", err)
+		utils.HandleHTTPError(ctx, err)
+		return
+	}
+
+	res := utils.SuccessResponse(messages.ExpenseCategoryFetched, response)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *ExpenseCategoryController) Create(ctx *gin.Context) {
+	auth, ok := getAuthOrAbort(ctx)
+	if !ok {
+		return
+	}
+
+	request := new(model.CreateExpenseCategoryRequest)
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		c.Log.Warnf("Failed to parse request body: %+v", err)
+		utils.HandleHTTPError(ctx, utils.Error(messages.FailedDataFromBody, http.StatusBadRequest, err))
+		return
+	}
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Validation failed: %+v", err)
+		message := utils.TranslateValidationError(c.Validate, err)
+		utils.HandleHTTPError(ctx, utils.Error(message, http.StatusBadRequest, err))
+		return
+	}
+
+	response, err := c.UseCase.Create(ctx.Request.Context(), auth, request)
+	if err != nil {
+		c.Log.Warnf("Failed to create expense category: %+v", err)
+		utils.HandleHTTPError(ctx, err)
+		return
+	}
+
+	res := utils.SuccessResponse(messages.ExpenseCategoryCreated, response)
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func (c *ExpenseCategoryController) Update(ctx *gin.Context) {
+	auth, ok := getAuthOrAbort(ctx)
+	if !ok {
+		return
+	}
+
+	categoryID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.HandleHTTPError(ctx, utils.Error(messages.ErrInvalidIDFormat, http.StatusBadRequest, err))
+		return
+	}
+
+	request := new(model.UpdateExpenseCategoryRequest)
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		c.Log.Warnf("Failed to parse request body: %+v", err)
+		utils.HandleHTTPError(ctx, utils.Error(messages.FailedDataFromBody, http.StatusBadRequest, err))
+		return
+	}
+	request.ID = categoryID
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Validation failed: %+v", err)
+		message := utils.TranslateValidationError(c.Validate, err)
+		utils.HandleHTTPError(ctx, utils.Error(message, http.StatusBadRequest, err))
+		return
+	}
+
+	response, err := c.UseCase.Update(ctx.Request.Context(), auth, request)
+	if err != nil {
+		c.Log.Warnf("Failed to update expense category: %+v", err)
+		utils.HandleHTTPError(ctx, err)
+		return
+	}
+
+	res := utils.SuccessResponse(messages.ExpenseCategoryUpdated, response)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *ExpenseCategoryController) Delete(ctx *gin.Context) {
+	auth, ok := getAuthOrAbort(ctx)
+	if !ok {
+		return
+	}
+
+	categoryID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.HandleHTTPError(ctx, utils.Error(messages.ErrInvalidIDFormat, http.StatusBadRequest, err))
+		return
+	}
+
+	if err := c.UseCase.Delete(ctx.Request.Context(), auth, categoryID); err != nil {
+		c.Log.Warnf("Failed to delete expense category: %+v", err)
+		utils.HandleHTTPError(ctx, err)
+		return
+	}
+
+	res := utils.SuccessResponse[any](messages.ExpenseCategoryDeleted, nil)
+	ctx.JSON(http.StatusOK, res)
+}
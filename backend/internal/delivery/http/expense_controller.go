@@ -2,14 +2,19 @@ package http
 
 import (
 	"errors"
+	"fmt"
+	"go-expense-management-system/internal/constants"
 	"go-expense-management-system/internal/delivery/http/middleware"
 	"go-expense-management-system/internal/messages"
 	"go-expense-management-system/internal/model"
 	"go-expense-management-system/internal/usecase"
 	"go-expense-management-system/internal/utils"
 	"io"
+	"mime"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -58,6 +63,7 @@ func (c *ExpenseController) Create(ctx *gin.Context) {
 		return
 	}
 
+	setETag(ctx, response.Version)
 	res := utils.SuccessResponse(messages.ExpenseCreated, response)
 	ctx.JSON(http.StatusCreated, res)
 }
@@ -68,11 +74,14 @@ func (c *ExpenseController) List(ctx *gin.Context) {
 		return
 	}
 
-	status := ctx.Query("status")
-	page := parseIntQuery(ctx.Query("page"), 1)
-	size := parseIntQuery(ctx.Query("size"), 10)
+	request, err := bindListExpenseRequest(ctx)
+	if err != nil {
+		c.Log.Warnf("Invalid expense filter: %+v", err)
+		utils.HandleHTTPError(ctx, err)
+		return
+	}
 
-	responses, paging, err := c.UseCase.List(ctx.Request.Context(), auth, status, page, size)
+	responses, paging, err := c.UseCase.List(ctx.Request.Context(), auth, request)
 	if err != nil {
 		c.Log.Warnf("Failed to list expenses: %+v", err)
 		utils.HandleHTTPError(ctx, err)
@@ -83,6 +92,43 @@ func (c *ExpenseController) List(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, res)
 }
 
+func (c *ExpenseController) Export(ctx *gin.Context) {
+	auth, ok := getAuthOrAbort(ctx)
+	if !ok {
+		return
+	}
+
+	format := strings.ToLower(ctx.DefaultQuery("format", constants.ExportFormatCSV))
+	writer, err := utils.NewExportWriter(format, ctx.Writer)
+	if err != nil {
+		utils.HandleHTTPError(ctx, utils.Error(messages.ErrInvalidExportFormat, http.StatusBadRequest, err))
+		return
+	}
+
+	request, err := bindListExpenseRequest(ctx)
+	if err != nil {
+		c.Log.Warnf("Invalid expense filter: %+v", err)
+		utils.HandleHTTPError(ctx, err)
+		return
+	}
+
+	fileName := fmt.Sprintf("expenses-%s.%s", time.Now().Format("20060102-150405"), format)
+	ctx.Header("Content-Type", utils.ExportContentType(format))
+	ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	ctx.Header("X-Content-Type-Options", "nosniff")
+
+	if err := c.UseCase.Export(ctx.Request.Context(), auth, request, writer); err != nil {
+		c.Log.Warnf("Failed to export expenses: %+v", err)
+		if ctx.Writer.Written() {
+			ctx.Abort()
+			return
+		}
+		ctx.Writer.Header().Del("Content-Type")
+		ctx.Writer.Header().Del("Content-Disposition")
+		utils.HandleHTTPError(ctx, err)
+	}
+}
+
 func (c *ExpenseController) Get(ctx *gin.Context) {
 	auth, ok := getAuthOrAbort(ctx)
 	if !ok {
@@ -102,6 +148,7 @@ func (c *ExpenseController) Get(ctx *gin.Context) {
 		return
 	}
 
+	setETag(ctx, response.Version)
 	res := utils.SuccessResponse(messages.ExpenseFetched, response)
 	ctx.JSON(http.StatusOK, res)
 }
@@ -118,13 +165,27 @@ func (c *ExpenseController) History(ctx *gin.Context) {
 		return
 	}
 
-	response, err := c.UseCase.History(ctx.Request.Context(), auth, expenseID)
+	request := &model.ListHistoryRequest{
+		Size: parseIntQuery(ctx.Query("size"), 10),
+	}
+	if cursor, ok := ctx.GetQuery("cursor"); ok {
+		request.Cursor = &cursor
+	}
+	request.IncludeTotal, _ = strconv.ParseBool(ctx.Query("include_total"))
+
+	response, paging, err := c.UseCase.History(ctx.Request.Context(), auth, expenseID, request)
 	if err != nil {
 		c.Log.Warnf("Failed to fetch expense history: %+v", err)
 		utils.HandleHTTPError(ctx, err)
 		return
 	}
 
+	if paging != nil {
+		res := utils.SuccessWithPaginationResponse(messages.ExpenseHistoryFetched, response, *paging)
+		ctx.JSON(http.StatusOK, res)
+		return
+	}
+
 	res := utils.SuccessResponse(messages.ExpenseHistoryFetched, response)
 	ctx.JSON(http.StatusOK, res)
 }
@@ -155,6 +216,12 @@ func (c *ExpenseController) Approve(ctx *gin.Context) {
 		return
 	}
 
+	version, ok := expectedVersion(ctx)
+	if !ok {
+		return
+	}
+	request.ExpectedVersion = version
+
 	response, err := c.UseCase.Approve(ctx.Request.Context(), auth, expenseID, request)
 	if err != nil {
 		c.Log.Warnf("Failed to approve expense: %+v", err)
@@ -162,6 +229,7 @@ func (c *ExpenseController) Approve(ctx *gin.Context) {
 		return
 	}
 
+	setETag(ctx, response.Version)
 	res := utils.SuccessResponse(messages.ExpenseApproved, response)
 	ctx.JSON(http.StatusOK, res)
 }
@@ -192,6 +260,12 @@ func (c *ExpenseController) Reject(ctx *gin.Context) {
 		return
 	}
 
+	version, ok := expectedVersion(ctx)
+	if !ok {
+		return
+	}
+	request.ExpectedVersion = version
+
 	response, err := c.UseCase.Reject(ctx.Request.Context(), auth, expenseID, request)
 	if err != nil {
 		c.Log.Warnf("Failed to reject expense: %+v", err)
@@ -199,10 +273,166 @@ func (c *ExpenseController) Reject(ctx *gin.Context) {
 		return
 	}
 
+	setETag(ctx, response.Version)
 	res := utils.SuccessResponse(messages.ExpenseRejected, response)
 	ctx.JSON(http.StatusOK, res)
 }
 
+func (c *ExpenseController) RetryPayment(ctx *gin.Context) {
+	auth, ok := getAuthOrAbort(ctx)
+	if !ok {
+		return
+	}
+
+	expenseID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.HandleHTTPError(ctx, utils.Error(messages.ErrInvalidIDFormat, http.StatusBadRequest, err))
+		return
+	}
+
+	request, ok := c.bindActionRequest(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := c.UseCase.RetryPayment(ctx.Request.Context(), auth, expenseID, request)
+	if err != nil {
+		c.Log.Warnf("Failed to retry expense payment: %+v", err)
+		utils.HandleHTTPError(ctx, err)
+		return
+	}
+
+	setETag(ctx, response.Version)
+	res := utils.SuccessResponse(messages.ExpensePaymentRetried, response)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *ExpenseController) Update(ctx *gin.Context) {
+	auth, ok := getAuthOrAbort(ctx)
+	if !ok {
+		return
+	}
+
+	expenseID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.HandleHTTPError(ctx, utils.Error(messages.ErrInvalidIDFormat, http.StatusBadRequest, err))
+		return
+	}
+
+	request := new(model.UpdateExpenseRequest)
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		c.Log.Warnf("Failed to parse request body: %+v", err)
+		utils.HandleHTTPError(ctx, utils.Error(messages.FailedDataFromBody, http.StatusBadRequest, err))
+		return
+	}
+	request.ID = expenseID
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Validation failed: %+v", err)
+		message := utils.TranslateValidationError(c.Validate, err)
+		utils.HandleHTTPError(ctx, utils.Error(message, http.StatusBadRequest, err))
+		return
+	}
+
+	version, ok := expectedVersion(ctx)
+	if !ok {
+		return
+	}
+	request.ExpectedVersion = version
+
+	response, err := c.UseCase.Update(ctx.Request.Context(), auth, request)
+	if err != nil {
+		c.Log.Warnf("Failed to update expense: %+v", err)
+		utils.HandleHTTPError(ctx, err)
+		return
+	}
+
+	setETag(ctx, response.Version)
+	res := utils.SuccessResponse(messages.ExpenseUpdated, response)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *ExpenseController) Cancel(ctx *gin.Context) {
+	auth, ok := getAuthOrAbort(ctx)
+	if !ok {
+		return
+	}
+
+	expenseID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.HandleHTTPError(ctx, utils.Error(messages.ErrInvalidIDFormat, http.StatusBadRequest, err))
+		return
+	}
+
+	request, ok := c.bindActionRequest(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := c.UseCase.Cancel(ctx.Request.Context(), auth, expenseID, request)
+	if err != nil {
+		c.Log.Warnf("Failed to cancel expense: %+v", err)
+		utils.HandleHTTPError(ctx, err)
+		return
+	}
+
+	setETag(ctx, response.Version)
+	res := utils.SuccessResponse(messages.ExpenseCancelled, response)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *ExpenseController) Resubmit(ctx *gin.Context) {
+	auth, ok := getAuthOrAbort(ctx)
+	if !ok {
+		return
+	}
+
+	expenseID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.HandleHTTPError(ctx, utils.Error(messages.ErrInvalidIDFormat, http.StatusBadRequest, err))
+		return
+	}
+
+	request, ok := c.bindActionRequest(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := c.UseCase.Resubmit(ctx.Request.Context(), auth, expenseID, request)
+	if err != nil {
+		c.Log.Warnf("Failed to resubmit expense: %+v", err)
+		utils.HandleHTTPError(ctx, err)
+		return
+	}
+
+	setETag(ctx, response.Version)
+	res := utils.SuccessResponse(messages.ExpenseResubmitted, response)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *ExpenseController) bindActionRequest(ctx *gin.Context) (*model.ExpenseActionRequest, bool) {
+	request := new(model.ExpenseActionRequest)
+	if err := ctx.ShouldBindJSON(request); err != nil && !errors.Is(err, io.EOF) {
+		c.Log.Warnf("Failed to parse request body: %+v", err)
+		utils.HandleHTTPError(ctx, utils.Error(messages.FailedDataFromBody, http.StatusBadRequest, err))
+		return nil, false
+	}
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Validation failed: %+v", err)
+		message := utils.TranslateValidationError(c.Validate, err)
+		utils.HandleHTTPError(ctx, utils.Error(message, http.StatusBadRequest, err))
+		return nil, false
+	}
+
+	version, ok := expectedVersion(ctx)
+	if !ok {
+		return nil, false
+	}
+	request.ExpectedVersion = version
+	return request, true
+}
+
 func getAuthOrAbort(ctx *gin.Context) (*model.Auth, bool) {
 	auth, ok := middleware.GetUser(ctx)
 	if !ok {
@@ -213,6 +443,118 @@ func getAuthOrAbort(ctx *gin.Context) (*model.Auth, bool) {
 	return auth, true
 }
 
+func expectedVersion(ctx *gin.Context) (*int64, bool) {
+	value := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if value == "" || value == "*" {
+		return nil, true
+	}
+
+	version, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(value, "W/"), `"`), 10, 64)
+	if err != nil {
+		utils.HandleHTTPError(ctx, utils.Error(messages.ErrInvalidIfMatch, http.StatusBadRequest, err))
+		return nil, false
+	}
+	return &version, true
+}
+
+func setETag(ctx *gin.Context, version int64) {
+	ctx.Header("ETag", strconv.Quote(strconv.FormatInt(version, 10)))
+}
+
+func bindListExpenseRequest(ctx *gin.Context) (*model.ListExpenseRequest, error) {
+	request := &model.ListExpenseRequest{
+		Query: ctx.Query("q"),
+		Sort:  ctx.Query("sort"),
+		Page:  parseIntQuery(ctx.Query("page"), 1),
+		Size:  parseIntQuery(ctx.Query("size"), 10),
+	}
+
+	for _, value := range ctx.QueryArray("status") {
+		for _, status := range strings.Split(value, ",") {
+			if status = strings.TrimSpace(status); status != "" {
+				request.Statuses = append(request.Statuses, status)
+			}
+		}
+	}
+
+	var err error
+	if request.UserID, err = parseUUIDQuery(ctx.Query("user_id")); err != nil {
+		return nil, utils.Error(messages.ErrInvalidExpenseFilter, http.StatusBadRequest, err)
+	}
+	if request.CategoryID, err = parseUUIDQuery(ctx.Query("category_id")); err != nil {
+		return nil, utils.Error(messages.ErrInvalidExpenseFilter, http.StatusBadRequest, err)
+	}
+	if request.SubmittedFrom, err = parseTimeQuery(ctx.Query("submitted_from"), false); err != nil {
+		return nil, utils.Error(messages.ErrInvalidExpenseFilter, http.StatusBadRequest, err)
+	}
+	if request.SubmittedTo, err = parseTimeQuery(ctx.Query("submitted_to"), true); err != nil {
+		return nil, utils.Error(messages.ErrInvalidExpenseFilter, http.StatusBadRequest, err)
+	}
+	if request.MinAmountIDR, err = parseInt64Query(ctx.Query("min_amount")); err != nil {
+		return nil, utils.Error(messages.ErrInvalidExpenseFilter, http.StatusBadRequest, err)
+	}
+	if request.MaxAmountIDR, err = parseInt64Query(ctx.Query("max_amount")); err != nil {
+		return nil, utils.Error(messages.ErrInvalidExpenseFilter, http.StatusBadRequest, err)
+	}
+	if value := ctx.Query("possible_duplicate"); value != "" {
+		if request.PossibleDuplicate, err = strconv.ParseBool(value); err != nil {
+			return nil, utils.Error(messages.ErrInvalidExpenseFilter, http.StatusBadRequest, err)
+		}
+	}
+	if cursor, ok := ctx.GetQuery("cursor"); ok {
+		request.Cursor = &cursor
+	}
+	if value := ctx.Query("include_total"); value != "" {
+		includeTotal, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, utils.Error(messages.ErrInvalidExpenseFilter, http.StatusBadRequest, err)
+		}
+		request.IncludeTotal = &includeTotal
+	}
+
+	return request, nil
+}
+
+func parseUUIDQuery(value string) (*uuid.UUID, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := uuid.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+func parseInt64Query(value string) (*int64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+func parseTimeQuery(value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return &parsed, nil
+	}
+
+	parsed, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		parsed = parsed.AddDate(0, 0, 1)
+	}
+	return &parsed, nil
+}
+
 func parseIntQuery(value string, fallback int) int {
 	if value == "" {
 		return fallback
@@ -0,0 +1,134 @@
+package http
+
+import (
+	"errors"
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/usecase"
+	"go-expense-management-system/internal/utils"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const multipartOverheadBytes = 1 << 20
+
+type ExpenseReceiptController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.ExpenseReceiptUseCase
+}
+
+func NewExpenseReceiptController(useCase *usecase.ExpenseReceiptUseCase, logger *logrus.Logger) *ExpenseReceiptController {
+	return &ExpenseReceiptController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *ExpenseReceiptController) Upload(ctx *gin.Context) {
+	auth, ok := getAuthOrAbort(ctx)
+	if !ok {
+		return
+	}
+
+	expenseID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.HandleHTTPError(ctx, utils.Error(messages.ErrInvalidIDFormat, http.StatusBadRequest, err))
+		return
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, c.UseCase.MaxSizeBytes+multipartOverheadBytes)
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		c.Log.Warnf("Failed to read receipt file: %+v", err)
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			utils.HandleHTTPError(ctx, utils.Error(messages.ErrReceiptTooLarge, http.StatusRequestEntityTooLarge, err))
+			return
+		}
+		utils.HandleHTTPError(ctx, utils.Error(messages.ErrReceiptFileRequired, http.StatusBadRequest, err))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.Log.Warnf("Failed to open receipt file: %+v", err)
+		utils.HandleHTTPError(ctx, utils.Error(messages.ErrReceiptFileRequired, http.StatusBadRequest, err))
+		return
+	}
+	defer file.Close()
+
+	request := &model.UploadReceiptRequest{
+		ExpenseID: expenseID,
+		FileName:  fileHeader.Filename,
+		Size:      fileHeader.Size,
+		Content:   file,
+	}
+
+	response, err := c.UseCase.Upload(ctx.Request.Context(), auth, request)
+	if err != nil {
+		c.Log.Warnf("Failed to upload receipt: %+v", err)
+		utils.HandleHTTPError(ctx, err)
+		return
+	}
+
+	res := utils.SuccessResponse(messages.ExpenseReceiptUploaded, response)
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func (c *ExpenseReceiptController) List(ctx *gin.Context) {
+	auth, ok := getAuthOrAbort(ctx)
+	if !ok {
+		return
+	}
+
+	expenseID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.HandleHTTPError(ctx, utils.Error(messages.ErrInvalidIDFormat, http.StatusBadRequest, err))
+		return
+	}
+
+	responses, err := c.UseCase.List(ctx.Request.Context(), auth, expenseID)
+	if err != nil {
+		c.Log.Warnf("Failed to list receipts: %+v", err)
+		utils.HandleHTTPError(ctx, err)
+		return
+	}
+
+	res := utils.SuccessResponse(messages.ExpenseReceiptsListed, responses)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *ExpenseReceiptController) Download(ctx *gin.Context) {
+	auth, ok := getAuthOrAbort(ctx)
+	if !ok {
+		return
+	}
+
+	expenseID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.HandleHTTPError(ctx, utils.Error(messages.ErrInvalidIDFormat, http.StatusBadRequest, err))
+		return
+	}
+	receiptID, err := uuid.Parse(ctx.Param("receiptId"))
+	if err != nil {
+		utils.HandleHTTPError(ctx, utils.Error(messages.ErrInvalidIDFormat, http.StatusBadRequest, err))
+		return
+	}
+
+	download, err := c.UseCase.Download(ctx.Request.Context(), auth, expenseID, receiptID)
+	if err != nil {
+		c.Log.Warnf("Failed to download receipt: %+v", err)
+		utils.HandleHTTPError(ctx, err)
+		return
+	}
+	defer download.Content.Close()
+
+	ctx.DataFromReader(http.StatusOK, download.SizeBytes, download.ContentType, download.Content, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": download.FileName}),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
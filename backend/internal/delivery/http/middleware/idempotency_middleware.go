@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/usecase"
+	"go-expense-management-system/internal/utils"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var replayedHeaders = []string{"Content-Type", "ETag", "Location"}
+
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyWriter) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
+
+func NewIdempotency(idempotencyUseCase *usecase.IdempotencyUseCase) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := strings.TrimSpace(ctx.GetHeader(constants.IdempotencyKeyHeader))
+		if key == "" {
+			ctx.Next()
+			return
+		}
+
+		auth, ok := GetUser(ctx)
+		if !ok {
+			res := utils.FailedResponse(messages.Unauthorized)
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, constants.IdempotencyMaxBodyBytes))
+		if err != nil {
+			idempotencyUseCase.Log.Warnf("Failed to read request body: %+v", err)
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				utils.HandleHTTPError(ctx, utils.Error(messages.ErrRequestBodyTooLarge, http.StatusRequestEntityTooLarge, err))
+				return
+			}
+			utils.HandleHTTPError(ctx, utils.Error(messages.FailedDataFromBody, http.StatusBadRequest, err))
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		request := &model.IdempotencyRequest{
+			UserID:      auth.UserID,
+			Key:         key,
+			Method:      ctx.Request.Method,
+			Path:        ctx.FullPath(),
+			RequestHash: hashRequestBody(body),
+		}
+
+		result, err := idempotencyUseCase.Begin(ctx.Request.Context(), request)
+		if err != nil {
+			idempotencyUseCase.Log.Warnf("Failed to begin idempotent request: %+v", err)
+			utils.HandleHTTPError(ctx, err)
+			return
+		}
+
+		if result.Replayed {
+			for name, value := range result.Headers {
+				ctx.Header(name, value)
+			}
+			ctx.Header(constants.IdempotencyReplayedHeader, "true")
+			ctx.Data(result.StatusCode, result.Headers["Content-Type"], result.Body)
+			ctx.Abort()
+			return
+		}
+
+		writer := &idempotencyWriter{ResponseWriter: ctx.Writer}
+		ctx.Writer = writer
+		ctx.Next()
+
+		// The handler has already run, so the key must be settled even if the
+		// client went away in the meantime.
+		settleCtx := context.WithoutCancel(ctx.Request.Context())
+
+		status := writer.Status()
+		if status >= http.StatusInternalServerError {
+			if err := idempotencyUseCase.Release(settleCtx, result.ID); err != nil {
+				idempotencyUseCase.Log.Warnf("Failed to release idempotency key %s: %+v", key, err)
+			}
+			return
+		}
+
+		headers := make(map[string]string, len(replayedHeaders))
+		for _, name := range replayedHeaders {
+			if value := writer.Header().Get(name); value != "" {
+				headers[name] = value
+			}
+		}
+
+		err = idempotencyUseCase.Complete(settleCtx, result.ID, &model.IdempotentResponse{
+			StatusCode: status,
+			Headers:    headers,
+			Body:       writer.body.Bytes(),
+		})
+		if err != nil {
+			idempotencyUseCase.Log.Warnf("Failed to complete idempotency key %s: %+v", key, err)
+		}
+	}
+}
+
+func hashRequestBody(body []byte) string {
+	canonical := body
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var payload any
+	if err := decoder.Decode(&payload); err == nil {
+		if encoded, err := json.Marshal(payload); err == nil {
+			canonical = encoded
+		}
+	}
+
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/usecase"
+	"go-expense-management-system/internal/utils"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type PaymentWebhookController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.PaymentWebhookUseCase
+}
+
+func NewPaymentWebhookController(useCase *usecase.PaymentWebhookUseCase, logger *logrus.Logger) *PaymentWebhookController {
+	return &PaymentWebhookController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *PaymentWebhookController) Handle(ctx *gin.Context) {
+	payload, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, constants.PaymentWebhookMaxBodyBytes))
+	if err != nil {
+		c.Log.Warnf("Failed to read payment webhook body: %+v", err)
+		utils.HandleHTTPError(ctx, utils.Error(messages.ErrInvalidWebhookPayload, http.StatusBadRequest, err))
+		return
+	}
+
+	request := &model.PaymentWebhookRequest{
+		Signature: ctx.GetHeader(constants.PaymentWebhookSignatureHeader),
+		Timestamp: ctx.GetHeader(constants.PaymentWebhookTimestampHeader),
+		Payload:   payload,
+	}
+
+	response, err := c.UseCase.Handle(ctx.Request.Context(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to handle payment webhook: %+v", err)
+		utils.HandleHTTPError(ctx, err)
+		return
+	}
+
+	message := messages.PaymentWebhookProcessed
+	if response.Replayed {
+		message = messages.PaymentWebhookReplayed
+	}
+	res := utils.SuccessResponse(message, response)
+	ctx.JSON(http.StatusOK, res)
+}
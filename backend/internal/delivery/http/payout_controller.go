@@ -0,0 +1,112 @@
+package http
+
+import (
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/usecase"
+	"go-expense-management-system/internal/utils"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type PayoutController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.PayoutUseCase
+}
+
+func NewPayoutController(useCase *usecase.PayoutUseCase, logger *logrus.Logger) *PayoutController {
+	return &PayoutController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *PayoutController) List(ctx *gin.Context) {
+	auth, ok := getAuthOrAbort(ctx)
+	if !ok {
+		return
+	}
+
+	request := &model.ListPayoutBatchRequest{
+		Status: strings.ToLower(strings.TrimSpace(ctx.Query("status"))),
+		Page:   parseIntQuery(ctx.Query("page"), 1),
+		Size:   parseIntQuery(ctx.Query("size"), 10),
+	}
+
+	responses, paging, err := c.UseCase.List(ctx.Request.Context(), auth, request)
+	if err != nil {
+		c.Log.Warnf("Failed to list payout batches: %+v", err)
+		utils.HandleHTTPError(ctx, err)
+		return
+	}
+
+	res := utils.SuccessWithPaginationResponse(messages.PayoutBatchesListed, responses, paging)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *PayoutController) Get(ctx *gin.Context) {
+	auth, ok := getAuthOrAbort(ctx)
+	if !ok {
+		return
+	}
+
+	batchID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.HandleHTTPError(ctx, utils.Error(messages.ErrInvalidIDFormat, http.StatusBadRequest, err))
+		return
+	}
+
+	response, err := c.UseCase.Get(ctx.Request.Context(), auth, batchID)
+	if err != nil {
+		c.Log.Warnf("Failed to get payout batch: %+v", err)
+		utils.HandleHTTPError(ctx, err)
+		return
+	}
+
+	res := utils.SuccessResponse(messages.PayoutBatchFetched, response)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *PayoutController) Create(ctx *gin.Context) {
+	auth, ok := getAuthOrAbort(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := c.UseCase.Create(ctx.Request.Context(), auth)
+	if err != nil {
+		c.Log.Warnf("Failed to create payout batch: %+v", err)
+		utils.HandleHTTPError(ctx, err)
+		return
+	}
+
+	res := utils.SuccessResponse(messages.PayoutBatchCreated, response)
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func (c *PayoutController) Approve(ctx *gin.Context) {
+	auth, ok := getAuthOrAbort(ctx)
+	if !ok {
+		return
+	}
+
+	batchID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.HandleHTTPError(ctx, utils.Error(messages.ErrInvalidIDFormat, http.StatusBadRequest, err))
+		return
+	}
+
+	response, err := c.UseCase.Approve(ctx.Request.Context(), auth, batchID)
+	if err != nil {
+		c.Log.Warnf("Failed to approve payout batch: %+v", err)
+		utils.HandleHTTPError(ctx, err)
+		return
+	}
+
+	res := utils.SuccessResponse(messages.PayoutBatchApproved, response)
+	ctx.JSON(http.StatusOK, res)
+}
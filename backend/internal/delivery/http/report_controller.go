@@ -0,0 +1,52 @@
+package http
+
+import (
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/usecase"
+	"go-expense-management-system/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type ReportController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.ReportUseCase
+}
+
+func NewReportController(useCase *usecase.ReportUseCase, logger *logrus.Logger) *ReportController {
+	return &ReportController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *ReportController) Summary(ctx *gin.Context) {
+	auth, ok := getAuthOrAbort(ctx)
+	if !ok {
+		return
+	}
+
+	request := new(model.ReportSummaryRequest)
+	var err error
+	if request.From, err = parseTimeQuery(ctx.Query("from"), false); err != nil {
+		utils.HandleHTTPError(ctx, utils.Error(messages.ErrInvalidReportRange, http.StatusBadRequest, err))
+		return
+	}
+	if request.To, err = parseTimeQuery(ctx.Query("to"), true); err != nil {
+		utils.HandleHTTPError(ctx, utils.Error(messages.ErrInvalidReportRange, http.StatusBadRequest, err))
+		return
+	}
+
+	response, err := c.UseCase.Summary(ctx.Request.Context(), auth, request)
+	if err != nil {
+		c.Log.Warnf("Failed to get report summary: %+v", err)
+		utils.HandleHTTPError(ctx, err)
+		return
+	}
+
+	res := utils.SuccessResponse(messages.ReportSummaryFetched, response)
+	ctx.JSON(http.StatusOK, res)
+}
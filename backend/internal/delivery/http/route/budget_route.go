@@ -0,0 +1,10 @@
+package route
+
+import "github.com/gin-gonic/gin"
+
+func (c *RouteConfig) RegisterBudgetRoutes(rg *gin.RouterGroup) {
+	budget := rg.Group("/budgets")
+	budget.Use(c.AuthMiddleware)
+
+	budget.GET("/me", c.BudgetController.Me)
+}
@@ -0,0 +1,14 @@
+package route
+
+import "github.com/gin-gonic/gin"
+
+func (c *RouteConfig) RegisterExpenseCategoryRoutes(rg *gin.RouterGroup) {
+	category := rg.Group("/expense-categories")
+	category.Use(c.AuthMiddleware)
+
+	category.GET("", c.CategoryController.List)
+	category.POST("", c.CategoryController.Create)
+	category.GET("/:id", c.CategoryController.Get)
+	category.PUT("/:id", c.CategoryController.Update)
+	category.DELETE("/:id", c.CategoryController.Delete)
+}
@@ -6,10 +6,18 @@ func (c *RouteConfig) RegisterExpenseRoutes(rg *gin.RouterGroup) {
 	expense := rg.Group("/expenses")
 	expense.Use(c.AuthMiddleware)
 
-	expense.POST("", c.ExpenseController.Create)
+	expense.POST("", c.IdempotencyMiddleware, c.ExpenseController.Create)
 	expense.GET("", c.ExpenseController.List)
+	expense.GET("/export", c.ExpenseController.Export)
 	expense.GET("/:id", c.ExpenseController.Get)
+	expense.PATCH("/:id", c.ExpenseController.Update)
+	expense.POST("/:id/cancel", c.ExpenseController.Cancel)
+	expense.POST("/:id/resubmit", c.ExpenseController.Resubmit)
 	expense.GET("/:id/history", c.ExpenseController.History)
 	expense.PUT("/:id/approve", c.ExpenseController.Approve)
 	expense.PUT("/:id/reject", c.ExpenseController.Reject)
+	expense.POST("/:id/payment/retry", c.ExpenseController.RetryPayment)
+	expense.POST("/:id/receipts", c.ReceiptController.Upload)
+	expense.GET("/:id/receipts", c.ReceiptController.List)
+	expense.GET("/:id/receipts/:receiptId/download", c.ReceiptController.Download)
 }
@@ -0,0 +1,13 @@
+package route
+
+import "github.com/gin-gonic/gin"
+
+func (c *RouteConfig) RegisterPayoutRoutes(rg *gin.RouterGroup) {
+	payout := rg.Group("/payouts")
+	payout.Use(c.AuthMiddleware)
+
+	payout.GET("", c.PayoutController.List)
+	payout.POST("", c.PayoutController.Create)
+	payout.GET("/:id", c.PayoutController.Get)
+	payout.POST("/:id/approve", c.PayoutController.Approve)
+}
@@ -0,0 +1,10 @@
+package route
+
+import "github.com/gin-gonic/gin"
+
+func (c *RouteConfig) RegisterReportRoutes(rg *gin.RouterGroup) {
+	report := rg.Group("/reports")
+	report.Use(c.AuthMiddleware)
+
+	report.GET("/summary", c.ReportController.Summary)
+}
@@ -7,17 +7,31 @@ import (
 )
 
 type RouteConfig struct {
-	Router            *gin.Engine
-	UserController    *http.UserController
-	ExpenseController *http.ExpenseController
-	AuthMiddleware    gin.HandlerFunc
+	Router                   *gin.Engine
+	UserController           *http.UserController
+	BankAccountController    *http.BankAccountController
+	ExpenseController        *http.ExpenseController
+	CategoryController       *http.ExpenseCategoryController
+	ReceiptController        *http.ExpenseReceiptController
+	BudgetController         *http.BudgetController
+	ReportController         *http.ReportController
+	PayoutController         *http.PayoutController
+	PaymentWebhookController *http.PaymentWebhookController
+	AuthMiddleware           gin.HandlerFunc
+	IdempotencyMiddleware    gin.HandlerFunc
 }
 
 func (c *RouteConfig) Setup() {
 	api := c.Router.Group("/api")
 
 	c.RegisterAuthRoutes(api)
+	c.RegisterUserRoutes(api)
 	c.RegisterExpenseRoutes(api)
+	c.RegisterExpenseCategoryRoutes(api)
+	c.RegisterBudgetRoutes(api)
+	c.RegisterReportRoutes(api)
+	c.RegisterPayoutRoutes(api)
+	c.RegisterWebhookRoutes(api)
 	c.RegisterApiRoutes(api)
 	c.RegisterPublicRoutes()
 }
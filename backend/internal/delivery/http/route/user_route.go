@@ -8,3 +8,11 @@ func (c *RouteConfig) RegisterAuthRoutes(rg *gin.RouterGroup) {
 	auth.POST("/register", c.UserController.Register)
 	auth.POST("/login", c.UserController.Login)
 }
+
+func (c *RouteConfig) RegisterUserRoutes(rg *gin.RouterGroup) {
+	users := rg.Group("/users")
+	users.Use(c.AuthMiddleware)
+
+	users.GET("/me/bank-account", c.BankAccountController.Me)
+	users.PUT("/me/bank-account", c.BankAccountController.Update)
+}
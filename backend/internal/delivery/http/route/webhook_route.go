@@ -0,0 +1,9 @@
+package route
+
+import "github.com/gin-gonic/gin"
+
+func (c *RouteConfig) RegisterWebhookRoutes(rg *gin.RouterGroup) {
+	webhook := rg.Group("/webhooks")
+
+	webhook.POST("/payments", c.PaymentWebhookController.Handle)
+}
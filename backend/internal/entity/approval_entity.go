@@ -8,14 +8,20 @@ import (
 )
 
 type Approval struct {
-	ID         uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
-	ExpenseID  uuid.UUID `gorm:"type:char(36);index;not null" json:"expense_id"`
-	ApproverID uuid.UUID `gorm:"type:char(36);index;not null" json:"approver_id"`
-	Status     string    `gorm:"type:varchar(30);not null" json:"status"`
-	Notes      string    `gorm:"type:text" json:"notes,omitempty"`
-	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime:milli" json:"created_at"`
-	Expense    Expense   `gorm:"foreignKey:ExpenseID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
-	Approver   User      `gorm:"foreignKey:ApproverID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT" json:"-"`
+	ID              uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
+	ExpenseID       uuid.UUID  `gorm:"type:char(36);index;not null" json:"expense_id"`
+	ApproverID      *uuid.UUID `gorm:"type:char(36);index" json:"approver_id,omitempty"`
+	Step            int        `gorm:"not null;default:1" json:"step"`
+	RequiredRole    string     `gorm:"type:varchar(20);not null;default:manager" json:"required_role"`
+	Status          string     `gorm:"type:varchar(30);not null" json:"status"`
+	Notes           string     `gorm:"type:text" json:"notes,omitempty"`
+	DecidedAt       *time.Time `gorm:"column:decided_at" json:"decided_at,omitempty"`
+	ActivatedAt     *time.Time `gorm:"column:activated_at" json:"activated_at,omitempty"`
+	EscalationLevel int        `gorm:"not null;default:0" json:"escalation_level"`
+	EscalatedAt     *time.Time `gorm:"column:escalated_at" json:"escalated_at,omitempty"`
+	CreatedAt       time.Time  `gorm:"column:created_at;autoCreateTime:milli" json:"created_at"`
+	Expense         Expense    `gorm:"foreignKey:ExpenseID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Approver        *User      `gorm:"foreignKey:ApproverID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT" json:"-"`
 }
 
 func (a *Approval) TableName() string {
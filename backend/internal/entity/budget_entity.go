@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"go-expense-management-system/internal/constants"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Budget struct {
+	ID           uuid.UUID   `gorm:"type:char(36);primaryKey" json:"id"`
+	UserID       *uuid.UUID  `gorm:"type:char(36);index" json:"user_id,omitempty"`
+	DepartmentID *uuid.UUID  `gorm:"type:char(36);index" json:"department_id,omitempty"`
+	Period       string      `gorm:"type:varchar(20);not null" json:"period"`
+	LimitIDR     int64       `gorm:"not null" json:"limit_idr"`
+	Enforcement  string      `gorm:"type:varchar(20);not null;default:require_approval" json:"enforcement"`
+	IsActive     bool        `gorm:"not null;default:true" json:"is_active"`
+	CreatedAt    time.Time   `gorm:"column:created_at;autoCreateTime:milli" json:"created_at"`
+	UpdatedAt    time.Time   `gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli" json:"updated_at"`
+	User         *User       `gorm:"foreignKey:UserID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Department   *Department `gorm:"foreignKey:DepartmentID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+func (b *Budget) TableName() string {
+	return "budgets"
+}
+
+func (b *Budget) Scope() string {
+	if b.UserID != nil {
+		return constants.BudgetScopeUser
+	}
+	return constants.BudgetScopeDepartment
+}
+
+func (b *Budget) BeforeCreate(_ *gorm.DB) (err error) {
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Department struct {
+	ID        uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	Code      string    `gorm:"type:varchar(50);uniqueIndex;not null" json:"code"`
+	Name      string    `gorm:"type:varchar(100);not null" json:"name"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime:milli" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli" json:"updated_at"`
+	Users     []User    `gorm:"foreignKey:DepartmentID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"-"`
+}
+
+func (d *Department) TableName() string {
+	return "departments"
+}
+
+func (d *Department) BeforeCreate(_ *gorm.DB) (err error) {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return
+}
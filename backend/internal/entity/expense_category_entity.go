@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ExpenseCategory struct {
+	ID                      uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	Code                    string    `gorm:"type:varchar(50);uniqueIndex;not null" json:"code"`
+	Name                    string    `gorm:"type:varchar(100);not null" json:"name"`
+	MinAmountIDR            int64     `gorm:"not null" json:"min_amount_idr"`
+	MaxAmountIDR            int64     `gorm:"not null" json:"max_amount_idr"`
+	ApprovalThresholdIDR    int64     `gorm:"not null" json:"approval_threshold_idr"`
+	ReceiptRequiredAboveIDR *int64    `gorm:"column:receipt_required_above_idr" json:"receipt_required_above_idr,omitempty"`
+	IsActive                bool      `gorm:"not null;default:true" json:"is_active"`
+	CreatedAt               time.Time `gorm:"column:created_at;autoCreateTime:milli" json:"created_at"`
+	UpdatedAt               time.Time `gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli" json:"updated_at"`
+	Expenses                []Expense `gorm:"foreignKey:CategoryID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT" json:"-"`
+}
+
+func (c *ExpenseCategory) TableName() string {
+	return "expense_categories"
+}
+
+func (c *ExpenseCategory) BeforeCreate(_ *gorm.DB) (err error) {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return
+}
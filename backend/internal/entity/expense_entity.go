@@ -8,19 +8,24 @@ import (
 )
 
 type Expense struct {
-	ID              uuid.UUID              `gorm:"type:char(36);primaryKey" json:"id"`
-	UserID          uuid.UUID              `gorm:"type:char(36);index;not null" json:"user_id"`
-	AmountIDR       int64                  `gorm:"not null" json:"amount_idr"`
-	Description     string                 `gorm:"type:varchar(255);not null" json:"description"`
-	ReceiptURL      string                 `gorm:"type:text" json:"receipt_url,omitempty"`
-	Status          string                 `gorm:"type:varchar(30);not null" json:"status"`
-	SubmittedAt     time.Time              `gorm:"column:submitted_at;autoCreateTime:milli" json:"submitted_at"`
-	ProcessedAt     *time.Time             `gorm:"column:processed_at" json:"processed_at,omitempty"`
-	CreatedAt       time.Time              `gorm:"column:created_at;autoCreateTime:milli" json:"created_at"`
-	UpdatedAt       time.Time              `gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli" json:"updated_at"`
-	User            User                   `gorm:"foreignKey:UserID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT" json:"-"`
-	Approvals       []Approval             `gorm:"foreignKey:ExpenseID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
-	StatusHistories []ExpenseStatusHistory `gorm:"foreignKey:ExpenseID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	ID                  uuid.UUID              `gorm:"type:char(36);primaryKey;index:idx_expenses_submitted_at_id,priority:2" json:"id"`
+	UserID              uuid.UUID              `gorm:"type:char(36);index;not null" json:"user_id"`
+	CategoryID          *uuid.UUID             `gorm:"type:char(36);index" json:"category_id,omitempty"`
+	AmountIDR           int64                  `gorm:"not null" json:"amount_idr"`
+	Description         string                 `gorm:"type:varchar(255);not null" json:"description"`
+	ReceiptURL          string                 `gorm:"type:text" json:"receipt_url,omitempty"`
+	Status              string                 `gorm:"type:varchar(30);not null" json:"status"`
+	RequiresApproval    bool                   `gorm:"not null;default:false" json:"requires_approval"`
+	PossibleDuplicateOf *uuid.UUID             `gorm:"column:possible_duplicate_of;type:char(36);index" json:"possible_duplicate_of,omitempty"`
+	Version             int64                  `gorm:"not null;default:1" json:"version"`
+	SubmittedAt         time.Time              `gorm:"column:submitted_at;autoCreateTime:milli;index:idx_expenses_submitted_at_id,priority:1" json:"submitted_at"`
+	ProcessedAt         *time.Time             `gorm:"column:processed_at" json:"processed_at,omitempty"`
+	CreatedAt           time.Time              `gorm:"column:created_at;autoCreateTime:milli" json:"created_at"`
+	UpdatedAt           time.Time              `gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli" json:"updated_at"`
+	User                User                   `gorm:"foreignKey:UserID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT" json:"-"`
+	Category            *ExpenseCategory       `gorm:"foreignKey:CategoryID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT" json:"-"`
+	Approvals           []Approval             `gorm:"foreignKey:ExpenseID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	StatusHistories     []ExpenseStatusHistory `gorm:"foreignKey:ExpenseID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
 }
 
 func (e *Expense) TableName() string {
@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ExpenseReceipt struct {
+	ID          uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	ExpenseID   uuid.UUID `gorm:"type:char(36);index;not null" json:"expense_id"`
+	UploadedBy  uuid.UUID `gorm:"type:char(36);index;not null" json:"uploaded_by"`
+	FileName    string    `gorm:"type:varchar(255);not null" json:"file_name"`
+	ContentType string    `gorm:"type:varchar(100);not null" json:"content_type"`
+	SizeBytes   int64     `gorm:"not null" json:"size_bytes"`
+	SHA256      string    `gorm:"column:sha256;type:char(64);index;not null" json:"sha256"`
+	StorageKey  string    `gorm:"type:varchar(500);not null" json:"-"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime:milli" json:"created_at"`
+	Expense     Expense   `gorm:"foreignKey:ExpenseID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Uploader    User      `gorm:"foreignKey:UploadedBy;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT" json:"-"`
+}
+
+func (e *ExpenseReceipt) TableName() string {
+	return "expense_receipts"
+}
+
+func (e *ExpenseReceipt) BeforeCreate(_ *gorm.DB) (err error) {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return
+}
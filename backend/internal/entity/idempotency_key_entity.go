@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type IdempotencyKey struct {
+	ID              uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	UserID          uuid.UUID `gorm:"type:char(36);uniqueIndex:idx_idempotency_keys_user_key;not null" json:"user_id"`
+	Key             string    `gorm:"column:key;type:varchar(255);uniqueIndex:idx_idempotency_keys_user_key;not null" json:"key"`
+	Method          string    `gorm:"type:varchar(10);not null" json:"method"`
+	Path            string    `gorm:"type:varchar(255);not null" json:"path"`
+	RequestHash     string    `gorm:"type:char(64);not null" json:"request_hash"`
+	Status          string    `gorm:"type:varchar(20);not null" json:"status"`
+	ResponseStatus  int       `gorm:"not null;default:0" json:"response_status"`
+	ResponseHeaders string    `gorm:"type:text" json:"-"`
+	ResponseBody    []byte    `gorm:"type:bytea" json:"-"`
+	ExpiresAt       time.Time `gorm:"column:expires_at;index;not null" json:"expires_at"`
+	CreatedAt       time.Time `gorm:"column:created_at;autoCreateTime:milli" json:"created_at"`
+	UpdatedAt       time.Time `gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli" json:"updated_at"`
+	User            User      `gorm:"foreignKey:UserID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+func (k *IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
+
+func (k *IdempotencyKey) BeforeCreate(_ *gorm.DB) (err error) {
+	if k.ID == uuid.Nil {
+		k.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Payment struct {
+	ID                uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
+	ExpenseID         uuid.UUID  `gorm:"type:char(36);uniqueIndex;not null" json:"expense_id"`
+	ProviderPaymentID string     `gorm:"type:varchar(100);index" json:"provider_payment_id,omitempty"`
+	ExternalID        string     `gorm:"type:varchar(100);index;not null" json:"external_id"`
+	AmountIDR         int64      `gorm:"not null" json:"amount_idr"`
+	ProviderStatus    string     `gorm:"type:varchar(30)" json:"provider_status,omitempty"`
+	Attempts          int        `gorm:"not null;default:0" json:"attempts"`
+	LastError         string     `gorm:"type:text" json:"last_error,omitempty"`
+	RequestedAt       *time.Time `gorm:"column:requested_at" json:"requested_at,omitempty"`
+	RespondedAt       *time.Time `gorm:"column:responded_at" json:"responded_at,omitempty"`
+	CreatedAt         time.Time  `gorm:"column:created_at;autoCreateTime:milli" json:"created_at"`
+	UpdatedAt         time.Time  `gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli" json:"updated_at"`
+	Expense           Expense    `gorm:"foreignKey:ExpenseID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+func (p *Payment) TableName() string {
+	return "payments"
+}
+
+func (p *Payment) BeforeCreate(_ *gorm.DB) (err error) {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PaymentJob struct {
+	ID          uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
+	ExpenseID   uuid.UUID  `gorm:"type:char(36);uniqueIndex;not null" json:"expense_id"`
+	AmountIDR   int64      `gorm:"not null" json:"amount_idr"`
+	ExternalID  string     `gorm:"type:varchar(100);not null" json:"external_id"`
+	Status      string     `gorm:"type:varchar(30);index:idx_payment_jobs_status_next_run;not null" json:"status"`
+	Attempts    int        `gorm:"not null;default:0" json:"attempts"`
+	NextRunAt   time.Time  `gorm:"column:next_run_at;index:idx_payment_jobs_status_next_run;not null" json:"next_run_at"`
+	LockedUntil *time.Time `gorm:"column:locked_until" json:"locked_until,omitempty"`
+	LastError   string     `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedAt   time.Time  `gorm:"column:created_at;autoCreateTime:milli" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli" json:"updated_at"`
+	Expense     Expense    `gorm:"foreignKey:ExpenseID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+func (p *PaymentJob) TableName() string {
+	return "payment_jobs"
+}
+
+func (p *PaymentJob) BeforeCreate(_ *gorm.DB) (err error) {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PaymentWebhookEvent struct {
+	ID         uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
+	EventID    string     `gorm:"type:varchar(100);uniqueIndex;not null" json:"event_id"`
+	ExpenseID  *uuid.UUID `gorm:"type:char(36);index" json:"expense_id,omitempty"`
+	Status     string     `gorm:"type:varchar(30)" json:"status,omitempty"`
+	SignedAt   time.Time  `gorm:"column:signed_at;not null" json:"signed_at"`
+	ReceivedAt time.Time  `gorm:"column:received_at;index;not null" json:"received_at"`
+}
+
+func (e *PaymentWebhookEvent) TableName() string {
+	return "payment_webhook_events"
+}
+
+func (e *PaymentWebhookEvent) BeforeCreate(_ *gorm.DB) (err error) {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PayoutBatch struct {
+	ID              uuid.UUID         `gorm:"type:char(36);primaryKey" json:"id"`
+	Status          string            `gorm:"type:varchar(20);index;not null" json:"status"`
+	ItemCount       int               `gorm:"not null;default:0" json:"item_count"`
+	TotalAmountIDR  int64             `gorm:"not null;default:0" json:"total_amount_idr"`
+	CreatedBy       *uuid.UUID        `gorm:"type:char(36)" json:"created_by,omitempty"`
+	ApprovedBy      *uuid.UUID        `gorm:"type:char(36)" json:"approved_by,omitempty"`
+	ApprovedAt      *time.Time        `gorm:"column:approved_at" json:"approved_at,omitempty"`
+	SubmittedAt     *time.Time        `gorm:"column:submitted_at" json:"submitted_at,omitempty"`
+	ProviderBatchID string            `gorm:"type:varchar(100)" json:"provider_batch_id,omitempty"`
+	LastError       string            `gorm:"type:text" json:"last_error,omitempty"`
+	CompletedAt     *time.Time        `gorm:"column:completed_at" json:"completed_at,omitempty"`
+	CreatedAt       time.Time         `gorm:"column:created_at;autoCreateTime:milli" json:"created_at"`
+	UpdatedAt       time.Time         `gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli" json:"updated_at"`
+	Items           []PayoutBatchItem `gorm:"foreignKey:BatchID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"items,omitempty"`
+}
+
+func (b *PayoutBatch) TableName() string {
+	return "payout_batches"
+}
+
+func (b *PayoutBatch) BeforeCreate(_ *gorm.DB) (err error) {
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	return
+}
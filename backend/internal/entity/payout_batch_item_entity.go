@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PayoutBatchItem struct {
+	ID                uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	BatchID           uuid.UUID `gorm:"type:char(36);index;not null" json:"batch_id"`
+	ExpenseID         uuid.UUID `gorm:"type:char(36);index;not null" json:"expense_id"`
+	UserID            uuid.UUID `gorm:"type:char(36);not null" json:"user_id"`
+	AmountIDR         int64     `gorm:"not null" json:"amount_idr"`
+	BankCode          string    `gorm:"type:varchar(20)" json:"bank_code"`
+	AccountNumber     string    `gorm:"type:varchar(50)" json:"account_number"`
+	AccountHolderName string    `gorm:"type:varchar(100)" json:"account_holder_name"`
+	Status            string    `gorm:"type:varchar(20);index;not null" json:"status"`
+	ProviderPaymentID string    `gorm:"type:varchar(100)" json:"provider_payment_id,omitempty"`
+	ProviderStatus    string    `gorm:"type:varchar(30)" json:"provider_status,omitempty"`
+	FailureReason     string    `gorm:"type:text" json:"failure_reason,omitempty"`
+	CreatedAt         time.Time `gorm:"column:created_at;autoCreateTime:milli" json:"created_at"`
+	UpdatedAt         time.Time `gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli" json:"updated_at"`
+	Expense           Expense   `gorm:"foreignKey:ExpenseID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+func (i *PayoutBatchItem) TableName() string {
+	return "payout_batch_items"
+}
+
+func (i *PayoutBatchItem) BeforeCreate(_ *gorm.DB) (err error) {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type UserBankAccount struct {
+	ID                uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
+	UserID            uuid.UUID  `gorm:"type:char(36);uniqueIndex;not null" json:"user_id"`
+	BankCode          string     `gorm:"type:varchar(20);not null" json:"bank_code"`
+	AccountNumber     string     `gorm:"type:varchar(50);not null" json:"account_number"`
+	AccountHolderName string     `gorm:"type:varchar(100);not null" json:"account_holder_name"`
+	IsVerified        bool       `gorm:"not null;default:false" json:"is_verified"`
+	VerifiedAt        *time.Time `gorm:"column:verified_at" json:"verified_at,omitempty"`
+	VerifiedBy        *uuid.UUID `gorm:"type:char(36)" json:"verified_by,omitempty"`
+	CreatedAt         time.Time  `gorm:"column:created_at;autoCreateTime:milli" json:"created_at"`
+	UpdatedAt         time.Time  `gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli" json:"updated_at"`
+	User              User       `gorm:"foreignKey:UserID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+func (a *UserBankAccount) TableName() string {
+	return "user_bank_accounts"
+}
+
+func (a *UserBankAccount) BeforeCreate(_ *gorm.DB) (err error) {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return
+}
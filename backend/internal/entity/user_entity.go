@@ -14,9 +14,12 @@ type User struct {
 	Name         string                 `gorm:"type:varchar(100);not null" json:"name"`
 	Email        string                 `gorm:"type:varchar(100);uniqueIndex;not null" json:"email"`
 	Role         string                 `gorm:"type:varchar(20);not null;default:employee" json:"role"`
+	ManagerID    *uuid.UUID             `gorm:"type:char(36);index" json:"manager_id,omitempty"`
+	DepartmentID *uuid.UUID             `gorm:"type:char(36);index" json:"department_id,omitempty"`
 	PasswordHash string                 `gorm:"type:varchar(255);not null" json:"-"`
 	CreatedAt    time.Time              `gorm:"column:created_at;autoCreateTime:milli" json:"created_at"`
 	UpdatedAt    time.Time              `gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli" json:"updated_at"`
+	Manager      *User                  `gorm:"foreignKey:ManagerID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"-"`
 	Expenses     []Expense              `gorm:"foreignKey:UserID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT" json:"-"`
 	Approvals    []Approval             `gorm:"foreignKey:ApproverID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT" json:"-"`
 	StatusLogs   []ExpenseStatusHistory `gorm:"foreignKey:ActorID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"-"`
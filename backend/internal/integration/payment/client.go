@@ -8,6 +8,7 @@ import (
 	"go-expense-management-system/internal/model"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 
 type Client struct {
 	BaseURL    string
+	APIKey     string
 	HTTPClient *http.Client
 	Log        *logrus.Logger
 }
@@ -29,6 +31,13 @@ func NewClient(baseURL string, timeout time.Duration, log *logrus.Logger) *Clien
 	}
 }
 
+func (c *Client) setHeaders(req *http.Request) {
+	req.Header.Set("Accept", "application/json")
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+}
+
 func (c *Client) Process(ctx context.Context, request model.PaymentRequest) (*model.PaymentResponse, error) {
 	if request.Amount <= 0 || request.ExternalID == "" {
 		return nil, fmt.Errorf("invalid payment request")
@@ -45,10 +54,11 @@ func (c *Client) Process(ctx context.Context, request model.PaymentRequest) (*mo
 		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/json")
+	c.setHeaders(req)
 
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, &APIError{Err: err}
 	}
 	defer resp.Body.Close()
 
@@ -86,7 +96,94 @@ func (c *Client) Process(ctx context.Context, request model.PaymentRequest) (*mo
 	if c.Log != nil {
 		c.Log.Warnf("Payment API error: status=%d body=%s", resp.StatusCode, string(body))
 	}
-	return nil, fmt.Errorf("payment api error: %s", resp.Status)
+	return nil, newAPIError(resp, body)
+}
+
+func (c *Client) Get(ctx context.Context, externalID string) (*model.PaymentResponse, error) {
+	if externalID == "" {
+		return nil, fmt.Errorf("invalid payment external id")
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/payments/%s", c.BaseURL, url.PathEscape(externalID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.setHeaders(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, &APIError{Err: err}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		if c.Log != nil {
+			c.Log.Warnf("Payment API error: status=%d body=%s", resp.StatusCode, string(body))
+		}
+		return nil, newAPIError(resp, body)
+	}
+
+	var parsed paymentAPIResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, err
+	}
+	return &model.PaymentResponse{
+		ID:         parsed.Data.ID,
+		ExternalID: parsed.Data.ExternalID,
+		Status:     parsed.Data.Status,
+	}, nil
+}
+
+func (c *Client) ProcessBulk(ctx context.Context, request model.BulkPaymentRequest) (*model.BulkPaymentResponse, error) {
+	if request.ExternalID == "" || len(request.Items) == 0 {
+		return nil, fmt.Errorf("invalid bulk payment request")
+	}
+
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/payouts/bulk", c.BaseURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	c.setHeaders(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, &APIError{Err: err}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		if c.Log != nil {
+			c.Log.Warnf("Payment API error: status=%d body=%s", resp.StatusCode, string(body))
+		}
+		return nil, newAPIError(resp, body)
+	}
+
+	var parsed bulkPaymentAPIResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, err
+	}
+	return &parsed.Data, nil
 }
 
 type paymentAPIResponse struct {
@@ -97,3 +194,8 @@ type paymentAPIResponse struct {
 	} `json:"data"`
 	Message string `json:"message"`
 }
+
+type bulkPaymentAPIResponse struct {
+	Data    model.BulkPaymentResponse `json:"data"`
+	Message string                    `json:"message"`
+}
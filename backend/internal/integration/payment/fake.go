@@ -0,0 +1,66 @@
+package payment
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/model"
+)
+
+type FakeProvider struct {
+	Status string
+}
+
+func NewFakeProvider(status string) *FakeProvider {
+	if status == "" {
+		status = constants.PaymentProviderStatusCompleted
+	}
+	return &FakeProvider{Status: status}
+}
+
+func (p *FakeProvider) Process(_ context.Context, request model.PaymentRequest) (*model.PaymentResponse, error) {
+	if request.Amount <= 0 || request.ExternalID == "" {
+		return nil, fmt.Errorf("invalid payment request")
+	}
+	return p.response(request.ExternalID), nil
+}
+
+func (p *FakeProvider) Get(_ context.Context, externalID string) (*model.PaymentResponse, error) {
+	if externalID == "" {
+		return nil, fmt.Errorf("invalid payment external id")
+	}
+	return p.response(externalID), nil
+}
+
+func (p *FakeProvider) response(externalID string) *model.PaymentResponse {
+	sum := sha256.Sum256([]byte(externalID))
+	return &model.PaymentResponse{
+		ID:         "fake_" + hex.EncodeToString(sum[:8]),
+		ExternalID: externalID,
+		Status:     p.Status,
+	}
+}
+
+func (p *FakeProvider) ProcessBulk(ctx context.Context, request model.BulkPaymentRequest) (*model.BulkPaymentResponse, error) {
+	if request.ExternalID == "" || len(request.Items) == 0 {
+		return nil, fmt.Errorf("invalid bulk payment request")
+	}
+
+	sum := sha256.Sum256([]byte(request.ExternalID))
+	response := &model.BulkPaymentResponse{
+		ID:         "fake_batch_" + hex.EncodeToString(sum[:8]),
+		ExternalID: request.ExternalID,
+		Status:     constants.PaymentProviderStatusProcessing,
+		Items:      make([]model.PaymentResponse, 0, len(request.Items)),
+	}
+	for _, item := range request.Items {
+		result, err := p.Process(ctx, item)
+		if err != nil {
+			return nil, err
+		}
+		response.Items = append(response.Items, *result)
+	}
+	return response, nil
+}
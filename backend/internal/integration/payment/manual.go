@@ -0,0 +1,64 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/model"
+
+	"github.com/sirupsen/logrus"
+)
+
+type ManualProvider struct {
+	Log *logrus.Logger
+}
+
+func NewManualProvider(log *logrus.Logger) *ManualProvider {
+	return &ManualProvider{Log: log}
+}
+
+func (p *ManualProvider) Process(_ context.Context, request model.PaymentRequest) (*model.PaymentResponse, error) {
+	if request.Amount <= 0 || request.ExternalID == "" {
+		return nil, fmt.Errorf("invalid payment request")
+	}
+	if p.Log != nil {
+		p.Log.Infof("Payment %s for %d IDR is awaiting manual bank transfer", request.ExternalID, request.Amount)
+	}
+	return p.response(request.ExternalID), nil
+}
+
+func (p *ManualProvider) Get(_ context.Context, externalID string) (*model.PaymentResponse, error) {
+	if externalID == "" {
+		return nil, fmt.Errorf("invalid payment external id")
+	}
+	return p.response(externalID), nil
+}
+
+func (p *ManualProvider) response(externalID string) *model.PaymentResponse {
+	return &model.PaymentResponse{
+		ID:         "manual_" + externalID,
+		ExternalID: externalID,
+		Status:     constants.PaymentProviderStatusAwaitingManualTransfer,
+	}
+}
+
+func (p *ManualProvider) ProcessBulk(ctx context.Context, request model.BulkPaymentRequest) (*model.BulkPaymentResponse, error) {
+	if request.ExternalID == "" || len(request.Items) == 0 {
+		return nil, fmt.Errorf("invalid bulk payment request")
+	}
+
+	response := &model.BulkPaymentResponse{
+		ID:         "manual_" + request.ExternalID,
+		ExternalID: request.ExternalID,
+		Status:     constants.PaymentProviderStatusAwaitingManualTransfer,
+		Items:      make([]model.PaymentResponse, 0, len(request.Items)),
+	}
+	for _, item := range request.Items {
+		result, err := p.Process(ctx, item)
+		if err != nil {
+			return nil, err
+		}
+		response.Items = append(response.Items, *result)
+	}
+	return response, nil
+}
@@ -17,17 +17,57 @@ const (
 )
 
 const (
-	ErrUserAlreadyExists      = "User with this email already exists"
-	ErrCheckUser              = "Failed to check user"
-	ErrInvalidEmailOrPassword = "Invalid email or password"
-	ErrProcessPassword        = "Failed to process password"
-	ErrGenerateAccessToken    = "Failed to generate access token"
-	ErrCreateUser             = "Failed to create user"
-	ErrCommitTransaction      = "Failed to commit transaction"
-	ErrUserNotFound           = "User not found"
-	ErrExpenseNotFound        = "Expense not found"
-	ErrInvalidExpenseAmount   = "Invalid expense amount"
-	ErrExpenseNotPending      = "Expense is not awaiting approval"
-	ErrExpenseAlreadyDone     = "Expense already processed"
-	ErrPaymentFailed          = "Payment processing failed"
+	ErrUserAlreadyExists       = "User with this email already exists"
+	ErrCheckUser               = "Failed to check user"
+	ErrInvalidEmailOrPassword  = "Invalid email or password"
+	ErrProcessPassword         = "Failed to process password"
+	ErrGenerateAccessToken     = "Failed to generate access token"
+	ErrCreateUser              = "Failed to create user"
+	ErrCommitTransaction       = "Failed to commit transaction"
+	ErrUserNotFound            = "User not found"
+	ErrExpenseNotFound         = "Expense not found"
+	ErrInvalidExpenseAmount    = "Invalid expense amount"
+	ErrReceiptRequired         = "Receipt is required for this expense"
+	ErrReceiptFileRequired     = "Receipt file is required"
+	ErrReceiptTooLarge         = "Receipt file is too large"
+	ErrReceiptUnsupportedType  = "Receipt must be a JPEG, PNG or PDF file"
+	ErrReceiptNotFound         = "Receipt not found"
+	ErrReceiptStore            = "Failed to store receipt"
+	ErrReceiptExpenseClosed    = "Receipts cannot be added to a completed or cancelled expense"
+	ErrCategoryNotFound        = "Expense category not found"
+	ErrCategoryInactive        = "Expense category is inactive"
+	ErrCategoryAlreadyExists   = "Expense category with this code already exists"
+	ErrCategoryInUse           = "Expense category is used by existing expenses"
+	ErrInvalidStatusTransition = "Expense status does not allow this action"
+	ErrExpenseModified         = "Expense was modified by another request, reload and try again"
+	ErrExpenseVersionMismatch  = "Expense version does not match If-Match"
+	ErrInvalidIfMatch          = "Invalid If-Match header"
+	ErrApprovalStepRole        = "Current approval step requires a different role"
+	ErrSelfApproval            = "Approvers cannot decide their own expense"
+	ErrApproverOutsideChain    = "Approver is not in the requester's reporting line"
+	ErrNoApproverInChain       = "No one in the requester's reporting line holds the role required for this step"
+	ErrExpenseAlreadyDone      = "Expense already processed"
+	ErrPaymentFailed           = "Payment processing failed"
+	ErrBudgetExceeded          = "Expense exceeds the remaining budget"
+	ErrInvalidExpenseFilter    = "Invalid expense filter"
+	ErrInvalidCursor           = "Invalid cursor"
+	ErrInvalidExportFormat     = "Export format must be csv or xlsx"
+	ErrInvalidReportRange      = "Invalid report date range"
+	ErrPaymentNotFound         = "Payment not found"
+	ErrBankAccountNotFound     = "Bank account not found"
+	ErrBankAccountNotVerified  = "Requester has no verified bank account"
+	ErrPayoutBatchNotFound     = "Payout batch not found"
+	ErrPayoutBatchNotDraft     = "Only draft payout batches can be approved"
+	ErrPayoutBatchDisabled     = "Batch payouts are disabled, set PAYOUT_MODE=batch"
+	ErrPayoutNothingToPay      = "No approved expenses are waiting for payout"
+	ErrWebhookNotConfigured    = "Payment webhook is not configured"
+	ErrInvalidWebhookSignature = "Invalid webhook signature"
+	ErrWebhookTimestampExpired = "Webhook timestamp is outside the allowed window"
+	ErrInvalidWebhookPayload   = "Invalid webhook payload"
+	ErrCursorSortUnsupported   = "Cursor pagination only supports sorting by submitted_at"
+	ErrInvalidExpenseSort      = "Invalid sort, use <field>[:asc|desc] with submitted_at, amount_idr, status or processed_at"
+	ErrIdempotencyKeyInvalid   = "Idempotency-Key must be at most 255 characters"
+	ErrIdempotencyKeyReused    = "Idempotency-Key was already used with a different request"
+	ErrIdempotencyInProgress   = "A request with this Idempotency-Key is still being processed"
+	ErrRequestBodyTooLarge     = "Request body is too large"
 )
@@ -1,13 +1,34 @@
 package messages
 
 const (
-	WelcomeMessage        = "Welcome to the Expense Management System"
-	UserRegistered        = "User registered successfully"
-	UserLoggedIn          = "User logged in successfully"
-	ExpenseCreated        = "Expense submitted successfully"
-	ExpenseListed         = "Expenses retrieved successfully"
-	ExpenseFetched        = "Expense retrieved successfully"
-	ExpenseApproved       = "Expense approved successfully"
-	ExpenseRejected       = "Expense rejected successfully"
-	ExpenseHistoryFetched = "Expense history retrieved successfully"
+	WelcomeMessage          = "Welcome to the Expense Management System"
+	UserRegistered          = "User registered successfully"
+	UserLoggedIn            = "User logged in successfully"
+	ExpenseCreated          = "Expense submitted successfully"
+	ExpenseListed           = "Expenses retrieved successfully"
+	ExpenseFetched          = "Expense retrieved successfully"
+	ExpenseApproved         = "Expense approved successfully"
+	ExpenseRejected         = "Expense rejected successfully"
+	ExpenseHistoryFetched   = "Expense history retrieved successfully"
+	ExpensePaymentRetried   = "Expense payment retry scheduled"
+	ExpenseUpdated          = "Expense updated successfully"
+	ExpenseCancelled        = "Expense cancelled successfully"
+	ExpenseResubmitted      = "Expense resubmitted successfully"
+	ExpenseCategoryListed   = "Expense categories retrieved successfully"
+	ExpenseCategoryFetched  = "Expense category retrieved successfully"
+	ExpenseCategoryCreated  = "Expense category created successfully"
+	ExpenseCategoryUpdated  = "Expense category updated successfully"
+	ExpenseCategoryDeleted  = "Expense category deleted successfully"
+	ExpenseReceiptUploaded  = "Receipt uploaded successfully"
+	ExpenseReceiptsListed   = "Receipts retrieved successfully"
+	BudgetsFetched          = "Budgets retrieved successfully"
+	ReportSummaryFetched    = "Report summary retrieved successfully"
+	PaymentWebhookProcessed = "Payment webhook processed successfully"
+	PaymentWebhookReplayed  = "Payment webhook was already processed"
+	BankAccountFetched      = "Bank account retrieved successfully"
+	BankAccountUpdated      = "Bank account updated successfully"
+	PayoutBatchesListed     = "Payout batches retrieved successfully"
+	PayoutBatchFetched      = "Payout batch retrieved successfully"
+	PayoutBatchCreated      = "Payout batch created successfully"
+	PayoutBatchApproved     = "Payout batch approved and submitted"
 )
@@ -1,11 +1,31 @@
 package migrations
 
 import (
+	"go-expense-management-system/internal/constants"
 	"go-expense-management-system/internal/entity"
 
 	"gorm.io/gorm"
 )
 
 func Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(&entity.User{}, &entity.Expense{}, &entity.Approval{}, &entity.ExpenseStatusHistory{})
+	if err := db.AutoMigrate(&entity.Department{}, &entity.User{}, &entity.Budget{}, &entity.ExpenseCategory{}, &entity.Expense{}, &entity.ExpenseReceipt{}, &entity.Approval{}, &entity.ExpenseStatusHistory{}, &entity.UserBankAccount{}, &entity.PaymentJob{}, &entity.Payment{}, &entity.PaymentWebhookEvent{}, &entity.PayoutBatch{}, &entity.PayoutBatchItem{}, &entity.IdempotencyKey{}); err != nil {
+		return err
+	}
+
+	if err := createExpenseSearchIndex(db); err != nil {
+		return err
+	}
+
+	return backfillRequiresApproval(db)
+}
+
+func createExpenseSearchIndex(db *gorm.DB) error {
+	return db.Exec("CREATE INDEX IF NOT EXISTS idx_expenses_description_search ON expenses USING GIN (to_tsvector('simple', description))").Error
+}
+
+func backfillRequiresApproval(db *gorm.DB) error {
+	return db.Model(&entity.Expense{}).
+		Where("requires_approval = ?", false).
+		Where("status = ? OR EXISTS (SELECT 1 FROM approvals WHERE approvals.expense_id = expenses.id)", constants.ExpenseStatusAwaitingApproval).
+		Update("requires_approval", true).Error
 }
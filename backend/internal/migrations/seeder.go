@@ -14,7 +14,11 @@ import (
 func Seeder(db *gorm.DB, logger *logrus.Logger) error {
 	logger.Info("Seeding database...")
 
+	seedFromJSON("internal/migrations/json/departments.json", &[]entity.Department{}, db, logger)
 	seedFromJSON("internal/migrations/json/users.json", &[]entity.User{}, db, logger)
+	seedFromJSON("internal/migrations/json/user_bank_accounts.json", &[]entity.UserBankAccount{}, db, logger)
+	seedFromJSON("internal/migrations/json/budgets.json", &[]entity.Budget{}, db, logger)
+	seedFromJSON("internal/migrations/json/expense_categories.json", &[]entity.ExpenseCategory{}, db, logger)
 	seedFromJSON("internal/migrations/json/expenses.json", &[]entity.Expense{}, db, logger)
 	seedFromJSON("internal/migrations/json/approvals.json", &[]entity.Approval{}, db, logger)
 	seedFromJSON("internal/migrations/json/expense_status_histories.json", &[]entity.ExpenseStatusHistory{}, db, logger)
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BankAccountResponse struct {
+	ID                uuid.UUID  `json:"id"`
+	UserID            uuid.UUID  `json:"user_id"`
+	BankCode          string     `json:"bank_code"`
+	AccountNumber     string     `json:"account_number"`
+	AccountHolderName string     `json:"account_holder_name"`
+	IsVerified        bool       `json:"is_verified"`
+	VerifiedAt        *time.Time `json:"verified_at,omitempty"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+type UpdateBankAccountRequest struct {
+	BankCode          string `json:"bank_code" validate:"required,alphanum,max=20"`
+	AccountNumber     string `json:"account_number" validate:"required,numeric,min=5,max=34"`
+	AccountHolderName string `json:"account_holder_name" validate:"required,max=100"`
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BudgetStatusResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	Scope        string     `json:"scope"`
+	DepartmentID *uuid.UUID `json:"department_id,omitempty"`
+	Period       string     `json:"period"`
+	PeriodStart  time.Time  `json:"period_start"`
+	PeriodEnd    time.Time  `json:"period_end"`
+	Enforcement  string     `json:"enforcement"`
+	LimitIDR     int64      `json:"limit_idr"`
+	SpentIDR     int64      `json:"spent_idr"`
+	PendingIDR   int64      `json:"pending_idr"`
+	RemainingIDR int64      `json:"remaining_idr"`
+}
@@ -0,0 +1,19 @@
+package converter
+
+import (
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/model"
+)
+
+func BankAccountToResponse(account *entity.UserBankAccount) *model.BankAccountResponse {
+	return &model.BankAccountResponse{
+		ID:                account.ID,
+		UserID:            account.UserID,
+		BankCode:          account.BankCode,
+		AccountNumber:     account.AccountNumber,
+		AccountHolderName: account.AccountHolderName,
+		IsVerified:        account.IsVerified,
+		VerifiedAt:        account.VerifiedAt,
+		UpdatedAt:         account.UpdatedAt,
+	}
+}
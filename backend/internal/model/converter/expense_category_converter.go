@@ -0,0 +1,21 @@
+package converter
+
+import (
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/model"
+)
+
+func ExpenseCategoryToResponse(category *entity.ExpenseCategory) *model.ExpenseCategoryResponse {
+	return &model.ExpenseCategoryResponse{
+		ID:                      category.ID,
+		Code:                    category.Code,
+		Name:                    category.Name,
+		MinAmountIDR:            category.MinAmountIDR,
+		MaxAmountIDR:            category.MaxAmountIDR,
+		ApprovalThresholdIDR:    category.ApprovalThresholdIDR,
+		ReceiptRequiredAboveIDR: category.ReceiptRequiredAboveIDR,
+		IsActive:                category.IsActive,
+		CreatedAt:               category.CreatedAt,
+		UpdatedAt:               category.UpdatedAt,
+	}
+}
@@ -9,16 +9,19 @@ import (
 
 func ExpenseToResponse(expense *entity.Expense, includeUserID bool) *model.ExpenseResponse {
 	response := &model.ExpenseResponse{
-		ID:                 expense.ID,
-		AmountIDR:          expense.AmountIDR,
-		AmountIDRFormatted: utils.FormatIDR(expense.AmountIDR),
-		Description:        expense.Description,
-		ReceiptURL:         expense.ReceiptURL,
-		Status:             expense.Status,
-		RequiresApproval:   expense.AmountIDR >= constants.ApprovalThreshold,
-		AutoApproved:       expense.AmountIDR < constants.ApprovalThreshold && expense.Status != constants.ExpenseStatusAwaitingApproval,
-		SubmittedAt:        expense.SubmittedAt,
-		ProcessedAt:        expense.ProcessedAt,
+		ID:                  expense.ID,
+		CategoryID:          expense.CategoryID,
+		AmountIDR:           expense.AmountIDR,
+		AmountIDRFormatted:  utils.FormatIDR(expense.AmountIDR),
+		Description:         expense.Description,
+		ReceiptURL:          expense.ReceiptURL,
+		Status:              expense.Status,
+		RequiresApproval:    expense.RequiresApproval,
+		PossibleDuplicateOf: expense.PossibleDuplicateOf,
+		AutoApproved:        !expense.RequiresApproval && expense.Status != constants.ExpenseStatusAwaitingApproval,
+		Version:             expense.Version,
+		SubmittedAt:         expense.SubmittedAt,
+		ProcessedAt:         expense.ProcessedAt,
 	}
 
 	if includeUserID {
@@ -31,12 +34,17 @@ func ExpenseToResponse(expense *entity.Expense, includeUserID bool) *model.Expen
 
 func ApprovalToResponse(approval *entity.Approval) model.ApprovalResponse {
 	return model.ApprovalResponse{
-		ID:         approval.ID,
-		ExpenseID:  approval.ExpenseID,
-		ApproverID: approval.ApproverID,
-		Status:     approval.Status,
-		Notes:      approval.Notes,
-		CreatedAt:  approval.CreatedAt,
+		ID:              approval.ID,
+		ExpenseID:       approval.ExpenseID,
+		ApproverID:      approval.ApproverID,
+		Step:            approval.Step,
+		RequiredRole:    approval.RequiredRole,
+		Status:          approval.Status,
+		Notes:           approval.Notes,
+		DecidedAt:       approval.DecidedAt,
+		EscalationLevel: approval.EscalationLevel,
+		EscalatedAt:     approval.EscalatedAt,
+		CreatedAt:       approval.CreatedAt,
 	}
 }
 
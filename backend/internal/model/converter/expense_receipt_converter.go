@@ -0,0 +1,19 @@
+package converter
+
+import (
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/model"
+)
+
+func ExpenseReceiptToResponse(receipt *entity.ExpenseReceipt) *model.ExpenseReceiptResponse {
+	return &model.ExpenseReceiptResponse{
+		ID:          receipt.ID,
+		ExpenseID:   receipt.ExpenseID,
+		UploadedBy:  receipt.UploadedBy,
+		FileName:    receipt.FileName,
+		ContentType: receipt.ContentType,
+		SizeBytes:   receipt.SizeBytes,
+		SHA256:      receipt.SHA256,
+		CreatedAt:   receipt.CreatedAt,
+	}
+}
@@ -0,0 +1,28 @@
+package converter
+
+import (
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/model"
+)
+
+func PaymentJobToModel(job *entity.PaymentJob) model.PaymentJob {
+	return model.PaymentJob{
+		ExpenseID:  job.ExpenseID,
+		AmountIDR:  job.AmountIDR,
+		ExternalID: job.ExternalID,
+	}
+}
+
+func PaymentToResponse(payment *entity.Payment) *model.ExpensePaymentResponse {
+	return &model.ExpensePaymentResponse{
+		ID:                payment.ID,
+		ProviderPaymentID: payment.ProviderPaymentID,
+		ExternalID:        payment.ExternalID,
+		AmountIDR:         payment.AmountIDR,
+		ProviderStatus:    payment.ProviderStatus,
+		Attempts:          payment.Attempts,
+		LastError:         payment.LastError,
+		RequestedAt:       payment.RequestedAt,
+		RespondedAt:       payment.RespondedAt,
+	}
+}
@@ -0,0 +1,52 @@
+package converter
+
+import (
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/model"
+	"strings"
+)
+
+func PayoutBatchToResponse(batch *entity.PayoutBatch) *model.PayoutBatchResponse {
+	response := &model.PayoutBatchResponse{
+		ID:              batch.ID,
+		Status:          batch.Status,
+		ItemCount:       batch.ItemCount,
+		TotalAmountIDR:  batch.TotalAmountIDR,
+		CreatedBy:       batch.CreatedBy,
+		ApprovedBy:      batch.ApprovedBy,
+		ApprovedAt:      batch.ApprovedAt,
+		SubmittedAt:     batch.SubmittedAt,
+		ProviderBatchID: batch.ProviderBatchID,
+		LastError:       batch.LastError,
+		CompletedAt:     batch.CompletedAt,
+		CreatedAt:       batch.CreatedAt,
+		UpdatedAt:       batch.UpdatedAt,
+	}
+	for i := range batch.Items {
+		response.Items = append(response.Items, PayoutBatchItemToResponse(&batch.Items[i]))
+	}
+	return response
+}
+
+func PayoutBatchItemToResponse(item *entity.PayoutBatchItem) model.PayoutBatchItemResponse {
+	return model.PayoutBatchItemResponse{
+		ID:                item.ID,
+		ExpenseID:         item.ExpenseID,
+		UserID:            item.UserID,
+		AmountIDR:         item.AmountIDR,
+		BankCode:          item.BankCode,
+		AccountNumber:     MaskAccountNumber(item.AccountNumber),
+		AccountHolderName: item.AccountHolderName,
+		Status:            item.Status,
+		ProviderPaymentID: item.ProviderPaymentID,
+		ProviderStatus:    item.ProviderStatus,
+		FailureReason:     item.FailureReason,
+	}
+}
+
+func MaskAccountNumber(number string) string {
+	if len(number) <= 4 {
+		return number
+	}
+	return strings.Repeat("*", len(number)-4) + number[len(number)-4:]
+}
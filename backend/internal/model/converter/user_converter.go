@@ -8,10 +8,11 @@ import (
 func UserToResponse(user *entity.User) *model.UserResponse {
 	id := user.ID
 	return &model.UserResponse{
-		ID:    &id,
-		Name:  user.Name,
-		Email: user.Email,
-		Role:  user.Role,
+		ID:        &id,
+		Name:      user.Name,
+		Email:     user.Email,
+		Role:      user.Role,
+		ManagerID: user.ManagerID,
 	}
 }
 
@@ -22,6 +23,7 @@ func UserToLoginResponse(user *entity.User, accessToken string) *model.UserRespo
 		Name:        user.Name,
 		Email:       user.Email,
 		Role:        user.Role,
+		ManagerID:   user.ManagerID,
 		AccessToken: accessToken,
 	}
 }
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateExpenseCategoryRequest struct {
+	Code                    string `json:"code" validate:"required,max=50"`
+	Name                    string `json:"name" validate:"required,max=100"`
+	MinAmountIDR            int64  `json:"min_amount_idr" validate:"required,gt=0"`
+	MaxAmountIDR            int64  `json:"max_amount_idr" validate:"required,gtefield=MinAmountIDR"`
+	ApprovalThresholdIDR    int64  `json:"approval_threshold_idr" validate:"required,gt=0"`
+	ReceiptRequiredAboveIDR *int64 `json:"receipt_required_above_idr,omitempty" validate:"omitempty,gte=0"`
+	IsActive                *bool  `json:"is_active,omitempty"`
+}
+
+type UpdateExpenseCategoryRequest struct {
+	ID                      uuid.UUID `json:"-" validate:"required"`
+	Code                    string    `json:"code" validate:"required,max=50"`
+	Name                    string    `json:"name" validate:"required,max=100"`
+	MinAmountIDR            int64     `json:"min_amount_idr" validate:"required,gt=0"`
+	MaxAmountIDR            int64     `json:"max_amount_idr" validate:"required,gtefield=MinAmountIDR"`
+	ApprovalThresholdIDR    int64     `json:"approval_threshold_idr" validate:"required,gt=0"`
+	ReceiptRequiredAboveIDR *int64    `json:"receipt_required_above_idr,omitempty" validate:"omitempty,gte=0"`
+	IsActive                *bool     `json:"is_active,omitempty"`
+}
+
+type ExpenseCategoryResponse struct {
+	ID                      uuid.UUID `json:"id"`
+	Code                    string    `json:"code"`
+	Name                    string    `json:"name"`
+	MinAmountIDR            int64     `json:"min_amount_idr"`
+	MaxAmountIDR            int64     `json:"max_amount_idr"`
+	ApprovalThresholdIDR    int64     `json:"approval_threshold_idr"`
+	ReceiptRequiredAboveIDR *int64    `json:"receipt_required_above_idr,omitempty"`
+	IsActive                bool      `json:"is_active"`
+	CreatedAt               time.Time `json:"created_at"`
+	UpdatedAt               time.Time `json:"updated_at"`
+}
@@ -7,41 +7,92 @@ import (
 )
 
 type CreateExpenseRequest struct {
-	AmountIDR   int64  `json:"amount_idr" validate:"required"`
-	Description string `json:"description" validate:"required"`
-	ReceiptURL  string `json:"receipt_url,omitempty" validate:"omitempty"`
+	CategoryID  uuid.UUID `json:"category_id" validate:"required"`
+	AmountIDR   int64     `json:"amount_idr" validate:"required"`
+	Description string    `json:"description" validate:"required"`
+	ReceiptURL  string    `json:"receipt_url,omitempty" validate:"omitempty,url,max=2048"`
+}
+
+type UpdateExpenseRequest struct {
+	ID              uuid.UUID  `json:"-" validate:"required"`
+	CategoryID      *uuid.UUID `json:"category_id,omitempty"`
+	AmountIDR       *int64     `json:"amount_idr,omitempty" validate:"omitempty,gt=0"`
+	Description     *string    `json:"description,omitempty" validate:"omitempty,max=1000"`
+	ReceiptURL      *string    `json:"receipt_url,omitempty" validate:"omitempty,url,max=2048"`
+	ExpectedVersion *int64     `json:"-"`
+}
+
+type ExpenseActionRequest struct {
+	Notes           string `json:"notes,omitempty" validate:"max=500"`
+	ExpectedVersion *int64 `json:"-"`
 }
 
 type ExpenseResponse struct {
-	ID                 uuid.UUID  `json:"id"`
-	UserID             *uuid.UUID `json:"user_id,omitempty"`
-	AmountIDR          int64      `json:"amount_idr"`
-	AmountIDRFormatted string     `json:"amount_idr_formatted,omitempty"`
-	Description        string     `json:"description"`
-	ReceiptURL         string     `json:"receipt_url,omitempty"`
-	Status             string     `json:"status"`
-	RequiresApproval   bool       `json:"requires_approval"`
-	AutoApproved       bool       `json:"auto_approved"`
-	SubmittedAt        time.Time  `json:"submitted_at"`
-	ProcessedAt        *time.Time `json:"processed_at,omitempty"`
+	ID                  uuid.UUID  `json:"id"`
+	UserID              *uuid.UUID `json:"user_id,omitempty"`
+	CategoryID          *uuid.UUID `json:"category_id,omitempty"`
+	AmountIDR           int64      `json:"amount_idr"`
+	AmountIDRFormatted  string     `json:"amount_idr_formatted,omitempty"`
+	Description         string     `json:"description"`
+	ReceiptURL          string     `json:"receipt_url,omitempty"`
+	Status              string     `json:"status"`
+	RequiresApproval    bool       `json:"requires_approval"`
+	PossibleDuplicateOf *uuid.UUID `json:"possible_duplicate_of,omitempty"`
+	AutoApproved        bool       `json:"auto_approved"`
+	Version             int64      `json:"version"`
+	SubmittedAt         time.Time  `json:"submitted_at"`
+	ProcessedAt         *time.Time `json:"processed_at,omitempty"`
+}
+
+type ListExpenseRequest struct {
+	Statuses          []string
+	UserID            *uuid.UUID
+	CategoryID        *uuid.UUID
+	SubmittedFrom     *time.Time
+	SubmittedTo       *time.Time
+	MinAmountIDR      *int64
+	MaxAmountIDR      *int64
+	Query             string
+	Sort              string
+	PossibleDuplicate bool
+	Page              int
+	Size              int
+	Cursor            *string
+	IncludeTotal      *bool
+}
+
+type ListHistoryRequest struct {
+	Cursor       *string
+	Size         int
+	IncludeTotal bool
 }
 
 type ExpenseDetailResponse struct {
 	ExpenseResponse
-	Approvals []ApprovalResponse `json:"approvals,omitempty"`
+	PossibleDuplicate *ExpenseResponse         `json:"possible_duplicate,omitempty"`
+	Category          *ExpenseCategoryResponse `json:"category,omitempty"`
+	Approvals         []ApprovalResponse       `json:"approvals,omitempty"`
+	Receipts          []ExpenseReceiptResponse `json:"receipts,omitempty"`
+	Payment           *ExpensePaymentResponse  `json:"payment,omitempty"`
 }
 
 type ApprovalResponse struct {
-	ID         uuid.UUID `json:"id"`
-	ExpenseID  uuid.UUID `json:"expense_id"`
-	ApproverID uuid.UUID `json:"approver_id"`
-	Status     string    `json:"status"`
-	Notes      string    `json:"notes,omitempty"`
-	CreatedAt  time.Time `json:"created_at"`
+	ID              uuid.UUID  `json:"id"`
+	ExpenseID       uuid.UUID  `json:"expense_id"`
+	ApproverID      *uuid.UUID `json:"approver_id,omitempty"`
+	Step            int        `json:"step"`
+	RequiredRole    string     `json:"required_role"`
+	Status          string     `json:"status"`
+	Notes           string     `json:"notes,omitempty"`
+	DecidedAt       *time.Time `json:"decided_at,omitempty"`
+	EscalationLevel int        `json:"escalation_level"`
+	EscalatedAt     *time.Time `json:"escalated_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
 }
 
 type ApproveExpenseRequest struct {
-	Notes string `json:"notes,omitempty" validate:"max=500"`
+	Notes           string `json:"notes,omitempty" validate:"max=500"`
+	ExpectedVersion *int64 `json:"-"`
 }
 
 type ExpenseStatusHistoryResponse struct {
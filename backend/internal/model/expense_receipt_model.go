@@ -0,0 +1,33 @@
+package model
+
+import (
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UploadReceiptRequest struct {
+	ExpenseID uuid.UUID
+	FileName  string
+	Size      int64
+	Content   io.Reader
+}
+
+type ReceiptDownload struct {
+	FileName    string
+	ContentType string
+	SizeBytes   int64
+	Content     io.ReadCloser
+}
+
+type ExpenseReceiptResponse struct {
+	ID          uuid.UUID `json:"id"`
+	ExpenseID   uuid.UUID `json:"expense_id"`
+	UploadedBy  uuid.UUID `json:"uploaded_by"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	SHA256      string    `json:"sha256"`
+	CreatedAt   time.Time `json:"created_at"`
+}
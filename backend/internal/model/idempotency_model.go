@@ -0,0 +1,19 @@
+package model
+
+import "github.com/google/uuid"
+
+type IdempotencyRequest struct {
+	UserID      uuid.UUID
+	Key         string
+	Method      string
+	Path        string
+	RequestHash string
+}
+
+type IdempotentResponse struct {
+	ID         uuid.UUID
+	Replayed   bool
+	StatusCode int
+	Headers    map[string]string
+	Body       []byte
+}
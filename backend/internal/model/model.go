@@ -8,10 +8,12 @@ type WebResponse[T any] struct {
 }
 
 type PageMetadata struct {
-	CurrentPage int   `json:"current_page"`
-	PageSize    int   `json:"page_size"`
-	TotalItem   int64 `json:"total_item"`
-	TotalPage   int64 `json:"total_page"`
-	HasNext     bool  `json:"has_next"`
-	HasPrevious bool  `json:"has_previous"`
+	CurrentPage int    `json:"current_page,omitempty"`
+	PageSize    int    `json:"page_size"`
+	TotalItem   *int64 `json:"total_item,omitempty"`
+	TotalPage   *int64 `json:"total_page,omitempty"`
+	HasNext     bool   `json:"has_next"`
+	HasPrevious bool   `json:"has_previous"`
+	NextCursor  string `json:"next_cursor,omitempty"`
+	PrevCursor  string `json:"prev_cursor,omitempty"`
 }
@@ -1,16 +1,37 @@
 package model
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type PaymentRequest struct {
-	Amount     int64  `json:"amount"`
-	ExternalID string `json:"external_id"`
+	Amount            int64  `json:"amount"`
+	ExternalID        string `json:"external_id"`
+	BankCode          string `json:"bank_code,omitempty"`
+	AccountNumber     string `json:"account_number,omitempty"`
+	AccountHolderName string `json:"account_holder_name,omitempty"`
+	Description       string `json:"description,omitempty"`
 }
 
 type PaymentResponse struct {
-	ID         string `json:"id"`
-	ExternalID string `json:"external_id"`
-	Status     string `json:"status"`
+	ID            string `json:"id"`
+	ExternalID    string `json:"external_id"`
+	Status        string `json:"status"`
+	FailureReason string `json:"failure_reason,omitempty"`
+}
+
+type BulkPaymentRequest struct {
+	ExternalID string           `json:"external_id"`
+	Items      []PaymentRequest `json:"items"`
+}
+
+type BulkPaymentResponse struct {
+	ID         string            `json:"id"`
+	ExternalID string            `json:"external_id"`
+	Status     string            `json:"status"`
+	Items      []PaymentResponse `json:"items"`
 }
 
 type PaymentJob struct {
@@ -18,3 +39,47 @@ type PaymentJob struct {
 	AmountIDR  int64
 	ExternalID string
 }
+
+type ExpensePaymentResponse struct {
+	ID                uuid.UUID  `json:"id"`
+	ProviderPaymentID string     `json:"provider_payment_id,omitempty"`
+	ExternalID        string     `json:"external_id"`
+	AmountIDR         int64      `json:"amount_idr"`
+	ProviderStatus    string     `json:"provider_status,omitempty"`
+	Attempts          int        `json:"attempts"`
+	LastError         string     `json:"last_error,omitempty"`
+	RequestedAt       *time.Time `json:"requested_at,omitempty"`
+	RespondedAt       *time.Time `json:"responded_at,omitempty"`
+}
+
+type PaymentWebhookRequest struct {
+	Signature string
+	Timestamp string
+	Payload   []byte
+}
+
+type PaymentWebhookEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		ID            string `json:"id"`
+		ExternalID    string `json:"external_id"`
+		Status        string `json:"status"`
+		FailureReason string `json:"failure_reason"`
+	} `json:"data"`
+}
+
+type PaymentWebhookResponse struct {
+	EventID   string    `json:"event_id"`
+	ExpenseID uuid.UUID `json:"expense_id"`
+	Status    string    `json:"status"`
+	Replayed  bool      `json:"replayed"`
+}
+
+type PaymentReconcileResult struct {
+	Checked   int
+	Completed int
+	Failed    int
+	Pending   int
+	Errors    int
+}
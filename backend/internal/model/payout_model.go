@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PayoutBatchResponse struct {
+	ID              uuid.UUID                 `json:"id"`
+	Status          string                    `json:"status"`
+	ItemCount       int                       `json:"item_count"`
+	TotalAmountIDR  int64                     `json:"total_amount_idr"`
+	CreatedBy       *uuid.UUID                `json:"created_by,omitempty"`
+	ApprovedBy      *uuid.UUID                `json:"approved_by,omitempty"`
+	ApprovedAt      *time.Time                `json:"approved_at,omitempty"`
+	SubmittedAt     *time.Time                `json:"submitted_at,omitempty"`
+	ProviderBatchID string                    `json:"provider_batch_id,omitempty"`
+	LastError       string                    `json:"last_error,omitempty"`
+	CompletedAt     *time.Time                `json:"completed_at,omitempty"`
+	CreatedAt       time.Time                 `json:"created_at"`
+	UpdatedAt       time.Time                 `json:"updated_at"`
+	Items           []PayoutBatchItemResponse `json:"items,omitempty"`
+}
+
+type PayoutBatchItemResponse struct {
+	ID                uuid.UUID `json:"id"`
+	ExpenseID         uuid.UUID `json:"expense_id"`
+	UserID            uuid.UUID `json:"user_id"`
+	AmountIDR         int64     `json:"amount_idr"`
+	BankCode          string    `json:"bank_code"`
+	AccountNumber     string    `json:"account_number"`
+	AccountHolderName string    `json:"account_holder_name"`
+	Status            string    `json:"status"`
+	ProviderPaymentID string    `json:"provider_payment_id,omitempty"`
+	ProviderStatus    string    `json:"provider_status,omitempty"`
+	FailureReason     string    `json:"failure_reason,omitempty"`
+}
+
+type ListPayoutBatchRequest struct {
+	Status string
+	Page   int
+	Size   int
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+type ReportSummaryRequest struct {
+	From *time.Time
+	To   *time.Time
+}
+
+type ReportSummaryResponse struct {
+	From                    *time.Time             `json:"from,omitempty"`
+	To                      *time.Time             `json:"to,omitempty"`
+	TotalCount              int64                  `json:"total_count"`
+	TotalAmountIDR          int64                  `json:"total_amount_idr"`
+	TotalAmountIDRFormatted string                 `json:"total_amount_idr_formatted"`
+	ByStatus                []ReportGroupResponse  `json:"by_status"`
+	ByMonth                 []ReportGroupResponse  `json:"by_month"`
+	ByRequester             []ReportGroupResponse  `json:"by_requester"`
+	ByCategory              []ReportGroupResponse  `json:"by_category"`
+	ApprovalTurnaround      ReportDurationResponse `json:"approval_turnaround"`
+	PaymentCompletion       ReportDurationResponse `json:"payment_completion"`
+}
+
+type ReportGroupResponse struct {
+	Key                     string `json:"key"`
+	Label                   string `json:"label,omitempty"`
+	Count                   int64  `json:"count"`
+	TotalAmountIDR          int64  `json:"total_amount_idr"`
+	TotalAmountIDRFormatted string `json:"total_amount_idr_formatted"`
+}
+
+type ReportDurationResponse struct {
+	Count          int64   `json:"count"`
+	AverageSeconds float64 `json:"average_seconds"`
+	AverageHours   float64 `json:"average_hours"`
+}
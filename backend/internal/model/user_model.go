@@ -9,6 +9,7 @@ type UserResponse struct {
 	Name        string     `json:"name,omitempty"`
 	Email       string     `json:"email,omitempty"`
 	Role        string     `json:"role,omitempty"`
+	ManagerID   *uuid.UUID `json:"manager_id,omitempty"`
 	AccessToken string     `json:"access_token,omitempty"`
 }
 
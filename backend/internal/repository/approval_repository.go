@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"go-expense-management-system/internal/constants"
 	"go-expense-management-system/internal/entity"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
@@ -21,8 +23,68 @@ func NewApprovalRepository(log *logrus.Logger) *ApprovalRepository {
 
 func (r *ApprovalRepository) ListByExpenseID(db *gorm.DB, expenseID uuid.UUID) ([]entity.Approval, error) {
 	var approvals []entity.Approval
-	if err := db.Where("expense_id = ?", expenseID).Order("created_at asc").Find(&approvals).Error; err != nil {
+	if err := db.Where("expense_id = ?", expenseID).Order("step asc, created_at asc").Find(&approvals).Error; err != nil {
 		return nil, err
 	}
 	return approvals, nil
 }
+
+func (r *ApprovalRepository) FindCurrentStep(db *gorm.DB, approval *entity.Approval, expenseID uuid.UUID) error {
+	return db.Where("expense_id = ? AND status = ?", expenseID, constants.ApprovalStatusPending).
+		Order("step asc").
+		Take(approval).Error
+}
+
+func (r *ApprovalRepository) NextStepNumber(db *gorm.DB, expenseID uuid.UUID) (int, error) {
+	var step int
+	err := db.Model(&entity.Approval{}).
+		Where("expense_id = ?", expenseID).
+		Select("COALESCE(MAX(step), 0)").
+		Scan(&step).Error
+	return step + 1, err
+}
+
+func (r *ApprovalRepository) Activate(db *gorm.DB, approval *entity.Approval, at time.Time) error {
+	approval.ActivatedAt = &at
+	return db.Model(approval).Update("activated_at", at).Error
+}
+
+func (r *ApprovalRepository) ListEscalationDue(db *gorm.DB, cutoff time.Time, limit int) ([]entity.Approval, error) {
+	var approvals []entity.Approval
+	err := db.Model(&entity.Approval{}).
+		Select("approvals.*").
+		Joins("JOIN expenses ON expenses.id = approvals.expense_id").
+		Where("approvals.status = ? AND expenses.status = ?", constants.ApprovalStatusPending, constants.ExpenseStatusAwaitingApproval).
+		Where("approvals.activated_at IS NOT NULL AND COALESCE(approvals.escalated_at, approvals.activated_at) <= ?", cutoff).
+		Order("approvals.activated_at asc").
+		Limit(limit).
+		Find(&approvals).Error
+	if err != nil {
+		return nil, err
+	}
+	return approvals, nil
+}
+
+func (r *ApprovalRepository) MarkEscalated(db *gorm.DB, approval *entity.Approval, level int, at time.Time) (bool, error) {
+	result := db.Model(&entity.Approval{}).
+		Where("id = ? AND status = ? AND escalation_level = ?", approval.ID, constants.ApprovalStatusPending, approval.EscalationLevel).
+		Updates(map[string]any{
+			"escalation_level": level,
+			"escalated_at":     at,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	approval.EscalationLevel = level
+	approval.EscalatedAt = &at
+	return true, nil
+}
+
+func (r *ApprovalRepository) SkipPending(db *gorm.DB, expenseID uuid.UUID) error {
+	return db.Model(&entity.Approval{}).
+		Where("expense_id = ? AND status = ?", expenseID, constants.ApprovalStatusPending).
+		Update("status", constants.ApprovalStatusSkipped).Error
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var BudgetSpentStatuses = []string{
+	constants.ExpenseStatusApproved,
+	constants.ExpenseStatusAutoApproved,
+	constants.ExpenseStatusPaymentProcessing,
+	constants.ExpenseStatusPaymentFailed,
+	constants.ExpenseStatusCompleted,
+}
+
+type BudgetRepository struct {
+	Repository[entity.Budget]
+	Log *logrus.Logger
+}
+
+type BudgetUsageFilter struct {
+	Budget           *entity.Budget
+	From             time.Time
+	To               time.Time
+	ExcludeExpenseID *uuid.UUID
+}
+
+type BudgetUsage struct {
+	SpentIDR   int64 `gorm:"column:spent_idr"`
+	PendingIDR int64 `gorm:"column:pending_idr"`
+}
+
+func NewBudgetRepository(log *logrus.Logger) *BudgetRepository {
+	return &BudgetRepository{
+		Log: log,
+	}
+}
+
+func (r *BudgetRepository) ListActiveForUser(db *gorm.DB, userID uuid.UUID, departmentID *uuid.UUID, lock bool) ([]entity.Budget, error) {
+	budgets := make([]entity.Budget, 0)
+	query := db.Model(&entity.Budget{}).Where("is_active = ?", true)
+	if departmentID != nil {
+		query = query.Where("(user_id = ? OR department_id = ?)", userID, *departmentID)
+	} else {
+		query = query.Where("user_id = ?", userID)
+	}
+	if lock {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	if err := query.Order("department_id nulls first, period asc").Find(&budgets).Error; err != nil {
+		return nil, err
+	}
+	return budgets, nil
+}
+
+func (r *BudgetRepository) Usage(db *gorm.DB, filter BudgetUsageFilter) (BudgetUsage, error) {
+	var usage BudgetUsage
+
+	query := db.Model(&entity.Expense{}).
+		Select(
+			"COALESCE(SUM(CASE WHEN status IN ? THEN amount_id_r ELSE 0 END), 0) AS spent_idr, "+
+				"COALESCE(SUM(CASE WHEN status = ? THEN amount_id_r ELSE 0 END), 0) AS pending_idr",
+			BudgetSpentStatuses, constants.ExpenseStatusAwaitingApproval,
+		).
+		Where("submitted_at >= ? AND submitted_at < ?", filter.From, filter.To)
+
+	if filter.Budget.UserID != nil {
+		query = query.Where("user_id = ?", *filter.Budget.UserID)
+	} else {
+		query = query.Where("user_id IN (SELECT id FROM users WHERE department_id = ?)", *filter.Budget.DepartmentID)
+	}
+	if filter.ExcludeExpenseID != nil {
+		query = query.Where("id <> ?", *filter.ExcludeExpenseID)
+	}
+
+	if err := query.Scan(&usage).Error; err != nil {
+		return BudgetUsage{}, err
+	}
+	return usage, nil
+}
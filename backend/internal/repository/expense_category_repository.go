@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"go-expense-management-system/internal/entity"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ExpenseCategoryRepository struct {
+	Repository[entity.ExpenseCategory]
+	Log *logrus.Logger
+}
+
+func NewExpenseCategoryRepository(log *logrus.Logger) *ExpenseCategoryRepository {
+	return &ExpenseCategoryRepository{
+		Log: log,
+	}
+}
+
+func (r *ExpenseCategoryRepository) List(db *gorm.DB, includeInactive bool) ([]entity.ExpenseCategory, error) {
+	categories := make([]entity.ExpenseCategory, 0)
+	query := db.Model(&entity.ExpenseCategory{})
+	if !includeInactive {
+		query = query.Where("is_active = ?", true)
+	}
+	if err := query.Order("name asc").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ExpenseReceiptRepository struct {
+	Repository[entity.ExpenseReceipt]
+	Log *logrus.Logger
+}
+
+func NewExpenseReceiptRepository(log *logrus.Logger) *ExpenseReceiptRepository {
+	return &ExpenseReceiptRepository{
+		Log: log,
+	}
+}
+
+func (r *ExpenseReceiptRepository) ListByExpenseID(db *gorm.DB, expenseID uuid.UUID) ([]entity.ExpenseReceipt, error) {
+	var receipts []entity.ExpenseReceipt
+	if err := db.Where("expense_id = ?", expenseID).Order("created_at asc").Find(&receipts).Error; err != nil {
+		return nil, err
+	}
+	return receipts, nil
+}
+
+func (r *ExpenseReceiptRepository) FindByExpenseID(db *gorm.DB, receipt *entity.ExpenseReceipt, expenseID, receiptID uuid.UUID) error {
+	return db.Where("id = ? AND expense_id = ?", receiptID, expenseID).Take(receipt).Error
+}
+
+func (r *ExpenseReceiptRepository) CountByExpenseID(db *gorm.DB, expenseID uuid.UUID) (int64, error) {
+	return r.CountByCondition(db, "expense_id = ?", expenseID)
+}
+
+func (r *ExpenseReceiptRepository) FindDuplicateExpenseID(db *gorm.DB, receipt *entity.ExpenseReceipt, userID uuid.UUID, since time.Time) (*uuid.UUID, error) {
+	var expenseIDs []uuid.UUID
+	err := db.Model(&entity.ExpenseReceipt{}).
+		Joins("JOIN expenses ON expenses.id = expense_receipts.expense_id").
+		Where("expenses.user_id = ? AND expenses.status <> ?", userID, constants.ExpenseStatusCancelled).
+		Where("expense_receipts.sha256 = ? AND expense_receipts.expense_id <> ? AND expense_receipts.created_at >= ?", receipt.SHA256, receipt.ExpenseID, since).
+		Order("expense_receipts.created_at asc").
+		Limit(1).
+		Pluck("expense_receipts.expense_id", &expenseIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(expenseIDs) == 0 {
+		return nil, nil
+	}
+	return &expenseIDs[0], nil
+}
@@ -1,21 +1,63 @@
 package repository
 
 import (
+	"errors"
+	"go-expense-management-system/internal/constants"
 	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/utils"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+var ErrStaleVersion = errors.New("expense version is stale")
+
 type ExpenseRepository struct {
 	Repository[entity.Expense]
 	Log *logrus.Logger
 }
 
+var ExpenseSortColumns = map[string]string{
+	"submitted_at": "submitted_at",
+	"amount_idr":   "amount_id_r",
+	"status":       "status",
+	"processed_at": "processed_at",
+}
+
+type ExpenseSort struct {
+	Field string
+	Desc  bool
+}
+
 type ExpenseFilter struct {
-	UserID *uuid.UUID
-	Status *string
+	UserID            *uuid.UUID
+	Statuses          []string
+	CategoryID        *uuid.UUID
+	SubmittedFrom     *time.Time
+	SubmittedTo       *time.Time
+	MinAmountIDR      *int64
+	MaxAmountIDR      *int64
+	Search            string
+	PossibleDuplicate bool
+	Sort              []ExpenseSort
+}
+
+type ExpenseExportRow struct {
+	ID             uuid.UUID
+	RequesterName  string
+	RequesterEmail string
+	CategoryName   string
+	Description    string
+	AmountIDR      int64
+	Status         string
+	Approvers      string
+	ApprovalNotes  string
+	SubmittedAt    time.Time
+	ProcessedAt    *time.Time
+	PaymentRef     string
 }
 
 func NewExpenseRepository(log *logrus.Logger) *ExpenseRepository {
@@ -24,30 +66,198 @@ func NewExpenseRepository(log *logrus.Logger) *ExpenseRepository {
 	}
 }
 
-func (r *ExpenseRepository) List(db *gorm.DB, filter ExpenseFilter, page, size int) ([]entity.Expense, int64, error) {
-	var expenses []entity.Expense
+func (r *ExpenseRepository) List(db *gorm.DB, filter ExpenseFilter, page, size int) ([]entity.Expense, bool, error) {
+	expenses := make([]entity.Expense, 0, size+1)
+
+	query := applyExpenseFilter(db.Model(&entity.Expense{}), filter)
+	query = applyExpenseSort(query, filter.Sort).Offset((page - 1) * size).Limit(size + 1)
+	if err := query.Find(&expenses).Error; err != nil {
+		return nil, false, err
+	}
+
+	hasNext := len(expenses) > size
+	if hasNext {
+		expenses = expenses[:size]
+	}
+	return expenses, hasNext, nil
+}
+
+func (r *ExpenseRepository) ListByCursor(db *gorm.DB, filter ExpenseFilter, cursor *utils.Cursor, size int) ([]entity.Expense, bool, error) {
+	expenses := make([]entity.Expense, 0, size+1)
+
+	desc := len(filter.Sort) == 0 || filter.Sort[0].Desc
+	query := applyExpenseFilter(db.Model(&entity.Expense{}), filter)
+	query = applyKeyset(query, "expenses.submitted_at", "expenses.id", cursor, desc, size)
+	if err := query.Find(&expenses).Error; err != nil {
+		return nil, false, err
+	}
+
+	expenses, hasMore := trimKeysetPage(expenses, cursor, size)
+	return expenses, hasMore, nil
+}
+
+func (r *ExpenseRepository) StreamExport(db *gorm.DB, filter ExpenseFilter, fn func(row *ExpenseExportRow) error) error {
+	query := applyExpenseFilter(db.Model(&entity.Expense{}), filter).
+		Select(`expenses.id, users.name AS requester_name, users.email AS requester_email,
+			COALESCE(expense_categories.name, '') AS category_name, expenses.description,
+			expenses.amount_id_r, expenses.status, expenses.submitted_at, expenses.processed_at,
+			COALESCE(NULLIF(payments.provider_payment_id, ''), payment_jobs.external_id, '') AS payment_ref,
+			COALESCE((SELECT string_agg(approvers.name, ', ' ORDER BY approvals.step) FROM approvals
+				JOIN users approvers ON approvers.id = approvals.approver_id
+				WHERE approvals.expense_id = expenses.id AND approvals.decided_at IS NOT NULL), '') AS approvers,
+			COALESCE((SELECT string_agg(approvals.notes, '; ' ORDER BY approvals.step) FROM approvals
+				WHERE approvals.expense_id = expenses.id AND approvals.notes <> ''), '') AS approval_notes`).
+		Joins("JOIN users ON users.id = expenses.user_id").
+		Joins("LEFT JOIN expense_categories ON expense_categories.id = expenses.category_id").
+		Joins("LEFT JOIN payment_jobs ON payment_jobs.expense_id = expenses.id").
+		Joins("LEFT JOIN payments ON payments.expense_id = expenses.id")
+
+	rows, err := applyExpenseSort(query, filter.Sort).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row := new(ExpenseExportRow)
+		if err := db.ScanRows(rows, row); err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *ExpenseRepository) Count(db *gorm.DB, filter ExpenseFilter) (int64, error) {
 	var total int64
+	err := applyExpenseFilter(db.Model(&entity.Expense{}), filter).Count(&total).Error
+	return total, err
+}
 
-	query := db.Model(&entity.Expense{})
+func applyExpenseFilter(query *gorm.DB, filter ExpenseFilter) *gorm.DB {
 	if filter.UserID != nil {
-		query = query.Where("user_id = ?", *filter.UserID)
+		query = query.Where("expenses.user_id = ?", *filter.UserID)
 	}
-	if filter.Status != nil && *filter.Status != "" {
-		query = query.Where("status = ?", *filter.Status)
+	if len(filter.Statuses) > 0 {
+		query = query.Where("expenses.status IN ?", filter.Statuses)
+	}
+	if filter.CategoryID != nil {
+		query = query.Where("expenses.category_id = ?", *filter.CategoryID)
+	}
+	if filter.SubmittedFrom != nil {
+		query = query.Where("expenses.submitted_at >= ?", *filter.SubmittedFrom)
+	}
+	if filter.SubmittedTo != nil {
+		query = query.Where("expenses.submitted_at < ?", *filter.SubmittedTo)
+	}
+	if filter.MinAmountIDR != nil {
+		query = query.Where("expenses.amount_id_r >= ?", *filter.MinAmountIDR)
+	}
+	if filter.MaxAmountIDR != nil {
+		query = query.Where("expenses.amount_id_r <= ?", *filter.MaxAmountIDR)
+	}
+	if filter.Search != "" {
+		query = query.Where("to_tsvector('simple', expenses.description) @@ websearch_to_tsquery('simple', ?)", filter.Search)
+	}
+	if filter.PossibleDuplicate {
+		query = query.Where("expenses.possible_duplicate_of IS NOT NULL")
+	}
+	return query
+}
+
+func applyExpenseSort(query *gorm.DB, sorts []ExpenseSort) *gorm.DB {
+	if len(sorts) == 0 {
+		sorts = []ExpenseSort{{Field: "submitted_at", Desc: true}}
+	}
+
+	tieBreakerDesc := false
+	for _, sort := range sorts {
+		column, ok := ExpenseSortColumns[sort.Field]
+		if !ok {
+			continue
+		}
+		direction := "ASC NULLS LAST"
+		if sort.Desc {
+			direction = "DESC NULLS LAST"
+		}
+		query = query.Order("expenses." + column + " " + direction)
+		tieBreakerDesc = sort.Desc
 	}
 
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
+	if tieBreakerDesc {
+		return query.Order("expenses.id DESC")
 	}
+	return query.Order("expenses.id ASC")
+}
+
+func (r *ExpenseRepository) UpdateVersioned(db *gorm.DB, expense *entity.Expense) error {
+	current := expense.Version
+	expense.Version = current + 1
+
+	result := db.Model(expense).
+		Select("*").
+		Omit(clause.Associations).
+		Where("version = ?", current).
+		Updates(expense)
+	if result.Error != nil {
+		expense.Version = current
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		expense.Version = current
+		return ErrStaleVersion
+	}
+	return nil
+}
 
-	if size > 0 {
-		offset := (page - 1) * size
-		query = query.Offset(offset).Limit(size)
+// ListAutoApprovedSince lists the user's auto-approved expenses, across all categories
+// unless categoryID is set.
+func (r *ExpenseRepository) ListAutoApprovedSince(db *gorm.DB, userID uuid.UUID, categoryID *uuid.UUID, since time.Time) ([]entity.Expense, error) {
+	expenses := make([]entity.Expense, 0)
+	query := db.Where("user_id = ? AND requires_approval = ? AND submitted_at >= ?", userID, false, since)
+	if categoryID != nil {
+		query = query.Where("category_id = ?", *categoryID)
 	}
+	err := query.
+		Where("status IN ?", []string{
+			constants.ExpenseStatusAutoApproved,
+			constants.ExpenseStatusPaymentProcessing,
+			constants.ExpenseStatusPaymentFailed,
+			constants.ExpenseStatusCompleted,
+		}).
+		Order("submitted_at asc").
+		Find(&expenses).Error
+	if err != nil {
+		return nil, err
+	}
+	return expenses, nil
+}
 
-	if err := query.Order("submitted_at desc").Find(&expenses).Error; err != nil {
-		return nil, 0, err
+func (r *ExpenseRepository) ListDuplicateCandidates(db *gorm.DB, expense *entity.Expense, since time.Time) ([]entity.Expense, error) {
+	expenses := make([]entity.Expense, 0)
+	query := db.Where("user_id = ? AND submitted_at >= ? AND status <> ?", expense.UserID, since, constants.ExpenseStatusCancelled)
+	if expense.ID != uuid.Nil {
+		query = query.Where("id <> ?", expense.ID)
+	}
+	if expense.ReceiptURL != "" {
+		query = query.Where("(amount_id_r = ? OR receipt_url = ?)", expense.AmountIDR, expense.ReceiptURL)
+	} else {
+		query = query.Where("amount_id_r = ?", expense.AmountIDR)
 	}
+	if err := query.Order("submitted_at desc").Limit(50).Find(&expenses).Error; err != nil {
+		return nil, err
+	}
+	return expenses, nil
+}
 
-	return expenses, total, nil
+func (r *ExpenseRepository) MarkPossibleDuplicate(db *gorm.DB, expenseID uuid.UUID, duplicateOf uuid.UUID) (bool, error) {
+	result := db.Model(&entity.Expense{}).
+		Where("id = ? AND possible_duplicate_of IS NULL", expenseID).
+		Update("possible_duplicate_of", duplicateOf)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
 }
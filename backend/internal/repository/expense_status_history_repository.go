@@ -2,6 +2,7 @@ package repository
 
 import (
 	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/utils"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
@@ -26,3 +27,19 @@ func (r *ExpenseStatusHistoryRepository) ListByExpenseID(db *gorm.DB, expenseID
 	}
 	return histories, nil
 }
+
+func (r *ExpenseStatusHistoryRepository) ListByExpenseIDCursor(db *gorm.DB, expenseID uuid.UUID, cursor *utils.Cursor, size int) ([]entity.ExpenseStatusHistory, bool, error) {
+	histories := make([]entity.ExpenseStatusHistory, 0, size+1)
+
+	query := applyKeyset(db.Where("expense_id = ?", expenseID), "created_at", "id", cursor, false, size)
+	if err := query.Find(&histories).Error; err != nil {
+		return nil, false, err
+	}
+
+	histories, hasMore := trimKeysetPage(histories, cursor, size)
+	return histories, hasMore, nil
+}
+
+func (r *ExpenseStatusHistoryRepository) CountByExpenseID(db *gorm.DB, expenseID uuid.UUID) (int64, error) {
+	return r.CountByCondition(db, "expense_id = ?", expenseID)
+}
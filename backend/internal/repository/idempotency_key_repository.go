@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyKeyRepository struct {
+	Repository[entity.IdempotencyKey]
+	Log *logrus.Logger
+}
+
+func NewIdempotencyKeyRepository(log *logrus.Logger) *IdempotencyKeyRepository {
+	return &IdempotencyKeyRepository{
+		Log: log,
+	}
+}
+
+func (r *IdempotencyKeyRepository) Reserve(db *gorm.DB, record *entity.IdempotencyKey) (bool, error) {
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *IdempotencyKeyRepository) FindByUserAndKey(db *gorm.DB, record *entity.IdempotencyKey, userID uuid.UUID, key string) error {
+	return db.Where("user_id = ? AND key = ?", userID, key).Take(record).Error
+}
+
+func (r *IdempotencyKeyRepository) Complete(db *gorm.DB, id uuid.UUID, status int, headers string, body []byte) error {
+	return db.Model(&entity.IdempotencyKey{}).
+		Where("id = ? AND status = ?", id, constants.IdempotencyStatusProcessing).
+		Updates(map[string]any{
+			"status":           constants.IdempotencyStatusCompleted,
+			"response_status":  status,
+			"response_headers": headers,
+			"response_body":    body,
+		}).Error
+}
+
+func (r *IdempotencyKeyRepository) Release(db *gorm.DB, id uuid.UUID) error {
+	return db.Where("id = ? AND status = ?", id, constants.IdempotencyStatusProcessing).
+		Delete(&entity.IdempotencyKey{}).Error
+}
+
+func (r *IdempotencyKeyRepository) DeleteExpired(db *gorm.DB, userID uuid.UUID, now time.Time) error {
+	return db.Where("user_id = ? AND expires_at <= ?", userID, now).
+		Delete(&entity.IdempotencyKey{}).Error
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"fmt"
+	"go-expense-management-system/internal/utils"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+func applyKeyset(query *gorm.DB, timeColumn, idColumn string, cursor *utils.Cursor, desc bool, size int) *gorm.DB {
+	scanDesc := desc != (cursor != nil && cursor.Backward)
+
+	direction, operator := "ASC", ">"
+	if scanDesc {
+		direction, operator = "DESC", "<"
+	}
+	if cursor != nil {
+		query = query.Where(fmt.Sprintf("(%s, %s) %s (?, ?)", timeColumn, idColumn, operator), cursor.Time, cursor.ID)
+	}
+	return query.Order(timeColumn + " " + direction).Order(idColumn + " " + direction).Limit(size + 1)
+}
+
+func trimKeysetPage[T any](items []T, cursor *utils.Cursor, size int) ([]T, bool) {
+	hasMore := len(items) > size
+	if hasMore {
+		items = items[:size]
+	}
+	if cursor != nil && cursor.Backward {
+		slices.Reverse(items)
+	}
+	return items, hasMore
+}
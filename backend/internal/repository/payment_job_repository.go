@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"errors"
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrLeaseLost = errors.New("payment job lease is no longer held")
+
+type PaymentJobRepository struct {
+	Repository[entity.PaymentJob]
+	Log *logrus.Logger
+}
+
+func NewPaymentJobRepository(log *logrus.Logger) *PaymentJobRepository {
+	return &PaymentJobRepository{
+		Log: log,
+	}
+}
+
+func (r *PaymentJobRepository) Enqueue(db *gorm.DB, job *entity.PaymentJob) (bool, error) {
+	result := db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "expense_id"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"status":       constants.PaymentJobStatusPending,
+			"amount_id_r":  gorm.Expr("excluded.amount_id_r"),
+			"attempts":     0,
+			"next_run_at":  gorm.Expr("excluded.next_run_at"),
+			"locked_until": nil,
+			"last_error":   "",
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.IN{Column: clause.Column{Table: "payment_jobs", Name: "status"}, Values: []any{constants.PaymentJobStatusFailed, constants.PaymentJobStatusCompleted}},
+		}},
+	}).Create(job)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *PaymentJobRepository) Claim(db *gorm.DB, now time.Time, limit int, lease time.Duration) ([]entity.PaymentJob, error) {
+	var jobs []entity.PaymentJob
+	err := db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("(status = ? AND next_run_at <= ?) OR (status = ? AND locked_until < ?)",
+			constants.PaymentJobStatusPending, now,
+			constants.PaymentJobStatusProcessing, now,
+		).
+		Order("next_run_at asc").
+		Limit(limit).
+		Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return jobs, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(jobs))
+	for _, job := range jobs {
+		ids = append(ids, job.ID)
+	}
+
+	lockedUntil := now.Add(lease)
+	err = db.Model(&entity.PaymentJob{}).
+		Where("id IN ?", ids).
+		Updates(map[string]any{
+			"status":       constants.PaymentJobStatusProcessing,
+			"locked_until": lockedUntil,
+			"attempts":     gorm.Expr("attempts + 1"),
+		}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range jobs {
+		jobs[i].Status = constants.PaymentJobStatusProcessing
+		jobs[i].LockedUntil = &lockedUntil
+		jobs[i].Attempts++
+	}
+	return jobs, nil
+}
+
+func (r *PaymentJobRepository) MarkCompleted(db *gorm.DB, job *entity.PaymentJob) error {
+	return r.updateClaimed(db, job, map[string]any{
+		"status":       constants.PaymentJobStatusCompleted,
+		"locked_until": nil,
+		"last_error":   "",
+	})
+}
+
+func (r *PaymentJobRepository) Reschedule(db *gorm.DB, job *entity.PaymentJob, nextRunAt time.Time, lastError string) error {
+	return r.updateClaimed(db, job, map[string]any{
+		"status":       constants.PaymentJobStatusPending,
+		"next_run_at":  nextRunAt,
+		"locked_until": nil,
+		"last_error":   lastError,
+	})
+}
+
+func (r *PaymentJobRepository) Release(db *gorm.DB, job *entity.PaymentJob, nextRunAt time.Time) error {
+	return r.updateClaimed(db, job, map[string]any{
+		"status":       constants.PaymentJobStatusPending,
+		"next_run_at":  nextRunAt,
+		"locked_until": nil,
+		"attempts":     gorm.Expr("attempts - 1"),
+	})
+}
+
+func (r *PaymentJobRepository) MarkFailed(db *gorm.DB, job *entity.PaymentJob, lastError string) error {
+	return r.updateClaimed(db, job, map[string]any{
+		"status":       constants.PaymentJobStatusFailed,
+		"locked_until": nil,
+		"last_error":   lastError,
+	})
+}
+
+// Hold parks the job until ResumeHeld; the attempt it was claimed for is not counted.
+func (r *PaymentJobRepository) Hold(db *gorm.DB, job *entity.PaymentJob, reason string) error {
+	return r.updateClaimed(db, job, map[string]any{
+		"status":       constants.PaymentJobStatusHeld,
+		"locked_until": nil,
+		"attempts":     gorm.Expr("attempts - 1"),
+		"last_error":   reason,
+	})
+}
+
+// updateClaimed only touches the job while the caller still holds the lease it
+// claimed; the attempt counter identifies the claim, so a worker whose lease
+// expired cannot overwrite the result of the worker that took the job over.
+func (r *PaymentJobRepository) updateClaimed(db *gorm.DB, job *entity.PaymentJob, values map[string]any) error {
+	result := db.Model(&entity.PaymentJob{}).
+		Where("id = ? AND status = ? AND attempts = ?", job.ID, constants.PaymentJobStatusProcessing, job.Attempts).
+		Updates(values)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+func (r *PaymentJobRepository) ListUnqueuedExpenses(db *gorm.DB, statuses []string) ([]entity.Expense, error) {
+	var expenses []entity.Expense
+	err := db.Model(&entity.Expense{}).
+		Joins("LEFT JOIN payment_jobs ON payment_jobs.expense_id = expenses.id").
+		Where("expenses.status IN ? AND expenses.processed_at IS NULL AND payment_jobs.id IS NULL", statuses).
+		Order("expenses.submitted_at asc").
+		Find(&expenses).Error
+	if err != nil {
+		return nil, err
+	}
+	return expenses, nil
+}
+
+// ResumeHeld puts the held jobs of the user's expenses back in the queue.
+func (r *PaymentJobRepository) ResumeHeld(db *gorm.DB, userID uuid.UUID, now time.Time) (int64, error) {
+	result := db.Model(&entity.PaymentJob{}).
+		Where("status = ? AND expense_id IN (SELECT id FROM expenses WHERE user_id = ?)", constants.PaymentJobStatusHeld, userID).
+		Updates(map[string]any{
+			"status":      constants.PaymentJobStatusPending,
+			"next_run_at": now,
+			"last_error":  "",
+		})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
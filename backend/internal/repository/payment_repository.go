@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PaymentRepository struct {
+	Repository[entity.Payment]
+	Log *logrus.Logger
+}
+
+func NewPaymentRepository(log *logrus.Logger) *PaymentRepository {
+	return &PaymentRepository{
+		Log: log,
+	}
+}
+
+func (r *PaymentRepository) RecordRequest(db *gorm.DB, expenseID uuid.UUID, externalID string, amount int64, requestedAt time.Time) error {
+	payment := &entity.Payment{
+		ExpenseID:   expenseID,
+		ExternalID:  externalID,
+		AmountIDR:   amount,
+		Attempts:    1,
+		RequestedAt: &requestedAt,
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "expense_id"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"external_id":  gorm.Expr("excluded.external_id"),
+			"amount_id_r":  gorm.Expr("excluded.amount_id_r"),
+			"attempts":     gorm.Expr("payments.attempts + 1"),
+			"requested_at": gorm.Expr("excluded.requested_at"),
+			"updated_at":   gorm.Expr("excluded.updated_at"),
+		}),
+	}).Create(payment).Error
+}
+
+func (r *PaymentRepository) RecordResponse(db *gorm.DB, expenseID uuid.UUID, providerPaymentID, providerStatus string, respondedAt time.Time) error {
+	return db.Model(&entity.Payment{}).
+		Where("expense_id = ?", expenseID).
+		Updates(map[string]any{
+			"provider_payment_id": providerPaymentID,
+			"provider_status":     providerStatus,
+			"last_error":          "",
+			"responded_at":        respondedAt,
+		}).Error
+}
+
+func (r *PaymentRepository) RecordError(db *gorm.DB, expenseID uuid.UUID, message string, respondedAt time.Time) error {
+	return db.Model(&entity.Payment{}).
+		Where("expense_id = ?", expenseID).
+		Updates(map[string]any{
+			"last_error":   message,
+			"responded_at": respondedAt,
+		}).Error
+}
+
+func (r *PaymentRepository) FindByExpenseID(db *gorm.DB, expenseID uuid.UUID) (*entity.Payment, error) {
+	payment := new(entity.Payment)
+	if err := db.Where("expense_id = ?", expenseID).Take(payment).Error; err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (r *PaymentRepository) FindByReference(db *gorm.DB, providerPaymentID, externalID string) (*entity.Payment, error) {
+	query := db.Clauses(clause.Locking{Strength: "UPDATE"})
+	switch {
+	case providerPaymentID != "" && externalID != "":
+		query = query.Where("provider_payment_id = ? OR external_id = ?", providerPaymentID, externalID)
+	case providerPaymentID != "":
+		query = query.Where("provider_payment_id = ?", providerPaymentID)
+	default:
+		query = query.Where("external_id = ?", externalID)
+	}
+
+	payment := new(entity.Payment)
+	if err := query.Order("updated_at desc").Take(payment).Error; err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (r *PaymentRepository) UpdateProviderStatus(db *gorm.DB, id uuid.UUID, providerPaymentID, providerStatus, lastError string) error {
+	updates := map[string]any{
+		"provider_status": providerStatus,
+		"last_error":      lastError,
+	}
+	if providerPaymentID != "" {
+		updates["provider_payment_id"] = providerPaymentID
+	}
+	return db.Model(&entity.Payment{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (r *PaymentRepository) ListUnconfirmed(db *gorm.DB, requestedBefore time.Time, limit int) ([]entity.Payment, error) {
+	payments := make([]entity.Payment, 0)
+	err := db.Model(&entity.Payment{}).
+		Joins("JOIN expenses ON expenses.id = payments.expense_id").
+		Where("expenses.status = ? AND payments.requested_at < ?", constants.ExpenseStatusPaymentProcessing, requestedBefore).
+		// a queued or running job may still (re)send the request, so the provider answer is not final yet
+		Where("NOT EXISTS (SELECT 1 FROM payment_jobs WHERE payment_jobs.expense_id = payments.expense_id AND payment_jobs.status IN ?)",
+			[]string{constants.PaymentJobStatusPending, constants.PaymentJobStatusProcessing}).
+		Order("payments.requested_at asc").
+		Limit(limit).
+		Find(&payments).Error
+	if err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"go-expense-management-system/internal/entity"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PaymentWebhookEventRepository struct {
+	Repository[entity.PaymentWebhookEvent]
+	Log *logrus.Logger
+}
+
+func NewPaymentWebhookEventRepository(log *logrus.Logger) *PaymentWebhookEventRepository {
+	return &PaymentWebhookEventRepository{
+		Log: log,
+	}
+}
+
+func (r *PaymentWebhookEventRepository) Record(db *gorm.DB, event *entity.PaymentWebhookEvent) (bool, error) {
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(event)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *PaymentWebhookEventRepository) DeleteReceivedBefore(db *gorm.DB, before time.Time) error {
+	return db.Where("received_at < ?", before).Delete(&entity.PaymentWebhookEvent{}).Error
+}
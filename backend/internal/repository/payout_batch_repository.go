@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const payoutBatchLockKey = 7203114
+
+var PayoutEligibleStatuses = []string{
+	constants.ExpenseStatusApproved,
+	constants.ExpenseStatusAutoApproved,
+}
+
+type PayoutBatchRepository struct {
+	Repository[entity.PayoutBatch]
+	Log *logrus.Logger
+}
+
+type PayoutCandidate struct {
+	ExpenseID         uuid.UUID
+	UserID            uuid.UUID
+	AmountIDR         int64
+	BankCode          string
+	AccountNumber     string
+	AccountHolderName string
+}
+
+func NewPayoutBatchRepository(log *logrus.Logger) *PayoutBatchRepository {
+	return &PayoutBatchRepository{
+		Log: log,
+	}
+}
+
+func (r *PayoutBatchRepository) Lock(db *gorm.DB) error {
+	// advisory locks only exist on postgres; other databases serialise writers on their own
+	if db.Dialector.Name() != "postgres" {
+		return nil
+	}
+	return db.Exec("SELECT pg_advisory_xact_lock(?)", payoutBatchLockKey).Error
+}
+
+func (r *PayoutBatchRepository) ListCandidates(db *gorm.DB, limit int) ([]PayoutCandidate, error) {
+	candidates := make([]PayoutCandidate, 0)
+	err := db.Table("expenses").
+		Select("expenses.id AS expense_id, expenses.user_id, expenses.amount_id_r, "+
+			"user_bank_accounts.bank_code, user_bank_accounts.account_number, user_bank_accounts.account_holder_name").
+		Joins("JOIN user_bank_accounts ON user_bank_accounts.user_id = expenses.user_id AND user_bank_accounts.is_verified = ?", true).
+		Where("expenses.status IN ? AND expenses.processed_at IS NULL", PayoutEligibleStatuses).
+		Where("NOT EXISTS (?)", r.openItems(db)).
+		Order("expenses.submitted_at asc, expenses.id asc").
+		Limit(limit).
+		Scan(&candidates).Error
+	if err != nil {
+		return nil, err
+	}
+	return candidates, nil
+}
+
+func (r *PayoutBatchRepository) openItems(db *gorm.DB) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).
+		Table("payout_batch_items").
+		Select("1").
+		Joins("JOIN payout_batches ON payout_batches.id = payout_batch_items.batch_id").
+		Where("payout_batch_items.expense_id = expenses.id").
+		Where("(payout_batches.status = ? OR (payout_batches.status = ? AND payout_batch_items.status = ?))",
+			constants.PayoutBatchStatusDraft, constants.PayoutBatchStatusProcessing, constants.PayoutItemStatusPending)
+}
+
+func (r *PayoutBatchRepository) CancelDrafts(db *gorm.DB) (int64, error) {
+	result := db.Model(&entity.PayoutBatch{}).
+		Where("status = ?", constants.PayoutBatchStatusDraft).
+		Update("status", constants.PayoutBatchStatusCancelled)
+	return result.RowsAffected, result.Error
+}
+
+func (r *PayoutBatchRepository) FindWithItems(db *gorm.DB, id uuid.UUID, lock bool) (*entity.PayoutBatch, error) {
+	batch := new(entity.PayoutBatch)
+	query := db.Where("id = ?", id)
+	if lock {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	if err := query.Take(batch).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("batch_id = ?", id).Order("created_at asc, id asc").Find(&batch.Items).Error; err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+func (r *PayoutBatchRepository) List(db *gorm.DB, status string, offset, limit int) ([]entity.PayoutBatch, int64, error) {
+	filtered := func() *gorm.DB {
+		query := db.Model(&entity.PayoutBatch{})
+		if status != "" {
+			query = query.Where("status = ?", status)
+		}
+		return query
+	}
+
+	var total int64
+	if err := filtered().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	batches := make([]entity.PayoutBatch, 0)
+	if err := filtered().Order("created_at desc, id desc").Offset(offset).Limit(limit).Find(&batches).Error; err != nil {
+		return nil, 0, err
+	}
+	return batches, total, nil
+}
+
+func (r *PayoutBatchRepository) ListProcessingIDs(db *gorm.DB) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0)
+	if err := db.Model(&entity.PayoutBatch{}).Where("status = ?", constants.PayoutBatchStatusProcessing).Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *PayoutBatchRepository) SaveItem(db *gorm.DB, item *entity.PayoutBatchItem) error {
+	return db.Save(item).Error
+}
+
+func (r *PayoutBatchRepository) SaveBatch(db *gorm.DB, batch *entity.PayoutBatch) error {
+	return db.Omit(clause.Associations).Save(batch).Error
+}
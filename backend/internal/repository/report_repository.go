@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"go-expense-management-system/internal/constants"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ReportRepository struct {
+	Log *logrus.Logger
+}
+
+type ReportFilter struct {
+	From *time.Time
+	To   *time.Time
+}
+
+type ReportTotals struct {
+	Count    int64
+	TotalIDR int64 `gorm:"column:total_idr"`
+}
+
+type ReportGroup struct {
+	Key      string `gorm:"column:group_key"`
+	Label    string `gorm:"column:group_label"`
+	Count    int64
+	TotalIDR int64 `gorm:"column:total_idr"`
+}
+
+type ReportDuration struct {
+	Count          int64
+	AverageSeconds float64
+}
+
+func NewReportRepository(log *logrus.Logger) *ReportRepository {
+	return &ReportRepository{
+		Log: log,
+	}
+}
+
+func (r *ReportRepository) Totals(db *gorm.DB, filter ReportFilter) (ReportTotals, error) {
+	var totals ReportTotals
+	err := applyReportFilter(db.Table("expenses"), filter).
+		Select("COUNT(*) AS count, COALESCE(SUM(expenses.amount_id_r), 0) AS total_idr").
+		Scan(&totals).Error
+	return totals, err
+}
+
+func (r *ReportRepository) ByStatus(db *gorm.DB, filter ReportFilter) ([]ReportGroup, error) {
+	return r.groupBy(applyReportFilter(db.Table("expenses"), filter),
+		"expenses.status", "expenses.status", "total_idr DESC, group_key ASC")
+}
+
+func (r *ReportRepository) ByMonth(db *gorm.DB, filter ReportFilter) ([]ReportGroup, error) {
+	month := "to_char(date_trunc('month', expenses.submitted_at), 'YYYY-MM')"
+	return r.groupBy(applyReportFilter(db.Table("expenses"), filter), month, month, "group_key ASC")
+}
+
+func (r *ReportRepository) ByRequester(db *gorm.DB, filter ReportFilter) ([]ReportGroup, error) {
+	query := applyReportFilter(db.Table("expenses"), filter).
+		Joins("JOIN users ON users.id = expenses.user_id")
+	return r.groupBy(query, "expenses.user_id", "users.name", "total_idr DESC, group_label ASC")
+}
+
+func (r *ReportRepository) ByCategory(db *gorm.DB, filter ReportFilter) ([]ReportGroup, error) {
+	query := applyReportFilter(db.Table("expenses"), filter).
+		Joins("LEFT JOIN expense_categories ON expense_categories.id = expenses.category_id")
+	return r.groupBy(query, "COALESCE(expenses.category_id, '')", "COALESCE(expense_categories.name, '')", "total_idr DESC, group_label ASC")
+}
+
+func (r *ReportRepository) ApprovalTurnaround(db *gorm.DB, filter ReportFilter) (ReportDuration, error) {
+	return r.averageDuration(db, filter,
+		[]string{constants.ExpenseStatusAwaitingApproval},
+		[]string{constants.ExpenseStatusApproved, constants.ExpenseStatusRejected})
+}
+
+func (r *ReportRepository) PaymentCompletion(db *gorm.DB, filter ReportFilter) (ReportDuration, error) {
+	return r.averageDuration(db, filter,
+		[]string{constants.ExpenseStatusApproved, constants.ExpenseStatusAutoApproved},
+		[]string{constants.ExpenseStatusCompleted})
+}
+
+func (r *ReportRepository) groupBy(query *gorm.DB, keyExpr, labelExpr, order string) ([]ReportGroup, error) {
+	groups := make([]ReportGroup, 0)
+	query = query.
+		Select(keyExpr + " AS group_key, " + labelExpr + " AS group_label, COUNT(*) AS count, COALESCE(SUM(expenses.amount_id_r), 0) AS total_idr").
+		Group(keyExpr)
+	if labelExpr != keyExpr {
+		query = query.Group(labelExpr)
+	}
+	err := query.Order(order).Scan(&groups).Error
+	return groups, err
+}
+
+func (r *ReportRepository) averageDuration(db *gorm.DB, filter ReportFilter, fromStatuses, toStatuses []string) (ReportDuration, error) {
+	var duration ReportDuration
+	query := db.Table("expense_status_histories AS ends").
+		Joins("JOIN expenses ON expenses.id = ends.expense_id").
+		Joins(`JOIN LATERAL (SELECT starts.created_at FROM expense_status_histories AS starts
+			WHERE starts.expense_id = ends.expense_id AND starts.new_status IN ? AND starts.created_at <= ends.created_at
+			ORDER BY starts.created_at DESC LIMIT 1) AS starts ON TRUE`, fromStatuses).
+		Where("ends.new_status IN ?", toStatuses)
+	err := applyReportFilter(query, filter).
+		Select("COUNT(*) AS count, COALESCE(AVG(EXTRACT(EPOCH FROM ends.created_at - starts.created_at)), 0)::float8 AS average_seconds").
+		Scan(&duration).Error
+	return duration, err
+}
+
+func applyReportFilter(query *gorm.DB, filter ReportFilter) *gorm.DB {
+	if filter.From != nil {
+		query = query.Where("expenses.submitted_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("expenses.submitted_at < ?", *filter.To)
+	}
+	return query
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"go-expense-management-system/internal/entity"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserBankAccountRepository struct {
+	Repository[entity.UserBankAccount]
+	Log *logrus.Logger
+}
+
+func NewUserBankAccountRepository(log *logrus.Logger) *UserBankAccountRepository {
+	return &UserBankAccountRepository{
+		Log: log,
+	}
+}
+
+func (r *UserBankAccountRepository) FindByUserID(db *gorm.DB, userID uuid.UUID, lock bool) (*entity.UserBankAccount, error) {
+	account := new(entity.UserBankAccount)
+	query := db.Where("user_id = ?", userID)
+	if lock {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	if err := query.Take(account).Error; err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+func (r *UserBankAccountRepository) FindVerifiedByUserID(db *gorm.DB, userID uuid.UUID) (*entity.UserBankAccount, error) {
+	account := new(entity.UserBankAccount)
+	if err := db.Where("user_id = ? AND is_verified = ?", userID, true).Take(account).Error; err != nil {
+		return nil, err
+	}
+	return account, nil
+}
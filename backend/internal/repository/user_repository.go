@@ -3,10 +3,13 @@ package repository
 import (
 	"go-expense-management-system/internal/entity"
 
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
+const maxManagementChainDepth = 32
+
 type UserRepository struct {
 	Repository[entity.User]
 	Log *logrus.Logger
@@ -18,6 +21,28 @@ func NewUserRepository(log *logrus.Logger) *UserRepository {
 	}
 }
 
+func (r *UserRepository) ListManagementChain(db *gorm.DB, userID uuid.UUID) ([]entity.User, error) {
+	users := make([]entity.User, 0)
+	err := db.Raw(`
+		WITH RECURSIVE chain AS (
+			SELECT managers.*, 1 AS depth
+			FROM users managers
+			JOIN users requester ON requester.manager_id = managers.id
+			WHERE requester.id = ?
+			UNION ALL
+			SELECT managers.*, chain.depth + 1
+			FROM users managers
+			JOIN chain ON chain.manager_id = managers.id
+			WHERE chain.depth < ?
+		)
+		SELECT * FROM chain ORDER BY depth`, userID, maxManagementChainDepth).
+		Scan(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 func (r *UserRepository) ListByRole(db *gorm.DB, role string) ([]entity.User, error) {
 	users := make([]entity.User, 0)
 	if err := db.Where("role = ?", role).Find(&users).Error; err != nil {
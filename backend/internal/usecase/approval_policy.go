@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"fmt"
+	"go-expense-management-system/internal/constants"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type ApprovalTier struct {
+	MinAmount int64
+	// Exclusive tiers only apply to amounts strictly above MinAmount
+	Exclusive bool
+	Roles     []string
+}
+
+func (t ApprovalTier) applies(amount int64) bool {
+	if t.Exclusive {
+		return amount > t.MinAmount
+	}
+	return amount >= t.MinAmount
+}
+
+type ApprovalPolicy struct {
+	Tiers     []ApprovalTier
+	OpenRoles []string
+}
+
+func NewApprovalPolicy(tiers []ApprovalTier) *ApprovalPolicy {
+	if len(tiers) == 0 {
+		tiers = DefaultApprovalTiers()
+	}
+
+	sorted := make([]ApprovalTier, len(tiers))
+	copy(sorted, tiers)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].MinAmount != sorted[j].MinAmount {
+			return sorted[i].MinAmount < sorted[j].MinAmount
+		}
+		return !sorted[i].Exclusive && sorted[j].Exclusive
+	})
+
+	return &ApprovalPolicy{Tiers: sorted}
+}
+
+func DefaultApprovalTiers() []ApprovalTier {
+	return []ApprovalTier{
+		{MinAmount: constants.ApprovalThreshold, Roles: []string{constants.RoleManager}},
+		{MinAmount: constants.FinanceApprovalThreshold, Roles: []string{constants.RoleManager, constants.RoleFinance}},
+		{MinAmount: constants.DirectorApprovalThreshold, Exclusive: true, Roles: []string{constants.RoleManager, constants.RoleFinance, constants.RoleDirector}},
+	}
+}
+
+func (p *ApprovalPolicy) Steps(amount int64, threshold int64) []string {
+	if threshold > 0 && amount < threshold {
+		return []string{}
+	}
+
+	var roles []string
+	for _, tier := range p.Tiers {
+		if !tier.applies(amount) {
+			break
+		}
+		roles = tier.Roles
+	}
+	if roles == nil && threshold > 0 && len(p.Tiers) > 0 {
+		roles = p.Tiers[0].Roles
+	}
+
+	steps := make([]string, len(roles))
+	copy(steps, roles)
+	return steps
+}
+
+func (p *ApprovalPolicy) IsOpenRole(role string) bool {
+	for _, open := range p.OpenRoles {
+		if open == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *ApprovalPolicy) EntrySteps() []string {
+	if len(p.Tiers) == 0 {
+		return []string{}
+	}
+	steps := make([]string, len(p.Tiers[0].Roles))
+	copy(steps, p.Tiers[0].Roles)
+	return steps
+}
+
+func ParseApprovalTiers(raw string) ([]ApprovalTier, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+
+	var tiers []ApprovalTier
+	for _, part := range strings.Split(raw, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		amountStr, rolesStr, found := strings.Cut(part, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid approval tier %q: expected [>]<min_amount>:<role>[,<role>...]", part)
+		}
+
+		// a leading ">" makes the tier start above the amount instead of at it
+		amountStr, exclusive := strings.CutPrefix(strings.TrimSpace(amountStr), ">")
+		minAmount, err := strconv.ParseInt(strings.TrimSpace(amountStr), 10, 64)
+		if err != nil || minAmount <= 0 {
+			return nil, fmt.Errorf("invalid approval tier amount %q", amountStr)
+		}
+
+		var roles []string
+		for _, role := range strings.Split(rolesStr, ",") {
+			role = strings.ToLower(strings.TrimSpace(role))
+			if role == "" {
+				continue
+			}
+			if !isApproverRole(role) {
+				return nil, fmt.Errorf("invalid approval tier role %q", role)
+			}
+			roles = append(roles, role)
+		}
+		if len(roles) == 0 {
+			return nil, fmt.Errorf("approval tier %q has no roles", part)
+		}
+
+		tiers = append(tiers, ApprovalTier{MinAmount: minAmount, Exclusive: exclusive, Roles: roles})
+	}
+
+	return tiers, nil
+}
+
+func ParseApprovalRoles(raw string) ([]string, error) {
+	roles := make([]string, 0)
+	for _, role := range strings.Split(raw, ",") {
+		role = strings.ToLower(strings.TrimSpace(role))
+		if role == "" {
+			continue
+		}
+		if !isApproverRole(role) {
+			return nil, fmt.Errorf("invalid approval role %q", role)
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+func isApproverRole(role string) bool {
+	switch role {
+	case constants.RoleManager, constants.RoleFinance, constants.RoleDirector:
+		return true
+	}
+	return false
+}
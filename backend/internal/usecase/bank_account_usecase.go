@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/model/converter"
+	"go-expense-management-system/internal/repository"
+	"go-expense-management-system/internal/utils"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type BankAccountUseCase struct {
+	DB                    *gorm.DB
+	Log                   *logrus.Logger
+	BankAccountRepository *repository.UserBankAccountRepository
+	UserRepository        *repository.UserRepository
+	PaymentJobRepository  *repository.PaymentJobRepository
+}
+
+func NewBankAccountUseCase(
+	db *gorm.DB,
+	logger *logrus.Logger,
+	bankAccountRepository *repository.UserBankAccountRepository,
+	userRepository *repository.UserRepository,
+	paymentJobRepository *repository.PaymentJobRepository,
+) *BankAccountUseCase {
+	return &BankAccountUseCase{
+		DB:                    db,
+		Log:                   logger,
+		BankAccountRepository: bankAccountRepository,
+		UserRepository:        userRepository,
+		PaymentJobRepository:  paymentJobRepository,
+	}
+}
+
+func (c *BankAccountUseCase) Me(ctx context.Context, auth *model.Auth) (*model.BankAccountResponse, error) {
+	account, err := c.BankAccountRepository.FindByUserID(c.DB.WithContext(ctx), auth.UserID, false)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.Error(messages.ErrBankAccountNotFound, http.StatusNotFound, err)
+		}
+		c.Log.Warnf("Failed to find bank account: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	return converter.BankAccountToResponse(account), nil
+}
+
+func (c *BankAccountUseCase) Update(ctx context.Context, auth *model.Auth, request *model.UpdateBankAccountRequest) (*model.BankAccountResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	bankCode := strings.ToUpper(strings.TrimSpace(request.BankCode))
+	accountNumber := strings.TrimSpace(request.AccountNumber)
+	holderName := strings.TrimSpace(request.AccountHolderName)
+
+	account, err := c.BankAccountRepository.FindByUserID(tx, auth.UserID, true)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		account = &entity.UserBankAccount{UserID: auth.UserID}
+	case err != nil:
+		c.Log.Warnf("Failed to find bank account: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	changed := account.BankCode != bankCode || account.AccountNumber != accountNumber || account.AccountHolderName != holderName
+	account.BankCode = bankCode
+	account.AccountNumber = accountNumber
+	account.AccountHolderName = holderName
+	if changed {
+		account.IsVerified = false
+		account.VerifiedAt = nil
+		account.VerifiedBy = nil
+	}
+
+	if err := c.BankAccountRepository.Update(tx, account); err != nil {
+		c.Log.Warnf("Failed to save bank account: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed to commit transaction: %+v", err)
+		return nil, utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
+	}
+
+	return converter.BankAccountToResponse(account), nil
+}
+
+// Verify marks the bank account of the user with the given email as verified and
+// puts the payouts held for it back in the payment queue. It backs the
+// --verify-bank-account command, run by an operator after checking the account.
+func (c *BankAccountUseCase) Verify(ctx context.Context, email string) (*model.BankAccountResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindByCondition(tx, user, "email = ?", strings.TrimSpace(email)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.Error(messages.ErrUserNotFound, http.StatusNotFound, err)
+		}
+		c.Log.Warnf("Failed to find user by email: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	account, err := c.BankAccountRepository.FindByUserID(tx, user.ID, true)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.Error(messages.ErrBankAccountNotFound, http.StatusNotFound, err)
+		}
+		c.Log.Warnf("Failed to find bank account: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	now := time.Now()
+	if !account.IsVerified {
+		account.IsVerified = true
+		account.VerifiedAt = &now
+		if err := c.BankAccountRepository.Update(tx, account); err != nil {
+			c.Log.Warnf("Failed to verify bank account: %+v", err)
+			return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+		}
+	}
+
+	resumed, err := c.PaymentJobRepository.ResumeHeld(tx, user.ID, now)
+	if err != nil {
+		c.Log.Warnf("Failed to resume held payment jobs for user %s: %+v", user.ID, err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed to commit transaction: %+v", err)
+		return nil, utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
+	}
+
+	if resumed > 0 {
+		c.Log.Infof("Resumed %d held payouts for user %s", resumed, user.ID)
+	}
+	return converter.BankAccountToResponse(account), nil
+}
@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"fmt"
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/repository"
+	"go-expense-management-system/internal/utils"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type BudgetStatus struct {
+	Budget entity.Budget
+	From   time.Time
+	To     time.Time
+	Usage  repository.BudgetUsage
+}
+
+func (s BudgetStatus) RemainingIDR() int64 {
+	remaining := s.Budget.LimitIDR - s.Usage.SpentIDR - s.Usage.PendingIDR
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+type BudgetCheck struct {
+	Exceeded []BudgetStatus
+}
+
+func (c *BudgetCheck) Blocked() bool {
+	for _, status := range c.Exceeded {
+		if status.Budget.Enforcement == constants.BudgetEnforcementBlock {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *BudgetCheck) Note() string {
+	notes := make([]string, 0, len(c.Exceeded))
+	for _, status := range c.Exceeded {
+		notes = append(notes, fmt.Sprintf("Exceeds %s %s budget (remaining %s)",
+			status.Budget.Period, status.Budget.Scope(), utils.FormatIDR(status.RemainingIDR())))
+	}
+	return strings.Join(notes, "; ")
+}
+
+type BudgetTracker struct {
+	Log              *logrus.Logger
+	BudgetRepository *repository.BudgetRepository
+	UserRepository   *repository.UserRepository
+}
+
+func NewBudgetTracker(log *logrus.Logger, budgetRepository *repository.BudgetRepository, userRepository *repository.UserRepository) *BudgetTracker {
+	return &BudgetTracker{
+		Log:              log,
+		BudgetRepository: budgetRepository,
+		UserRepository:   userRepository,
+	}
+}
+
+func (t *BudgetTracker) Statuses(db *gorm.DB, userID uuid.UUID, now time.Time, lock bool, excludeExpenseID *uuid.UUID) ([]BudgetStatus, error) {
+	user := new(entity.User)
+	if err := t.UserRepository.FindById(db, user, userID); err != nil {
+		return nil, utils.Error(messages.ErrUserNotFound, http.StatusNotFound, err)
+	}
+
+	budgets, err := t.BudgetRepository.ListActiveForUser(db, user.ID, user.DepartmentID, lock)
+	if err != nil {
+		t.Log.Warnf("Failed to list budgets: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	statuses := make([]BudgetStatus, 0, len(budgets))
+	for i := range budgets {
+		from, to := BudgetPeriodWindow(budgets[i].Period, now)
+		usage, err := t.BudgetRepository.Usage(db, repository.BudgetUsageFilter{
+			Budget:           &budgets[i],
+			From:             from,
+			To:               to,
+			ExcludeExpenseID: excludeExpenseID,
+		})
+		if err != nil {
+			t.Log.Warnf("Failed to compute budget usage: %+v", err)
+			return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+		}
+		statuses = append(statuses, BudgetStatus{Budget: budgets[i], From: from, To: to, Usage: usage})
+	}
+	return statuses, nil
+}
+
+func (t *BudgetTracker) Check(tx *gorm.DB, userID uuid.UUID, amount int64, excludeExpenseID *uuid.UUID) (*BudgetCheck, error) {
+	statuses, err := t.Statuses(tx, userID, time.Now(), true, excludeExpenseID)
+	if err != nil {
+		return nil, err
+	}
+
+	check := &BudgetCheck{}
+	for _, status := range statuses {
+		if status.Usage.SpentIDR+status.Usage.PendingIDR+amount > status.Budget.LimitIDR {
+			check.Exceeded = append(check.Exceeded, status)
+		}
+	}
+	return check, nil
+}
+
+func BudgetPeriodWindow(period string, now time.Time) (time.Time, time.Time) {
+	year, month, _ := now.Date()
+	if period == constants.BudgetPeriodQuarterly {
+		start := time.Date(year, ((month-1)/3)*3+1, 1, 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(0, 3, 0)
+	}
+
+	start := time.Date(year, month, 1, 0, 0, 0, 0, now.Location())
+	return start, start.AddDate(0, 1, 0)
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/utils"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type BudgetUseCase struct {
+	DB      *gorm.DB
+	Log     *logrus.Logger
+	Tracker *BudgetTracker
+}
+
+func NewBudgetUseCase(db *gorm.DB, logger *logrus.Logger, tracker *BudgetTracker) *BudgetUseCase {
+	return &BudgetUseCase{
+		DB:      db,
+		Log:     logger,
+		Tracker: tracker,
+	}
+}
+
+func (c *BudgetUseCase) Me(ctx context.Context, auth *model.Auth) ([]model.BudgetStatusResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	statuses, err := c.Tracker.Statuses(tx, auth.UserID, time.Now(), false, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]model.BudgetStatusResponse, 0, len(statuses))
+	for _, status := range statuses {
+		responses = append(responses, model.BudgetStatusResponse{
+			ID:           status.Budget.ID,
+			Scope:        status.Budget.Scope(),
+			DepartmentID: status.Budget.DepartmentID,
+			Period:       status.Budget.Period,
+			PeriodStart:  status.From,
+			PeriodEnd:    status.To,
+			Enforcement:  status.Budget.Enforcement,
+			LimitIDR:     status.Budget.LimitIDR,
+			SpentIDR:     status.Usage.SpentIDR,
+			PendingIDR:   status.Usage.PendingIDR,
+			RemainingIDR: status.RemainingIDR(),
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed to commit transaction: %+v", err)
+		return nil, utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
+	}
+
+	return responses, nil
+}
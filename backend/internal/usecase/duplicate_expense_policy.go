@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/utils"
+	"time"
+)
+
+type DuplicateExpensePolicy struct {
+	Window        time.Duration
+	MinSimilarity float64
+}
+
+func (p *DuplicateExpensePolicy) Enabled() bool {
+	return p != nil && p.Window > 0
+}
+
+func (p *DuplicateExpensePolicy) Match(expense *entity.Expense, candidates []entity.Expense) *entity.Expense {
+	for i := range candidates {
+		candidate := &candidates[i]
+		if candidate.ID == expense.ID {
+			continue
+		}
+		if expense.ReceiptURL != "" && candidate.ReceiptURL == expense.ReceiptURL {
+			return candidate
+		}
+		if candidate.AmountIDR == expense.AmountIDR &&
+			utils.TextSimilarity(candidate.Description, expense.Description) >= p.MinSimilarity {
+			return candidate
+		}
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"context"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/model/converter"
+	"go-expense-management-system/internal/repository"
+	"go-expense-management-system/internal/utils"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ExpenseCategoryUseCase struct {
+	DB                 *gorm.DB
+	Log                *logrus.Logger
+	CategoryRepository *repository.ExpenseCategoryRepository
+	ExpenseRepository  *repository.ExpenseRepository
+}
+
+func NewExpenseCategoryUseCase(
+	db *gorm.DB,
+	logger *logrus.Logger,
+	categoryRepository *repository.ExpenseCategoryRepository,
+	expenseRepository *repository.ExpenseRepository,
+) *ExpenseCategoryUseCase {
+	return &ExpenseCategoryUseCase{
+		DB:                 db,
+		Log:                logger,
+		CategoryRepository: categoryRepository,
+		ExpenseRepository:  expenseRepository,
+	}
+}
+
+func (c *ExpenseCategoryUseCase) List(ctx context.Context, auth *model.Auth) ([]model.ExpenseCategoryResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	categories, err := c.CategoryRepository.List(tx, isManager(auth))
+	if err != nil {
+		c.Log.Warnf("Failed to list expense categories: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed to commit transaction: %+v", err)
+		return nil, utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
+	}
+
+	responses := make([]model.ExpenseCategoryResponse, 0, len(categories))
+	for i := range categories {
+		responses = append(responses, *converter.ExpenseCategoryToResponse(&categories[i]))
+	}
+	return responses, nil
+}
+
+func (c *ExpenseCategoryUseCase) Get(ctx context.Context, auth *model.Auth, categoryID uuid.UUID) (*model.ExpenseCategoryResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	category := new(entity.ExpenseCategory)
+	if err := c.CategoryRepository.FindById(tx, category, categoryID); err != nil {
+		return nil, utils.Error(messages.ErrCategoryNotFound, http.StatusNotFound, err)
+	}
+	if !category.IsActive && !isManager(auth) {
+		return nil, utils.Error(messages.ErrCategoryNotFound, http.StatusNotFound, nil)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed to commit transaction: %+v", err)
+		return nil, utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
+	}
+
+	return converter.ExpenseCategoryToResponse(category), nil
+}
+
+func (c *ExpenseCategoryUseCase) Create(ctx context.Context, auth *model.Auth, request *model.CreateExpenseCategoryRequest) (*model.ExpenseCategoryResponse, error) {
+	if !isManager(auth) {
+		return nil, utils.Error(messages.Forbidden, http.StatusForbidden, nil)
+	}
+
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	code := normalizeCategoryCode(request.Code)
+	total, err := c.CategoryRepository.CountByCondition(tx, "code = ?", code)
+	if err != nil {
+		c.Log.Warnf("Failed to check existing expense category: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+	if total > 0 {
+		return nil, utils.Error(messages.ErrCategoryAlreadyExists, http.StatusConflict, nil)
+	}
+
+	category := &entity.ExpenseCategory{
+		Code:                    code,
+		Name:                    strings.TrimSpace(request.Name),
+		MinAmountIDR:            request.MinAmountIDR,
+		MaxAmountIDR:            request.MaxAmountIDR,
+		ApprovalThresholdIDR:    request.ApprovalThresholdIDR,
+		ReceiptRequiredAboveIDR: request.ReceiptRequiredAboveIDR,
+		IsActive:                true,
+	}
+	if request.IsActive != nil {
+		category.IsActive = *request.IsActive
+	}
+
+	if err := c.CategoryRepository.Create(tx, category); err != nil {
+		c.Log.Warnf("Failed to create expense category: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed to commit transaction: %+v", err)
+		return nil, utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
+	}
+
+	return converter.ExpenseCategoryToResponse(category), nil
+}
+
+func (c *ExpenseCategoryUseCase) Update(ctx context.Context, auth *model.Auth, request *model.UpdateExpenseCategoryRequest) (*model.ExpenseCategoryResponse, error) {
+	if !isManager(auth) {
+		return nil, utils.Error(messages.Forbidden, http.StatusForbidden, nil)
+	}
+
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	category := new(entity.ExpenseCategory)
+	if err := c.CategoryRepository.FindById(tx, category, request.ID); err != nil {
+		return nil, utils.Error(messages.ErrCategoryNotFound, http.StatusNotFound, err)
+	}
+
+	code := normalizeCategoryCode(request.Code)
+	total, err := c.CategoryRepository.CountByCondition(tx, "code = ? AND id <> ?", code, category.ID)
+	if err != nil {
+		c.Log.Warnf("Failed to check existing expense category: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+	if total > 0 {
+		return nil, utils.Error(messages.ErrCategoryAlreadyExists, http.StatusConflict, nil)
+	}
+
+	category.Code = code
+	category.Name = strings.TrimSpace(request.Name)
+	category.MinAmountIDR = request.MinAmountIDR
+	category.MaxAmountIDR = request.MaxAmountIDR
+	category.ApprovalThresholdIDR = request.ApprovalThresholdIDR
+	category.ReceiptRequiredAboveIDR = request.ReceiptRequiredAboveIDR
+	if request.IsActive != nil {
+		category.IsActive = *request.IsActive
+	}
+
+	if err := c.CategoryRepository.Update(tx, category); err != nil {
+		c.Log.Warnf("Failed to update expense category: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed to commit transaction: %+v", err)
+		return nil, utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
+	}
+
+	return converter.ExpenseCategoryToResponse(category), nil
+}
+
+func (c *ExpenseCategoryUseCase) Delete(ctx context.Context, auth *model.Auth, categoryID uuid.UUID) error {
+	if !isManager(auth) {
+		return utils.Error(messages.Forbidden, http.StatusForbidden, nil)
+	}
+
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	category := new(entity.ExpenseCategory)
+	if err := c.CategoryRepository.FindById(tx, category, categoryID); err != nil {
+		return utils.Error(messages.ErrCategoryNotFound, http.StatusNotFound, err)
+	}
+
+	used, err := c.ExpenseRepository.CountByCondition(tx, "category_id = ?", category.ID)
+	if err != nil {
+		c.Log.Warnf("Failed to check expense category usage: %+v", err)
+		return utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+	if used > 0 {
+		return utils.Error(messages.ErrCategoryInUse, http.StatusConflict, nil)
+	}
+
+	if err := c.CategoryRepository.Delete(tx, category); err != nil {
+		c.Log.Warnf("Failed to delete expense category: %+v", err)
+		return utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed to commit transaction: %+v", err)
+		return utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
+	}
+
+	return nil
+}
+
+func normalizeCategoryCode(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/model/converter"
+	"go-expense-management-system/internal/repository"
+	"go-expense-management-system/internal/utils"
+	"io"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const receiptSniffLength = 512
+
+var receiptExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"application/pdf": ".pdf",
+}
+
+// receipts can no longer be attached once the expense reached a terminal status
+var receiptClosedStatuses = []string{
+	constants.ExpenseStatusCompleted,
+	constants.ExpenseStatusCancelled,
+}
+
+type ExpenseReceiptUseCase struct {
+	DB                *gorm.DB
+	Log               *logrus.Logger
+	ExpenseRepository *repository.ExpenseRepository
+	ReceiptRepository *repository.ExpenseReceiptRepository
+	Storage           ReceiptStorage
+	MaxSizeBytes      int64
+	DuplicatePolicy   *DuplicateExpensePolicy
+}
+
+func NewExpenseReceiptUseCase(
+	db *gorm.DB,
+	logger *logrus.Logger,
+	expenseRepository *repository.ExpenseRepository,
+	receiptRepository *repository.ExpenseReceiptRepository,
+	storage ReceiptStorage,
+	maxSizeBytes int64,
+	duplicatePolicy *DuplicateExpensePolicy,
+) *ExpenseReceiptUseCase {
+	return &ExpenseReceiptUseCase{
+		DB:                db,
+		Log:               logger,
+		ExpenseRepository: expenseRepository,
+		ReceiptRepository: receiptRepository,
+		Storage:           storage,
+		MaxSizeBytes:      maxSizeBytes,
+		DuplicatePolicy:   duplicatePolicy,
+	}
+}
+
+func (c *ExpenseReceiptUseCase) Upload(ctx context.Context, auth *model.Auth, request *model.UploadReceiptRequest) (*model.ExpenseReceiptResponse, error) {
+	db := c.DB.WithContext(ctx)
+
+	expense := new(entity.Expense)
+	if err := c.ExpenseRepository.FindById(db, expense, request.ExpenseID); err != nil {
+		return nil, utils.Error(messages.ErrExpenseNotFound, http.StatusNotFound, err)
+	}
+	if expense.UserID != auth.UserID {
+		return nil, utils.Error(messages.Forbidden, http.StatusForbidden, nil)
+	}
+	if slices.Contains(receiptClosedStatuses, expense.Status) {
+		return nil, utils.Error(messages.ErrReceiptExpenseClosed, http.StatusConflict, nil)
+	}
+	if request.Size > c.MaxSizeBytes {
+		return nil, utils.Error(messages.ErrReceiptTooLarge, http.StatusRequestEntityTooLarge, nil)
+	}
+
+	head := make([]byte, receiptSniffLength)
+	n, err := io.ReadFull(request.Content, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, utils.Error(messages.ErrReceiptFileRequired, http.StatusBadRequest, err)
+	}
+	if n == 0 {
+		return nil, utils.Error(messages.ErrReceiptFileRequired, http.StatusBadRequest, nil)
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	extension, ok := receiptExtensions[contentType]
+	if !ok {
+		return nil, utils.Error(messages.ErrReceiptUnsupportedType, http.StatusUnsupportedMediaType, nil)
+	}
+
+	receipt := &entity.ExpenseReceipt{
+		ID:          uuid.New(),
+		ExpenseID:   expense.ID,
+		UploadedBy:  auth.UserID,
+		FileName:    receiptFileName(request.FileName, extension),
+		ContentType: contentType,
+	}
+	receipt.StorageKey = fmt.Sprintf("receipts/%s/%s%s", expense.ID, receipt.ID, extension)
+
+	hasher := sha256.New()
+	var size byteCounter
+	content := io.LimitReader(io.MultiReader(bytes.NewReader(head), request.Content), c.MaxSizeBytes+1)
+	if err := c.Storage.Put(ctx, receipt.StorageKey, io.TeeReader(content, io.MultiWriter(hasher, &size))); err != nil {
+		c.Log.Warnf("Failed to store receipt: %+v", err)
+		return nil, utils.Error(messages.ErrReceiptStore, http.StatusInternalServerError, err)
+	}
+	if int64(size) > c.MaxSizeBytes {
+		c.removeObject(ctx, receipt.StorageKey)
+		return nil, utils.Error(messages.ErrReceiptTooLarge, http.StatusRequestEntityTooLarge, nil)
+	}
+
+	receipt.SizeBytes = int64(size)
+	receipt.SHA256 = hex.EncodeToString(hasher.Sum(nil))
+
+	if err := c.ReceiptRepository.Create(db, receipt); err != nil {
+		c.Log.Warnf("Failed to create receipt: %+v", err)
+		c.removeObject(ctx, receipt.StorageKey)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	c.flagDuplicateReceipt(db, expense, receipt)
+
+	return converter.ExpenseReceiptToResponse(receipt), nil
+}
+
+func (c *ExpenseReceiptUseCase) flagDuplicateReceipt(db *gorm.DB, expense *entity.Expense, receipt *entity.ExpenseReceipt) {
+	if !c.DuplicatePolicy.Enabled() || expense.PossibleDuplicateOf != nil {
+		return
+	}
+
+	duplicateOf, err := c.ReceiptRepository.FindDuplicateExpenseID(db, receipt, expense.UserID, time.Now().Add(-c.DuplicatePolicy.Window))
+	if err != nil {
+		c.Log.Warnf("Failed to check duplicate receipt: %+v", err)
+		return
+	}
+	if duplicateOf == nil {
+		return
+	}
+
+	if _, err := c.ExpenseRepository.MarkPossibleDuplicate(db, expense.ID, *duplicateOf); err != nil {
+		c.Log.Warnf("Failed to flag possible duplicate expense: %+v", err)
+		return
+	}
+	c.Log.Infof("Expense %s flagged as possible duplicate of %s (same receipt file)", expense.ID, *duplicateOf)
+}
+
+func (c *ExpenseReceiptUseCase) List(ctx context.Context, auth *model.Auth, expenseID uuid.UUID) ([]model.ExpenseReceiptResponse, error) {
+	db := c.DB.WithContext(ctx)
+
+	if _, err := c.findViewableExpense(db, auth, expenseID); err != nil {
+		return nil, err
+	}
+
+	receipts, err := c.ReceiptRepository.ListByExpenseID(db, expenseID)
+	if err != nil {
+		c.Log.Warnf("Failed to list receipts: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	responses := make([]model.ExpenseReceiptResponse, 0, len(receipts))
+	for i := range receipts {
+		responses = append(responses, *converter.ExpenseReceiptToResponse(&receipts[i]))
+	}
+	return responses, nil
+}
+
+func (c *ExpenseReceiptUseCase) Download(ctx context.Context, auth *model.Auth, expenseID, receiptID uuid.UUID) (*model.ReceiptDownload, error) {
+	db := c.DB.WithContext(ctx)
+
+	if _, err := c.findViewableExpense(db, auth, expenseID); err != nil {
+		return nil, err
+	}
+
+	receipt := new(entity.ExpenseReceipt)
+	if err := c.ReceiptRepository.FindByExpenseID(db, receipt, expenseID, receiptID); err != nil {
+		return nil, utils.Error(messages.ErrReceiptNotFound, http.StatusNotFound, err)
+	}
+
+	content, err := c.Storage.Open(ctx, receipt.StorageKey)
+	if err != nil {
+		c.Log.Warnf("Failed to open receipt %s: %+v", receipt.ID, err)
+		return nil, utils.Error(messages.ErrReceiptStore, http.StatusInternalServerError, err)
+	}
+
+	return &model.ReceiptDownload{
+		FileName:    receipt.FileName,
+		ContentType: receipt.ContentType,
+		SizeBytes:   receipt.SizeBytes,
+		Content:     content,
+	}, nil
+}
+
+func (c *ExpenseReceiptUseCase) findViewableExpense(db *gorm.DB, auth *model.Auth, expenseID uuid.UUID) (*entity.Expense, error) {
+	expense := new(entity.Expense)
+	if err := c.ExpenseRepository.FindById(db, expense, expenseID); err != nil {
+		return nil, utils.Error(messages.ErrExpenseNotFound, http.StatusNotFound, err)
+	}
+	if !canViewExpense(auth, expense) {
+		return nil, utils.Error(messages.Forbidden, http.StatusForbidden, nil)
+	}
+	return expense, nil
+}
+
+func (c *ExpenseReceiptUseCase) removeObject(ctx context.Context, key string) {
+	if err := c.Storage.Delete(ctx, key); err != nil {
+		c.Log.Warnf("Failed to remove stored receipt %s: %+v", key, err)
+	}
+}
+
+func receiptFileName(name, extension string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "" || name == "." || name == "/" {
+		return "receipt" + extension
+	}
+	if len(name) > 255 {
+		name = name[len(name)-255:]
+	}
+	return name
+}
+
+type byteCounter int64
+
+func (b *byteCounter) Write(p []byte) (int, error) {
+	*b += byteCounter(len(p))
+	return len(p), nil
+}
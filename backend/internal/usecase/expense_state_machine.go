@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"errors"
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/repository"
+	"go-expense-management-system/internal/utils"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ExpenseEvent string
+
+const (
+	ExpenseEventSubmit          ExpenseEvent = "submit"
+	ExpenseEventAutoApprove     ExpenseEvent = "auto_approve"
+	ExpenseEventEdit            ExpenseEvent = "edit"
+	ExpenseEventApprove         ExpenseEvent = "approve"
+	ExpenseEventReject          ExpenseEvent = "reject"
+	ExpenseEventCancel          ExpenseEvent = "cancel"
+	ExpenseEventResubmit        ExpenseEvent = "resubmit"
+	ExpenseEventStartPayment    ExpenseEvent = "start_payment"
+	ExpenseEventCompletePayment ExpenseEvent = "complete_payment"
+	ExpenseEventFailPayment     ExpenseEvent = "fail_payment"
+	ExpenseEventRetryPayment    ExpenseEvent = "retry_payment"
+)
+
+const (
+	ExpenseActorRequester = "requester"
+	ExpenseActorSystem    = "system"
+)
+
+type ExpenseSideEffect string
+
+const (
+	ExpenseEffectEnqueuePayment ExpenseSideEffect = "enqueue_payment"
+	ExpenseEffectNotifyApprover ExpenseSideEffect = "notify_approver"
+)
+
+type ExpenseTransition struct {
+	Event       ExpenseEvent
+	From        string
+	To          string
+	Roles       []string
+	SideEffects []ExpenseSideEffect
+}
+
+func (t ExpenseTransition) HasSideEffect(effect ExpenseSideEffect) bool {
+	for _, candidate := range t.SideEffects {
+		if candidate == effect {
+			return true
+		}
+	}
+	return false
+}
+
+func (t ExpenseTransition) allows(expense *entity.Expense, actor *model.Auth) bool {
+	for _, role := range t.Roles {
+		switch role {
+		case ExpenseActorSystem:
+			if actor == nil {
+				return true
+			}
+		case ExpenseActorRequester:
+			if actor != nil && actor.UserID == expense.UserID {
+				return true
+			}
+		default:
+			if actor != nil && actor.Role == role {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var reviewerRoles = []string{constants.RoleManager, constants.RoleFinance, constants.RoleDirector}
+
+func DefaultExpenseTransitions() []ExpenseTransition {
+	requester := []string{ExpenseActorRequester}
+	system := []string{ExpenseActorSystem}
+	notify := []ExpenseSideEffect{ExpenseEffectNotifyApprover}
+	pay := []ExpenseSideEffect{ExpenseEffectEnqueuePayment}
+
+	return []ExpenseTransition{
+		{Event: ExpenseEventSubmit, From: "", To: constants.ExpenseStatusAwaitingApproval, Roles: requester, SideEffects: notify},
+		{Event: ExpenseEventAutoApprove, From: "", To: constants.ExpenseStatusAutoApproved, Roles: requester, SideEffects: pay},
+		{Event: ExpenseEventEdit, From: constants.ExpenseStatusAwaitingApproval, To: constants.ExpenseStatusAwaitingApproval, Roles: requester, SideEffects: notify},
+		{Event: ExpenseEventAutoApprove, From: constants.ExpenseStatusAwaitingApproval, To: constants.ExpenseStatusAutoApproved, Roles: requester, SideEffects: pay},
+		{Event: ExpenseEventApprove, From: constants.ExpenseStatusAwaitingApproval, To: constants.ExpenseStatusApproved, Roles: reviewerRoles, SideEffects: pay},
+		{Event: ExpenseEventReject, From: constants.ExpenseStatusAwaitingApproval, To: constants.ExpenseStatusRejected, Roles: reviewerRoles},
+		{Event: ExpenseEventCancel, From: constants.ExpenseStatusAwaitingApproval, To: constants.ExpenseStatusCancelled, Roles: requester},
+		{Event: ExpenseEventCancel, From: constants.ExpenseStatusRejected, To: constants.ExpenseStatusCancelled, Roles: requester},
+		{Event: ExpenseEventResubmit, From: constants.ExpenseStatusRejected, To: constants.ExpenseStatusAwaitingApproval, Roles: requester, SideEffects: notify},
+		{Event: ExpenseEventStartPayment, From: constants.ExpenseStatusApproved, To: constants.ExpenseStatusPaymentProcessing, Roles: system},
+		{Event: ExpenseEventStartPayment, From: constants.ExpenseStatusAutoApproved, To: constants.ExpenseStatusPaymentProcessing, Roles: system},
+		{Event: ExpenseEventCompletePayment, From: constants.ExpenseStatusPaymentProcessing, To: constants.ExpenseStatusCompleted, Roles: system},
+		{Event: ExpenseEventFailPayment, From: constants.ExpenseStatusApproved, To: constants.ExpenseStatusPaymentFailed, Roles: system},
+		{Event: ExpenseEventFailPayment, From: constants.ExpenseStatusAutoApproved, To: constants.ExpenseStatusPaymentFailed, Roles: system},
+		{Event: ExpenseEventFailPayment, From: constants.ExpenseStatusPaymentProcessing, To: constants.ExpenseStatusPaymentFailed, Roles: system},
+		{Event: ExpenseEventRetryPayment, From: constants.ExpenseStatusPaymentFailed, To: constants.ExpenseStatusApproved, Roles: []string{constants.RoleManager}, SideEffects: pay},
+	}
+}
+
+type ExpenseStateMachine struct {
+	Log               *logrus.Logger
+	ExpenseRepository *repository.ExpenseRepository
+	HistoryRepository *repository.ExpenseStatusHistoryRepository
+	transitions       []ExpenseTransition
+}
+
+func NewExpenseStateMachine(
+	logger *logrus.Logger,
+	expenseRepository *repository.ExpenseRepository,
+	historyRepository *repository.ExpenseStatusHistoryRepository,
+) *ExpenseStateMachine {
+	return &ExpenseStateMachine{
+		Log:               logger,
+		ExpenseRepository: expenseRepository,
+		HistoryRepository: historyRepository,
+		transitions:       DefaultExpenseTransitions(),
+	}
+}
+
+func (m *ExpenseStateMachine) Resolve(expense *entity.Expense, event ExpenseEvent, actor *model.Auth) (*ExpenseTransition, error) {
+	for i := range m.transitions {
+		transition := m.transitions[i]
+		if transition.Event != event || transition.From != expense.Status {
+			continue
+		}
+		if !transition.allows(expense, actor) {
+			return nil, utils.Error(messages.Forbidden, http.StatusForbidden, nil)
+		}
+		return &transition, nil
+	}
+	return nil, utils.Error(messages.ErrInvalidStatusTransition, http.StatusConflict, nil)
+}
+
+func (m *ExpenseStateMachine) Transition(tx *gorm.DB, expense *entity.Expense, event ExpenseEvent, actor *model.Auth, notes string) (*ExpenseTransition, error) {
+	transition, err := m.Resolve(expense, event, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	previousStatus := expense.Status
+	expense.Status = transition.To
+	if previousStatus == "" {
+		expense.Version = 1
+		err = m.ExpenseRepository.Create(tx, expense)
+		if err != nil {
+			m.Log.Warnf("Failed to create expense: %+v", err)
+			err = utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+		}
+	} else {
+		err = m.Touch(tx, expense)
+	}
+	if err != nil {
+		expense.Status = previousStatus
+		return nil, err
+	}
+
+	var actorID *uuid.UUID
+	if actor != nil {
+		actorID = &actor.UserID
+	}
+	history := &entity.ExpenseStatusHistory{
+		ExpenseID:      expense.ID,
+		ActorID:        actorID,
+		PreviousStatus: previousStatus,
+		NewStatus:      expense.Status,
+		Notes:          strings.TrimSpace(notes),
+	}
+	if err := m.HistoryRepository.Create(tx, history); err != nil {
+		m.Log.Warnf("Failed to create expense history: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	return transition, nil
+}
+
+// Note records a history entry that leaves the status unchanged, e.g. why a payout is held.
+func (m *ExpenseStateMachine) Note(tx *gorm.DB, expense *entity.Expense, notes string) error {
+	history := &entity.ExpenseStatusHistory{
+		ExpenseID:      expense.ID,
+		PreviousStatus: expense.Status,
+		NewStatus:      expense.Status,
+		Notes:          strings.TrimSpace(notes),
+	}
+	if err := m.HistoryRepository.Create(tx, history); err != nil {
+		m.Log.Warnf("Failed to create expense history: %+v", err)
+		return utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+	return nil
+}
+
+func (m *ExpenseStateMachine) Touch(tx *gorm.DB, expense *entity.Expense) error {
+	if err := m.ExpenseRepository.UpdateVersioned(tx, expense); err != nil {
+		if errors.Is(err, repository.ErrStaleVersion) {
+			return utils.Error(messages.ErrExpenseModified, http.StatusConflict, err)
+		}
+		m.Log.Warnf("Failed to update expense: %+v", err)
+		return utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"go-expense-management-system/internal/constants"
 	"go-expense-management-system/internal/entity"
@@ -11,6 +12,7 @@ import (
 	"go-expense-management-system/internal/repository"
 	"go-expense-management-system/internal/utils"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
@@ -20,15 +22,25 @@ import (
 )
 
 type ExpenseUseCase struct {
-	DB                 *gorm.DB
-	Log                *logrus.Logger
-	ExpenseRepository  *repository.ExpenseRepository
-	ApprovalRepository *repository.ApprovalRepository
-	HistoryRepository  *repository.ExpenseStatusHistoryRepository
-	UserRepository     *repository.UserRepository
-	EmailSender        EmailSender
-	PaymentQueue       PaymentQueue
-	PaymentProcessor   PaymentProcessor
+	DB                    *gorm.DB
+	Log                   *logrus.Logger
+	ExpenseRepository     *repository.ExpenseRepository
+	ApprovalRepository    *repository.ApprovalRepository
+	HistoryRepository     *repository.ExpenseStatusHistoryRepository
+	UserRepository        *repository.UserRepository
+	CategoryRepository    *repository.ExpenseCategoryRepository
+	ReceiptRepository     *repository.ExpenseReceiptRepository
+	PaymentRepository     *repository.PaymentRepository
+	BankAccountRepository *repository.UserBankAccountRepository
+	BudgetTracker         *BudgetTracker
+	EmailSender           EmailSender
+	PaymentQueue          PaymentQueue
+	PaymentProcessor      PaymentProcessor
+	ApprovalPolicy        *ApprovalPolicy
+	SplitPolicy           *SplitExpensePolicy
+	DuplicatePolicy       *DuplicateExpensePolicy
+	StateMachine          *ExpenseStateMachine
+	EscalationAfter       time.Duration
 }
 
 func NewExpenseUseCase(
@@ -38,20 +50,41 @@ func NewExpenseUseCase(
 	approvalRepository *repository.ApprovalRepository,
 	historyRepository *repository.ExpenseStatusHistoryRepository,
 	userRepository *repository.UserRepository,
+	categoryRepository *repository.ExpenseCategoryRepository,
+	receiptRepository *repository.ExpenseReceiptRepository,
+	paymentRepository *repository.PaymentRepository,
+	bankAccountRepository *repository.UserBankAccountRepository,
+	budgetTracker *BudgetTracker,
 	emailSender EmailSender,
 	paymentQueue PaymentQueue,
 	paymentProcessor PaymentProcessor,
+	approvalPolicy *ApprovalPolicy,
+	splitPolicy *SplitExpensePolicy,
+	duplicatePolicy *DuplicateExpensePolicy,
 ) *ExpenseUseCase {
+	if approvalPolicy == nil {
+		approvalPolicy = NewApprovalPolicy(nil)
+	}
+
 	return &ExpenseUseCase{
-		DB:                 db,
-		Log:                logger,
-		ExpenseRepository:  expenseRepository,
-		ApprovalRepository: approvalRepository,
-		HistoryRepository:  historyRepository,
-		UserRepository:     userRepository,
-		EmailSender:        emailSender,
-		PaymentQueue:       paymentQueue,
-		PaymentProcessor:   paymentProcessor,
+		DB:                    db,
+		Log:                   logger,
+		ExpenseRepository:     expenseRepository,
+		ApprovalRepository:    approvalRepository,
+		HistoryRepository:     historyRepository,
+		UserRepository:        userRepository,
+		CategoryRepository:    categoryRepository,
+		ReceiptRepository:     receiptRepository,
+		PaymentRepository:     paymentRepository,
+		BankAccountRepository: bankAccountRepository,
+		BudgetTracker:         budgetTracker,
+		EmailSender:           emailSender,
+		PaymentQueue:          paymentQueue,
+		PaymentProcessor:      paymentProcessor,
+		ApprovalPolicy:        approvalPolicy,
+		SplitPolicy:           splitPolicy,
+		DuplicatePolicy:       duplicatePolicy,
+		StateMachine:          NewExpenseStateMachine(logger, expenseRepository, historyRepository),
 	}
 }
 
@@ -59,7 +92,15 @@ func (c *ExpenseUseCase) Create(ctx context.Context, auth *model.Auth, request *
 	tx := c.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
-	if err := validateExpenseAmount(request.AmountIDR); err != nil {
+	category := new(entity.ExpenseCategory)
+	if err := c.CategoryRepository.FindById(tx, category, request.CategoryID); err != nil {
+		return nil, utils.Error(messages.ErrCategoryNotFound, http.StatusBadRequest, err)
+	}
+	if !category.IsActive {
+		return nil, utils.Error(messages.ErrCategoryInactive, http.StatusBadRequest, nil)
+	}
+
+	if err := validateExpenseAmount(request.AmountIDR, category); err != nil {
 		return nil, err
 	}
 
@@ -68,74 +109,126 @@ func (c *ExpenseUseCase) Create(ctx context.Context, auth *model.Auth, request *
 		return nil, utils.Error(messages.InvalidRequestData, http.StatusBadRequest, nil)
 	}
 
-	requiresApproval := request.AmountIDR >= constants.ApprovalThreshold
-	status := constants.ExpenseStatusAutoApproved
-	if requiresApproval {
-		status = constants.ExpenseStatusAwaitingApproval
+	receiptURL := strings.TrimSpace(request.ReceiptURL)
+
+	budgetCheck, err := c.BudgetTracker.Check(tx, auth.UserID, request.AmountIDR, nil)
+	if err != nil {
+		return nil, err
+	}
+	if budgetCheck.Blocked() {
+		return nil, utils.Error(messages.ErrBudgetExceeded, http.StatusUnprocessableEntity, nil)
+	}
+
+	steps := c.approvalSteps(category, request.AmountIDR, receiptURL != "")
+	if len(budgetCheck.Exceeded) > 0 && len(steps) == 0 {
+		steps = c.ApprovalPolicy.EntrySteps()
+	}
+
+	splitNote := ""
+	if len(steps) == 0 {
+		steps, splitNote, err = c.splitExpenseSteps(tx, auth.UserID, category, request.AmountIDR, description)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	event := ExpenseEventSubmit
+	if len(steps) == 0 {
+		event = ExpenseEventAutoApprove
 	}
 
 	expense := &entity.Expense{
-		UserID:      auth.UserID,
-		AmountIDR:   request.AmountIDR,
-		Description: description,
-		ReceiptURL:  request.ReceiptURL,
-		Status:      status,
+		UserID:           auth.UserID,
+		CategoryID:       &category.ID,
+		AmountIDR:        request.AmountIDR,
+		Description:      description,
+		ReceiptURL:       receiptURL,
+		RequiresApproval: len(steps) > 0,
 	}
 
-	if err := c.ExpenseRepository.Create(tx, expense); err != nil {
-		c.Log.Warnf("Failed to create expense: %+v", err)
-		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	duplicateNote, err := c.flagPossibleDuplicate(tx, expense)
+	if err != nil {
+		return nil, err
+	}
+
+	transition, err := c.StateMachine.Transition(tx, expense, event, auth, joinNotes(budgetCheck.Note(), splitNote, duplicateNote))
+	if err != nil {
+		return nil, err
 	}
-	if err := c.recordStatusChange(tx, expense, &auth.UserID, "", expense.Status, ""); err != nil {
-		c.Log.Warnf("Failed to create expense history: %+v", err)
+	if err := c.createApprovalSteps(tx, expense, steps); err != nil {
+		c.Log.Warnf("Failed to create approval steps: %+v", err)
 		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
 	}
 
+	if err := c.enqueuePayment(tx, expense, transition); err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		c.Log.Warnf("Failed to commit transaction: %+v", err)
 		return nil, utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
 	}
 
-	if !requiresApproval {
-		c.enqueuePayment(expense)
-	} else {
-		if err := c.notifyApprovalRequest(ctx, expense); err != nil {
-			c.Log.Warnf("Failed to send approval notification: %+v", err)
-		}
-	}
-
+	c.runSideEffects(ctx, expense, transition, firstStep(steps))
 	return converter.ExpenseToResponse(expense, false), nil
 }
 
-func (c *ExpenseUseCase) List(ctx context.Context, auth *model.Auth, status string, page, size int) ([]model.ExpenseResponse, model.PageMetadata, error) {
+func (c *ExpenseUseCase) List(ctx context.Context, auth *model.Auth, request *model.ListExpenseRequest) ([]model.ExpenseResponse, model.PageMetadata, error) {
 	tx := c.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
-	filter := repository.ExpenseFilter{}
-	if !isManager(auth) {
-		filter.UserID = &auth.UserID
-	}
-
-	status = normalizeStatusFilter(status)
-	if status != "" {
-		filter.Status = &status
+	filter, err := buildExpenseFilter(auth, request)
+	if err != nil {
+		return nil, model.PageMetadata{}, err
 	}
 
+	page := request.Page
 	if page < 1 {
 		page = 1
 	}
+	size := request.Size
 	if size < 1 {
 		size = 10
 	}
 
-	expenses, total, err := c.ExpenseRepository.List(tx, filter, page, size)
+	var cursor *utils.Cursor
+	if request.Cursor != nil {
+		if cursor, err = utils.DecodeCursor(*request.Cursor); err != nil {
+			return nil, model.PageMetadata{}, utils.Error(messages.ErrInvalidCursor, http.StatusBadRequest, err)
+		}
+		if len(filter.Sort) > 1 || (len(filter.Sort) == 1 && filter.Sort[0].Field != "submitted_at") {
+			return nil, model.PageMetadata{}, utils.Error(messages.ErrCursorSortUnsupported, http.StatusBadRequest, nil)
+		}
+	}
+
+	var expenses []entity.Expense
+	var hasMore bool
+	if request.Cursor != nil {
+		expenses, hasMore, err = c.ExpenseRepository.ListByCursor(tx, filter, cursor, size)
+	} else {
+		expenses, hasMore, err = c.ExpenseRepository.List(tx, filter, page, size)
+	}
 	if err != nil {
 		c.Log.Warnf("Failed to list expenses: %+v", err)
 		return nil, model.PageMetadata{}, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
 	}
 
+	includeTotal := request.Cursor == nil
+	if request.IncludeTotal != nil {
+		includeTotal = *request.IncludeTotal
+	}
+	var total *int64
+	if includeTotal {
+		count, err := c.ExpenseRepository.Count(tx, filter)
+		if err != nil {
+			c.Log.Warnf("Failed to count expenses: %+v", err)
+			return nil, model.PageMetadata{}, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+		}
+		total = &count
+	}
+
 	responses := make([]model.ExpenseResponse, 0, len(expenses))
-	includeUserID := isManager(auth)
+	includeUserID := isReviewer(auth)
 	for i := range expenses {
 		responses = append(responses, *converter.ExpenseToResponse(&expenses[i], includeUserID))
 	}
@@ -145,8 +238,18 @@ func (c *ExpenseUseCase) List(ctx context.Context, auth *model.Auth, status stri
 		return nil, model.PageMetadata{}, utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
 	}
 
-	paging := utils.NewPageMetadata(page, size, total)
-	return responses, paging, nil
+	if request.Cursor != nil {
+		var first, last *utils.Cursor
+		if len(expenses) > 0 {
+			first = &utils.Cursor{Time: expenses[0].SubmittedAt, ID: expenses[0].ID}
+			last = &utils.Cursor{Time: expenses[len(expenses)-1].SubmittedAt, ID: expenses[len(expenses)-1].ID}
+		}
+		return responses, utils.NewCursorPageMetadata(size, cursor, hasMore, first, last, total), nil
+	}
+	if total != nil {
+		return responses, utils.NewPageMetadata(page, size, *total), nil
+	}
+	return responses, utils.NewPageMetadataWithoutTotal(page, size, hasMore), nil
 }
 
 func (c *ExpenseUseCase) Get(ctx context.Context, auth *model.Auth, expenseID uuid.UUID) (*model.ExpenseDetailResponse, error) {
@@ -158,7 +261,7 @@ func (c *ExpenseUseCase) Get(ctx context.Context, auth *model.Auth, expenseID uu
 		return nil, utils.Error(messages.ErrExpenseNotFound, http.StatusNotFound, err)
 	}
 
-	if !isManager(auth) && expense.UserID != auth.UserID {
+	if !canViewExpense(auth, expense) {
 		return nil, utils.Error(messages.Forbidden, http.StatusForbidden, nil)
 	}
 
@@ -168,47 +271,131 @@ func (c *ExpenseUseCase) Get(ctx context.Context, auth *model.Auth, expenseID uu
 		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
 	}
 
+	var category *entity.ExpenseCategory
+	if expense.CategoryID != nil {
+		category = new(entity.ExpenseCategory)
+		if err := c.CategoryRepository.FindById(tx, category, *expense.CategoryID); err != nil {
+			c.Log.Warnf("Failed to load expense category: %+v", err)
+			return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+		}
+	}
+
+	receipts, err := c.ReceiptRepository.ListByExpenseID(tx, expense.ID)
+	if err != nil {
+		c.Log.Warnf("Failed to list receipts: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	payment, err := c.PaymentRepository.FindByExpenseID(tx, expense.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.Log.Warnf("Failed to load payment: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	var duplicate *entity.Expense
+	if expense.PossibleDuplicateOf != nil {
+		duplicate = new(entity.Expense)
+		if err := c.ExpenseRepository.FindById(tx, duplicate, *expense.PossibleDuplicateOf); err != nil {
+			c.Log.Warnf("Failed to load possible duplicate expense: %+v", err)
+			duplicate = nil
+		}
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		c.Log.Warnf("Failed to commit transaction: %+v", err)
 		return nil, utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
 	}
 
-	includeUserID := isManager(auth)
+	includeUserID := isReviewer(auth)
 	response := model.ExpenseDetailResponse{
 		ExpenseResponse: *converter.ExpenseToResponse(expense, includeUserID),
 	}
+	if duplicate != nil {
+		response.PossibleDuplicate = converter.ExpenseToResponse(duplicate, includeUserID)
+	}
+	if category != nil {
+		response.Category = converter.ExpenseCategoryToResponse(category)
+	}
+	if payment != nil {
+		response.Payment = converter.PaymentToResponse(payment)
+	}
 	if len(approvals) > 0 {
 		response.Approvals = make([]model.ApprovalResponse, 0, len(approvals))
 		for i := range approvals {
 			response.Approvals = append(response.Approvals, converter.ApprovalToResponse(&approvals[i]))
 		}
 	}
+	if len(receipts) > 0 {
+		response.Receipts = make([]model.ExpenseReceiptResponse, 0, len(receipts))
+		for i := range receipts {
+			response.Receipts = append(response.Receipts, *converter.ExpenseReceiptToResponse(&receipts[i]))
+		}
+	}
 
 	return &response, nil
 }
 
-func (c *ExpenseUseCase) History(ctx context.Context, auth *model.Auth, expenseID uuid.UUID) ([]model.ExpenseStatusHistoryResponse, error) {
+func (c *ExpenseUseCase) History(ctx context.Context, auth *model.Auth, expenseID uuid.UUID, request *model.ListHistoryRequest) ([]model.ExpenseStatusHistoryResponse, *model.PageMetadata, error) {
 	tx := c.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
 	expense := new(entity.Expense)
 	if err := c.ExpenseRepository.FindById(tx, expense, expenseID); err != nil {
-		return nil, utils.Error(messages.ErrExpenseNotFound, http.StatusNotFound, err)
+		return nil, nil, utils.Error(messages.ErrExpenseNotFound, http.StatusNotFound, err)
 	}
 
-	if !isManager(auth) && expense.UserID != auth.UserID {
-		return nil, utils.Error(messages.Forbidden, http.StatusForbidden, nil)
+	if !canViewExpense(auth, expense) {
+		return nil, nil, utils.Error(messages.Forbidden, http.StatusForbidden, nil)
 	}
 
-	histories, err := c.HistoryRepository.ListByExpenseID(tx, expense.ID)
-	if err != nil {
-		c.Log.Warnf("Failed to list expense histories: %+v", err)
-		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	var histories []entity.ExpenseStatusHistory
+	var paging *model.PageMetadata
+	if request.Cursor == nil {
+		var err error
+		histories, err = c.HistoryRepository.ListByExpenseID(tx, expense.ID)
+		if err != nil {
+			c.Log.Warnf("Failed to list expense histories: %+v", err)
+			return nil, nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+		}
+	} else {
+		cursor, err := utils.DecodeCursor(*request.Cursor)
+		if err != nil {
+			return nil, nil, utils.Error(messages.ErrInvalidCursor, http.StatusBadRequest, err)
+		}
+		size := request.Size
+		if size < 1 {
+			size = 10
+		}
+
+		var hasMore bool
+		histories, hasMore, err = c.HistoryRepository.ListByExpenseIDCursor(tx, expense.ID, cursor, size)
+		if err != nil {
+			c.Log.Warnf("Failed to list expense histories: %+v", err)
+			return nil, nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+		}
+
+		var total *int64
+		if request.IncludeTotal {
+			count, err := c.HistoryRepository.CountByExpenseID(tx, expense.ID)
+			if err != nil {
+				c.Log.Warnf("Failed to count expense histories: %+v", err)
+				return nil, nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+			}
+			total = &count
+		}
+
+		var first, last *utils.Cursor
+		if len(histories) > 0 {
+			first = &utils.Cursor{Time: histories[0].CreatedAt, ID: histories[0].ID}
+			last = &utils.Cursor{Time: histories[len(histories)-1].CreatedAt, ID: histories[len(histories)-1].ID}
+		}
+		meta := utils.NewCursorPageMetadata(size, cursor, hasMore, first, last, total)
+		paging = &meta
 	}
 
 	if err := tx.Commit().Error; err != nil {
 		c.Log.Warnf("Failed to commit transaction: %+v", err)
-		return nil, utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
+		return nil, nil, utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
 	}
 
 	responses := make([]model.ExpenseStatusHistoryResponse, 0, len(histories))
@@ -216,14 +403,61 @@ func (c *ExpenseUseCase) History(ctx context.Context, auth *model.Auth, expenseI
 		responses = append(responses, converter.ExpenseStatusHistoryToResponse(&histories[i]))
 	}
 
-	return responses, nil
+	return responses, paging, nil
 }
 
-func (c *ExpenseUseCase) Approve(ctx context.Context, auth *model.Auth, expenseID uuid.UUID, request *model.ApproveExpenseRequest) (*model.ExpenseResponse, error) {
-	if !isManager(auth) {
-		return nil, utils.Error(messages.Forbidden, http.StatusForbidden, nil)
+func (c *ExpenseUseCase) Export(ctx context.Context, auth *model.Auth, request *model.ListExpenseRequest, writer utils.ExportWriter) error {
+	if !isReviewer(auth) {
+		return utils.Error(messages.Forbidden, http.StatusForbidden, nil)
+	}
+
+	filter, err := buildExpenseFilter(auth, request)
+	if err != nil {
+		return err
+	}
+
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := writer.WriteHeader(expenseExportColumns); err != nil {
+		return err
+	}
+
+	err = c.ExpenseRepository.StreamExport(tx, filter, func(row *repository.ExpenseExportRow) error {
+		processedAt := ""
+		if row.ProcessedAt != nil {
+			processedAt = row.ProcessedAt.Format(time.RFC3339)
+		}
+		return writer.WriteRow([]any{
+			row.ID.String(),
+			row.RequesterName,
+			row.RequesterEmail,
+			row.CategoryName,
+			row.Description,
+			row.AmountIDR,
+			utils.FormatIDR(row.AmountIDR),
+			row.Status,
+			row.Approvers,
+			row.ApprovalNotes,
+			row.SubmittedAt.Format(time.RFC3339),
+			processedAt,
+			row.PaymentRef,
+		})
+	})
+	if err != nil {
+		c.Log.Warnf("Failed to export expenses: %+v", err)
+		return utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed to commit transaction: %+v", err)
+		return utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
 	}
 
+	return writer.Close()
+}
+
+func (c *ExpenseUseCase) Approve(ctx context.Context, auth *model.Auth, expenseID uuid.UUID, request *model.ApproveExpenseRequest) (*model.ExpenseResponse, error) {
 	tx := c.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
@@ -232,47 +466,76 @@ func (c *ExpenseUseCase) Approve(ctx context.Context, auth *model.Auth, expenseI
 		return nil, utils.Error(messages.ErrExpenseNotFound, http.StatusNotFound, err)
 	}
 
-	if expense.Status != constants.ExpenseStatusAwaitingApproval {
-		return nil, utils.Error(messages.ErrExpenseNotPending, http.StatusConflict, nil)
+	if _, err := c.StateMachine.Resolve(expense, ExpenseEventApprove, auth); err != nil {
+		return nil, err
 	}
-
-	approval := &entity.Approval{
-		ExpenseID:  expense.ID,
-		ApproverID: auth.UserID,
-		Status:     constants.ApprovalStatusApproved,
-		Notes:      strings.TrimSpace(request.Notes),
+	if err := ensureExpectedVersion(expense, request.ExpectedVersion); err != nil {
+		return nil, err
 	}
 
-	if err := c.ApprovalRepository.Create(tx, approval); err != nil {
-		c.Log.Warnf("Failed to create approval: %+v", err)
+	approval, err := c.currentApprovalStep(tx, expense)
+	if err != nil {
+		c.Log.Warnf("Failed to load current approval step: %+v", err)
 		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
 	}
+	if err := c.ensureCanDecide(tx, expense, approval, auth); err != nil {
+		return nil, err
+	}
 
-	previousStatus := expense.Status
-	expense.Status = constants.ExpenseStatusApproved
-	if err := c.ExpenseRepository.Update(tx, expense); err != nil {
-		c.Log.Warnf("Failed to update expense: %+v", err)
+	if err := c.decideApprovalStep(tx, approval, auth, constants.ApprovalStatusApproved, request.Notes); err != nil {
+		c.Log.Warnf("Failed to update approval: %+v", err)
 		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
 	}
-	if err := c.recordStatusChange(tx, expense, &auth.UserID, previousStatus, expense.Status, approval.Notes); err != nil {
-		c.Log.Warnf("Failed to create expense history: %+v", err)
+
+	nextStep := new(entity.Approval)
+	err = c.ApprovalRepository.FindCurrentStep(tx, nextStep, expense.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.Log.Warnf("Failed to load next approval step: %+v", err)
 		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
 	}
 
+	if err == nil {
+		if err := c.ApprovalRepository.Activate(tx, nextStep, time.Now()); err != nil {
+			c.Log.Warnf("Failed to activate next approval step: %+v", err)
+			return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+		}
+		if err := c.StateMachine.Touch(tx, expense); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit().Error; err != nil {
+			c.Log.Warnf("Failed to commit transaction: %+v", err)
+			return nil, utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
+		}
+
+		if err := c.notifyApprovalRequest(ctx, expense, nextStep.RequiredRole); err != nil {
+			c.Log.Warnf("Failed to send approval notification: %+v", err)
+		}
+		return converter.ExpenseToResponse(expense, true), nil
+	}
+
+	if err := c.ensureReceiptAttached(tx, expense); err != nil {
+		return nil, err
+	}
+
+	transition, err := c.StateMachine.Transition(tx, expense, ExpenseEventApprove, auth, approval.Notes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.enqueuePayment(tx, expense, transition); err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		c.Log.Warnf("Failed to commit transaction: %+v", err)
 		return nil, utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
 	}
 
-	c.enqueuePayment(expense)
+	c.runSideEffects(ctx, expense, transition, "")
 	return converter.ExpenseToResponse(expense, true), nil
 }
 
 func (c *ExpenseUseCase) Reject(ctx context.Context, auth *model.Auth, expenseID uuid.UUID, request *model.ApproveExpenseRequest) (*model.ExpenseResponse, error) {
-	if !isManager(auth) {
-		return nil, utils.Error(messages.Forbidden, http.StatusForbidden, nil)
-	}
-
 	tx := c.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
@@ -281,185 +544,982 @@ func (c *ExpenseUseCase) Reject(ctx context.Context, auth *model.Auth, expenseID
 		return nil, utils.Error(messages.ErrExpenseNotFound, http.StatusNotFound, err)
 	}
 
-	if expense.Status != constants.ExpenseStatusAwaitingApproval {
-		return nil, utils.Error(messages.ErrExpenseNotPending, http.StatusConflict, nil)
+	if _, err := c.StateMachine.Resolve(expense, ExpenseEventReject, auth); err != nil {
+		return nil, err
 	}
-
-	approval := &entity.Approval{
-		ExpenseID:  expense.ID,
-		ApproverID: auth.UserID,
-		Status:     constants.ApprovalStatusRejected,
-		Notes:      strings.TrimSpace(request.Notes),
+	if err := ensureExpectedVersion(expense, request.ExpectedVersion); err != nil {
+		return nil, err
 	}
 
-	if err := c.ApprovalRepository.Create(tx, approval); err != nil {
-		c.Log.Warnf("Failed to create approval: %+v", err)
+	approval, err := c.currentApprovalStep(tx, expense)
+	if err != nil {
+		c.Log.Warnf("Failed to load current approval step: %+v", err)
 		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
 	}
+	if err := c.ensureCanDecide(tx, expense, approval, auth); err != nil {
+		return nil, err
+	}
 
-	previousStatus := expense.Status
-	expense.Status = constants.ExpenseStatusRejected
-	if err := c.ExpenseRepository.Update(tx, expense); err != nil {
-		c.Log.Warnf("Failed to update expense: %+v", err)
+	if err := c.decideApprovalStep(tx, approval, auth, constants.ApprovalStatusRejected, request.Notes); err != nil {
+		c.Log.Warnf("Failed to update approval: %+v", err)
 		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
 	}
-	if err := c.recordStatusChange(tx, expense, &auth.UserID, previousStatus, expense.Status, approval.Notes); err != nil {
-		c.Log.Warnf("Failed to create expense history: %+v", err)
+	if err := c.ApprovalRepository.SkipPending(tx, expense.ID); err != nil {
+		c.Log.Warnf("Failed to skip remaining approval steps: %+v", err)
 		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
 	}
 
+	transition, err := c.StateMachine.Transition(tx, expense, ExpenseEventReject, auth, approval.Notes)
+	if err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		c.Log.Warnf("Failed to commit transaction: %+v", err)
 		return nil, utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
 	}
 
+	c.runSideEffects(ctx, expense, transition, "")
 	return converter.ExpenseToResponse(expense, true), nil
 }
 
-func (c *ExpenseUseCase) ProcessPayment(ctx context.Context, job model.PaymentJob) error {
-	if c.PaymentProcessor == nil {
-		return utils.Error(messages.ErrPaymentFailed, http.StatusBadGateway, nil)
-	}
-
+func (c *ExpenseUseCase) Update(ctx context.Context, auth *model.Auth, request *model.UpdateExpenseRequest) (*model.ExpenseResponse, error) {
 	tx := c.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
 	expense := new(entity.Expense)
-	if err := tx.Where("id = ?", job.ExpenseID).Take(expense).Error; err != nil {
-		return utils.Error(messages.ErrExpenseNotFound, http.StatusNotFound, err)
+	if err := c.ExpenseRepository.FindById(tx, expense, request.ID); err != nil {
+		return nil, utils.Error(messages.ErrExpenseNotFound, http.StatusNotFound, err)
+	}
+	if _, err := c.StateMachine.Resolve(expense, ExpenseEventEdit, auth); err != nil {
+		return nil, err
+	}
+	if err := ensureExpectedVersion(expense, request.ExpectedVersion); err != nil {
+		return nil, err
 	}
 
-	if expense.Status == constants.ExpenseStatusCompleted || expense.Status == constants.ExpenseStatusRejected {
-		return nil
+	categoryID := expense.CategoryID
+	if request.CategoryID != nil {
+		categoryID = request.CategoryID
+	}
+	if categoryID == nil {
+		return nil, utils.Error(messages.ErrCategoryNotFound, http.StatusBadRequest, nil)
+	}
+	category := new(entity.ExpenseCategory)
+	if err := c.CategoryRepository.FindById(tx, category, *categoryID); err != nil {
+		return nil, utils.Error(messages.ErrCategoryNotFound, http.StatusBadRequest, err)
+	}
+	if !category.IsActive {
+		return nil, utils.Error(messages.ErrCategoryInactive, http.StatusBadRequest, nil)
 	}
 
-	if expense.Status != constants.ExpenseStatusApproved && expense.Status != constants.ExpenseStatusAutoApproved {
-		return nil
+	if request.AmountIDR != nil {
+		expense.AmountIDR = *request.AmountIDR
+	}
+	if request.Description != nil {
+		expense.Description = strings.TrimSpace(*request.Description)
+	}
+	if request.ReceiptURL != nil {
+		expense.ReceiptURL = strings.TrimSpace(*request.ReceiptURL)
 	}
+	expense.CategoryID = &category.ID
 
-	if expense.ProcessedAt != nil {
-		return nil
+	if err := validateExpenseAmount(expense.AmountIDR, category); err != nil {
+		return nil, err
+	}
+	if expense.Description == "" {
+		return nil, utils.Error(messages.InvalidRequestData, http.StatusBadRequest, nil)
 	}
 
-	_, err := c.PaymentProcessor.Process(ctx, model.PaymentRequest{
-		Amount:     job.AmountIDR,
-		ExternalID: job.ExternalID,
-	})
+	hasReceipt, err := c.hasReceipt(tx, expense)
 	if err != nil {
-		c.Log.Warnf("Payment processing failed for expense %s: %+v", job.ExpenseID, err)
-		return utils.Error(messages.ErrPaymentFailed, http.StatusBadGateway, err)
+		return nil, err
 	}
-
-	now := time.Now()
-	previousStatus := expense.Status
-	expense.Status = constants.ExpenseStatusCompleted
-	expense.ProcessedAt = &now
-	if err := tx.Save(expense).Error; err != nil {
-		c.Log.Warnf("Failed to update expense payment status: %+v", err)
-		return utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	budgetCheck, err := c.BudgetTracker.Check(tx, expense.UserID, expense.AmountIDR, &expense.ID)
+	if err != nil {
+		return nil, err
 	}
-	if err := c.recordStatusChange(tx, expense, nil, previousStatus, expense.Status, ""); err != nil {
-		c.Log.Warnf("Failed to create expense history: %+v", err)
-		return utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	if budgetCheck.Blocked() {
+		return nil, utils.Error(messages.ErrBudgetExceeded, http.StatusUnprocessableEntity, nil)
 	}
 
-	if err := tx.Commit().Error; err != nil {
-		c.Log.Warnf("Failed to commit transaction: %+v", err)
-		return utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
+	steps := c.approvalSteps(category, expense.AmountIDR, hasReceipt)
+	if len(budgetCheck.Exceeded) > 0 && len(steps) == 0 {
+		steps = c.ApprovalPolicy.EntrySteps()
 	}
 
-	return nil
-}
-
-func validateExpenseAmount(amount int64) error {
-	if amount <= 0 {
-		return utils.Error(messages.ErrInvalidExpenseAmount, http.StatusBadRequest, nil)
-	}
-	if amount < constants.MinExpenseAmount || amount > constants.MaxExpenseAmount {
-		return utils.Error(messages.ErrInvalidExpenseAmount, http.StatusBadRequest, nil)
+	splitNote := ""
+	if len(steps) == 0 {
+		steps, splitNote, err = c.splitExpenseSteps(tx, expense.UserID, category, expense.AmountIDR, expense.Description)
+		if err != nil {
+			return nil, err
+		}
 	}
-	return nil
-}
 
-func normalizeStatusFilter(status string) string {
-	status = strings.TrimSpace(strings.ToLower(status))
-	switch status {
-	case "pending":
-		return constants.ExpenseStatusAwaitingApproval
-	case "auto-approved":
-		return constants.ExpenseStatusAutoApproved
+	if err := c.ApprovalRepository.SkipPending(tx, expense.ID); err != nil {
+		c.Log.Warnf("Failed to skip pending approval steps: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
 	}
-	return status
-}
 
-func (c *ExpenseUseCase) enqueuePayment(expense *entity.Expense) {
-	if c.PaymentQueue == nil {
-		return
+	event := ExpenseEventEdit
+	if len(steps) == 0 {
+		event = ExpenseEventAutoApprove
 	}
 
-	job := model.PaymentJob{
-		ExpenseID:  expense.ID,
-		AmountIDR:  expense.AmountIDR,
-		ExternalID: expense.ID.String(),
+	expense.PossibleDuplicateOf = nil
+	duplicateNote, err := c.flagPossibleDuplicate(tx, expense)
+	if err != nil {
+		return nil, err
 	}
-	c.PaymentQueue.Enqueue(job)
-}
 
-func isManager(auth *model.Auth) bool {
-	if auth == nil {
-		return false
+	notes := joinNotes("Expense updated by requester", budgetCheck.Note(), splitNote, duplicateNote)
+
+	expense.RequiresApproval = len(steps) > 0
+	transition, err := c.StateMachine.Transition(tx, expense, event, auth, notes)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.createApprovalSteps(tx, expense, steps); err != nil {
+		c.Log.Warnf("Failed to create approval steps: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
 	}
-	return auth.Role == constants.RoleManager
-}
 
-func (c *ExpenseUseCase) recordStatusChange(
-	tx *gorm.DB,
-	expense *entity.Expense,
-	actorID *uuid.UUID,
-	previousStatus string,
-	newStatus string,
-	notes string,
-) error {
-	if c.HistoryRepository == nil {
-		return nil
+	if err := c.enqueuePayment(tx, expense, transition); err != nil {
+		return nil, err
 	}
 
-	history := &entity.ExpenseStatusHistory{
-		ExpenseID:      expense.ID,
-		ActorID:        actorID,
-		PreviousStatus: previousStatus,
-		NewStatus:      newStatus,
-		Notes:          strings.TrimSpace(notes),
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed to commit transaction: %+v", err)
+		return nil, utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
 	}
 
-	return c.HistoryRepository.Create(tx, history)
+	c.runSideEffects(ctx, expense, transition, firstStep(steps))
+
+	return converter.ExpenseToResponse(expense, false), nil
 }
 
-func (c *ExpenseUseCase) notifyApprovalRequest(ctx context.Context, expense *entity.Expense) error {
-	if c.EmailSender == nil || c.UserRepository == nil {
-		return nil
+func (c *ExpenseUseCase) Cancel(ctx context.Context, auth *model.Auth, expenseID uuid.UUID, request *model.ExpenseActionRequest) (*model.ExpenseResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	expense := new(entity.Expense)
+	if err := c.ExpenseRepository.FindById(tx, expense, expenseID); err != nil {
+		return nil, utils.Error(messages.ErrExpenseNotFound, http.StatusNotFound, err)
 	}
 
-	db := c.DB.WithContext(ctx)
-	managers, err := c.UserRepository.ListByRole(db, constants.RoleManager)
-	if err != nil {
-		return err
+	if _, err := c.StateMachine.Resolve(expense, ExpenseEventCancel, auth); err != nil {
+		return nil, err
 	}
-	if len(managers) == 0 {
-		return nil
+	if err := ensureExpectedVersion(expense, request.ExpectedVersion); err != nil {
+		return nil, err
+	}
+	if err := c.ApprovalRepository.SkipPending(tx, expense.ID); err != nil {
+		c.Log.Warnf("Failed to skip pending approval steps: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
 	}
 
-	recipients := make([]string, 0, len(managers))
-	for _, manager := range managers {
-		if manager.Email != "" {
-			recipients = append(recipients, manager.Email)
-		}
+	transition, err := c.StateMachine.Transition(tx, expense, ExpenseEventCancel, auth, request.Notes)
+	if err != nil {
+		return nil, err
 	}
-	if len(recipients) == 0 {
-		return nil
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed to commit transaction: %+v", err)
+		return nil, utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
 	}
 
-	requestor := &entity.User{}
+	c.runSideEffects(ctx, expense, transition, "")
+	return converter.ExpenseToResponse(expense, false), nil
+}
+
+func (c *ExpenseUseCase) Resubmit(ctx context.Context, auth *model.Auth, expenseID uuid.UUID, request *model.ExpenseActionRequest) (*model.ExpenseResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	expense := new(entity.Expense)
+	if err := c.ExpenseRepository.FindById(tx, expense, expenseID); err != nil {
+		return nil, utils.Error(messages.ErrExpenseNotFound, http.StatusNotFound, err)
+	}
+	if _, err := c.StateMachine.Resolve(expense, ExpenseEventResubmit, auth); err != nil {
+		return nil, err
+	}
+	if err := ensureExpectedVersion(expense, request.ExpectedVersion); err != nil {
+		return nil, err
+	}
+
+	var steps []string
+	if expense.CategoryID != nil {
+		category := new(entity.ExpenseCategory)
+		if err := c.CategoryRepository.FindById(tx, category, *expense.CategoryID); err != nil {
+			c.Log.Warnf("Failed to load expense category: %+v", err)
+			return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+		}
+		hasReceipt, err := c.hasReceipt(tx, expense)
+		if err != nil {
+			return nil, err
+		}
+		steps = c.approvalSteps(category, expense.AmountIDR, hasReceipt)
+	} else {
+		steps = c.ApprovalPolicy.Steps(expense.AmountIDR, 0)
+	}
+	if len(steps) == 0 {
+		steps = c.ApprovalPolicy.EntrySteps()
+	}
+
+	expense.RequiresApproval = true
+	transition, err := c.StateMachine.Transition(tx, expense, ExpenseEventResubmit, auth, request.Notes)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.createApprovalSteps(tx, expense, steps); err != nil {
+		c.Log.Warnf("Failed to create approval steps: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed to commit transaction: %+v", err)
+		return nil, utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
+	}
+
+	c.runSideEffects(ctx, expense, transition, firstStep(steps))
+
+	return converter.ExpenseToResponse(expense, false), nil
+}
+
+func (c *ExpenseUseCase) RetryPayment(ctx context.Context, auth *model.Auth, expenseID uuid.UUID, request *model.ExpenseActionRequest) (*model.ExpenseResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	expense := new(entity.Expense)
+	if err := c.ExpenseRepository.FindById(tx, expense, expenseID); err != nil {
+		return nil, utils.Error(messages.ErrExpenseNotFound, http.StatusNotFound, err)
+	}
+
+	if err := ensureExpectedVersion(expense, request.ExpectedVersion); err != nil {
+		return nil, err
+	}
+
+	notes := strings.TrimSpace(request.Notes)
+	if notes == "" {
+		notes = "Payment retry requested"
+	}
+	transition, err := c.StateMachine.Transition(tx, expense, ExpenseEventRetryPayment, auth, notes)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := c.verifiedBankAccount(tx, expense.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, utils.Error(messages.ErrBankAccountNotVerified, http.StatusUnprocessableEntity, nil)
+	}
+
+	if err := c.enqueuePayment(tx, expense, transition); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed to commit transaction: %+v", err)
+		return nil, utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
+	}
+
+	c.runSideEffects(ctx, expense, transition, "")
+	return converter.ExpenseToResponse(expense, true), nil
+}
+
+const payoutHeldNote = "Payout held until the requester has a verified bank account"
+
+// PaymentHeldError reports a payout that cannot start yet; the payment queue holds
+// the job instead of retrying or failing it.
+type PaymentHeldError struct {
+	Reason string
+}
+
+func (e *PaymentHeldError) Error() string {
+	return e.Reason
+}
+
+func (e *PaymentHeldError) Held() bool {
+	return true
+}
+
+func (c *ExpenseUseCase) ProcessPayment(ctx context.Context, job model.PaymentJob) error {
+	if c.PaymentProcessor == nil {
+		return utils.Error(messages.ErrPaymentFailed, http.StatusBadGateway, nil)
+	}
+
+	account, err := c.startPayment(ctx, job)
+	if err != nil || account == nil {
+		return err
+	}
+
+	if err := c.PaymentRepository.RecordRequest(c.DB.WithContext(ctx), job.ExpenseID, job.ExternalID, job.AmountIDR, time.Now()); err != nil {
+		c.Log.Warnf("Failed to record payment request for expense %s: %+v", job.ExpenseID, err)
+		return utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	payment, err := c.PaymentProcessor.Process(ctx, model.PaymentRequest{
+		Amount:            job.AmountIDR,
+		ExternalID:        job.ExternalID,
+		BankCode:          account.BankCode,
+		AccountNumber:     account.AccountNumber,
+		AccountHolderName: account.AccountHolderName,
+		Description:       "Expense reimbursement " + job.ExpenseID.String(),
+	})
+	if err != nil {
+		c.Log.Warnf("Payment processing failed for expense %s: %+v", job.ExpenseID, err)
+		if recordErr := c.PaymentRepository.RecordError(c.DB.WithContext(ctx), job.ExpenseID, err.Error(), time.Now()); recordErr != nil {
+			c.Log.Warnf("Failed to record payment error for expense %s: %+v", job.ExpenseID, recordErr)
+		}
+		return utils.Error(messages.ErrPaymentFailed, http.StatusBadGateway, err)
+	}
+
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.PaymentRepository.RecordResponse(tx, job.ExpenseID, payment.ID, payment.Status, time.Now()); err != nil {
+		c.Log.Warnf("Failed to record payment response for expense %s: %+v", job.ExpenseID, err)
+		return utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	expense := new(entity.Expense)
+	if err := tx.Where("id = ?", job.ExpenseID).Take(expense).Error; err != nil {
+		return utils.Error(messages.ErrExpenseNotFound, http.StatusNotFound, err)
+	}
+
+	switch PaymentOutcome(payment.Status) {
+	case constants.PaymentProviderStatusPending:
+		c.Log.Infof("Payment for expense %s is pending at the provider, waiting for webhook", job.ExpenseID)
+	case constants.PaymentProviderStatusFailed:
+		if _, err := c.StateMachine.Resolve(expense, ExpenseEventFailPayment, nil); err == nil {
+			reason := "payment provider reported status " + payment.Status
+			if _, err := c.StateMachine.Transition(tx, expense, ExpenseEventFailPayment, nil, reason); err != nil {
+				return err
+			}
+		}
+	default:
+		if _, err := c.StateMachine.Resolve(expense, ExpenseEventCompletePayment, nil); err == nil {
+			now := time.Now()
+			expense.ProcessedAt = &now
+			if _, err := c.StateMachine.Transition(tx, expense, ExpenseEventCompletePayment, nil, ""); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed to commit transaction: %+v", err)
+		return utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
+	}
+
+	return nil
+}
+
+func (c *ExpenseUseCase) MarkPaymentFailed(ctx context.Context, job model.PaymentJob, reason string) error {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	expense := new(entity.Expense)
+	if err := tx.Where("id = ?", job.ExpenseID).Take(expense).Error; err != nil {
+		return utils.Error(messages.ErrExpenseNotFound, http.StatusNotFound, err)
+	}
+
+	if _, err := c.StateMachine.Resolve(expense, ExpenseEventFailPayment, nil); err != nil {
+		return nil
+	}
+	if _, err := c.StateMachine.Transition(tx, expense, ExpenseEventFailPayment, nil, reason); err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed to commit transaction: %+v", err)
+		return utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
+	}
+
+	return nil
+}
+
+func (c *ExpenseUseCase) startPayment(ctx context.Context, job model.PaymentJob) (*entity.UserBankAccount, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	expense := new(entity.Expense)
+	if err := tx.Where("id = ?", job.ExpenseID).Take(expense).Error; err != nil {
+		return nil, utils.Error(messages.ErrExpenseNotFound, http.StatusNotFound, err)
+	}
+
+	if expense.ProcessedAt != nil {
+		return nil, nil
+	}
+
+	processing := expense.Status == constants.ExpenseStatusPaymentProcessing
+	if !processing {
+		if _, err := c.StateMachine.Resolve(expense, ExpenseEventStartPayment, nil); err != nil {
+			return nil, nil
+		}
+	}
+
+	account, err := c.verifiedBankAccount(tx, expense.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		// the expense stays payable; verifying the account resumes the held job
+		c.Log.Warnf("Holding payout for expense %s: requester has no verified bank account", job.ExpenseID)
+		if err := c.StateMachine.Note(tx, expense, payoutHeldNote); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit().Error; err != nil {
+			c.Log.Warnf("Failed to commit transaction: %+v", err)
+			return nil, utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
+		}
+		return nil, &PaymentHeldError{Reason: messages.ErrBankAccountNotVerified}
+	}
+
+	if !processing {
+		if _, err := c.StateMachine.Transition(tx, expense, ExpenseEventStartPayment, nil, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed to commit transaction: %+v", err)
+		return nil, utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
+	}
+
+	return account, nil
+}
+
+func (c *ExpenseUseCase) verifiedBankAccount(db *gorm.DB, userID uuid.UUID) (*entity.UserBankAccount, error) {
+	account, err := c.BankAccountRepository.FindVerifiedByUserID(db, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		c.Log.Warnf("Failed to find bank account for user %s: %+v", userID, err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+	return account, nil
+}
+
+func validateExpenseAmount(amount int64, category *entity.ExpenseCategory) error {
+	if amount <= 0 {
+		return utils.Error(messages.ErrInvalidExpenseAmount, http.StatusBadRequest, nil)
+	}
+	if amount < category.MinAmountIDR || amount > category.MaxAmountIDR {
+		return utils.Error(messages.ErrInvalidExpenseAmount, http.StatusBadRequest, nil)
+	}
+	return nil
+}
+
+func receiptRequired(category *entity.ExpenseCategory, amount int64) bool {
+	if category.ReceiptRequiredAboveIDR == nil {
+		return false
+	}
+	return amount > *category.ReceiptRequiredAboveIDR
+}
+
+func (c *ExpenseUseCase) approvalSteps(category *entity.ExpenseCategory, amount int64, hasReceipt bool) []string {
+	steps := c.ApprovalPolicy.Steps(amount, category.ApprovalThresholdIDR)
+	if len(steps) == 0 && receiptRequired(category, amount) && !hasReceipt {
+		steps = c.ApprovalPolicy.EntrySteps()
+	}
+	return steps
+}
+
+func (c *ExpenseUseCase) splitExpenseSteps(tx *gorm.DB, userID uuid.UUID, category *entity.ExpenseCategory, amount int64, description string) ([]string, string, error) {
+	if !c.SplitPolicy.Enabled() {
+		return []string{}, "", nil
+	}
+
+	var categoryID *uuid.UUID
+	if c.SplitPolicy.SameCategory {
+		categoryID = &category.ID
+	}
+	recent, err := c.ExpenseRepository.ListAutoApprovedSince(tx, userID, categoryID, time.Now().Add(-c.SplitPolicy.Window))
+	if err != nil {
+		c.Log.Warnf("Failed to list recent auto-approved expenses: %+v", err)
+		return nil, "", utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	related := c.SplitPolicy.Related(description, recent)
+	if len(related) == 0 {
+		return []string{}, "", nil
+	}
+
+	total := amount
+	for _, expense := range related {
+		total += expense.AmountIDR
+	}
+	if total < category.ApprovalThresholdIDR {
+		return []string{}, "", nil
+	}
+
+	note := fmt.Sprintf("Possible split expense: %d auto-approved expense(s) in the last %g hours bring the total to %s, reaching the %s approval threshold of %s",
+		len(related), c.SplitPolicy.Window.Hours(), utils.FormatIDR(total), category.Code, utils.FormatIDR(category.ApprovalThresholdIDR))
+	return c.ApprovalPolicy.Steps(total, category.ApprovalThresholdIDR), note, nil
+}
+
+func (c *ExpenseUseCase) flagPossibleDuplicate(tx *gorm.DB, expense *entity.Expense) (string, error) {
+	if !c.DuplicatePolicy.Enabled() {
+		return "", nil
+	}
+
+	candidates, err := c.ExpenseRepository.ListDuplicateCandidates(tx, expense, time.Now().Add(-c.DuplicatePolicy.Window))
+	if err != nil {
+		c.Log.Warnf("Failed to list duplicate candidates: %+v", err)
+		return "", utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	duplicate := c.DuplicatePolicy.Match(expense, candidates)
+	if duplicate == nil {
+		return "", nil
+	}
+
+	expense.PossibleDuplicateOf = &duplicate.ID
+	return fmt.Sprintf("Possible duplicate of expense %s", duplicate.ID), nil
+}
+
+func joinNotes(notes ...string) string {
+	parts := make([]string, 0, len(notes))
+	for _, note := range notes {
+		if note != "" {
+			parts = append(parts, note)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (c *ExpenseUseCase) hasReceipt(tx *gorm.DB, expense *entity.Expense) (bool, error) {
+	if expense.ReceiptURL != "" {
+		return true, nil
+	}
+	total, err := c.ReceiptRepository.CountByExpenseID(tx, expense.ID)
+	if err != nil {
+		c.Log.Warnf("Failed to count receipts: %+v", err)
+		return false, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+	return total > 0, nil
+}
+
+func (c *ExpenseUseCase) ensureReceiptAttached(tx *gorm.DB, expense *entity.Expense) error {
+	if expense.CategoryID == nil || expense.ReceiptURL != "" {
+		return nil
+	}
+
+	category := new(entity.ExpenseCategory)
+	if err := c.CategoryRepository.FindById(tx, category, *expense.CategoryID); err != nil {
+		c.Log.Warnf("Failed to load expense category: %+v", err)
+		return utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+	if !receiptRequired(category, expense.AmountIDR) {
+		return nil
+	}
+
+	attached, err := c.hasReceipt(tx, expense)
+	if err != nil {
+		return err
+	}
+	if !attached {
+		return utils.Error(messages.ErrReceiptRequired, http.StatusConflict, nil)
+	}
+	return nil
+}
+
+var expenseExportColumns = []string{
+	"Expense ID",
+	"Requester Name",
+	"Requester Email",
+	"Category",
+	"Description",
+	"Amount IDR",
+	"Amount",
+	"Status",
+	"Approvers",
+	"Approval Notes",
+	"Submitted At",
+	"Processed At",
+	"Payment Reference",
+}
+
+func buildExpenseFilter(auth *model.Auth, request *model.ListExpenseRequest) (repository.ExpenseFilter, error) {
+	filter := repository.ExpenseFilter{
+		CategoryID:        request.CategoryID,
+		SubmittedFrom:     request.SubmittedFrom,
+		SubmittedTo:       request.SubmittedTo,
+		MinAmountIDR:      request.MinAmountIDR,
+		MaxAmountIDR:      request.MaxAmountIDR,
+		Search:            strings.TrimSpace(request.Query),
+		PossibleDuplicate: request.PossibleDuplicate,
+	}
+
+	if isReviewer(auth) {
+		filter.UserID = request.UserID
+	} else {
+		filter.UserID = &auth.UserID
+	}
+
+	for _, raw := range request.Statuses {
+		status := normalizeStatusFilter(raw)
+		if status == "" {
+			continue
+		}
+		if !slices.Contains(constants.ExpenseStatuses, status) {
+			return filter, utils.Error(messages.ErrInvalidExpenseFilter, http.StatusBadRequest, nil)
+		}
+		if !slices.Contains(filter.Statuses, status) {
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	if filter.SubmittedFrom != nil && filter.SubmittedTo != nil && !filter.SubmittedFrom.Before(*filter.SubmittedTo) {
+		return filter, utils.Error(messages.ErrInvalidExpenseFilter, http.StatusBadRequest, nil)
+	}
+	if filter.MinAmountIDR != nil && filter.MaxAmountIDR != nil && *filter.MinAmountIDR > *filter.MaxAmountIDR {
+		return filter, utils.Error(messages.ErrInvalidExpenseFilter, http.StatusBadRequest, nil)
+	}
+
+	sorts, err := ParseExpenseSort(request.Sort)
+	if err != nil {
+		return filter, err
+	}
+	filter.Sort = sorts
+
+	return filter, nil
+}
+
+func ParseExpenseSort(raw string) ([]repository.ExpenseSort, error) {
+	sorts := make([]repository.ExpenseSort, 0)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(strings.ToLower(part))
+		if part == "" {
+			continue
+		}
+
+		sort := repository.ExpenseSort{}
+		field, direction, hasDirection := strings.Cut(part, ":")
+		if strings.HasPrefix(field, "-") && !hasDirection {
+			field = strings.TrimPrefix(field, "-")
+			direction = "desc"
+		}
+		switch direction {
+		case "", "asc":
+		case "desc":
+			sort.Desc = true
+		default:
+			return nil, utils.Error(messages.ErrInvalidExpenseSort, http.StatusBadRequest, nil)
+		}
+
+		if _, ok := repository.ExpenseSortColumns[field]; !ok {
+			return nil, utils.Error(messages.ErrInvalidExpenseSort, http.StatusBadRequest, nil)
+		}
+		sort.Field = field
+		sorts = append(sorts, sort)
+	}
+	return sorts, nil
+}
+
+func normalizeStatusFilter(status string) string {
+	status = strings.TrimSpace(strings.ToLower(status))
+	switch status {
+	case "pending":
+		return constants.ExpenseStatusAwaitingApproval
+	case "auto-approved":
+		return constants.ExpenseStatusAutoApproved
+	}
+	return status
+}
+
+func (c *ExpenseUseCase) enqueuePayment(tx *gorm.DB, expense *entity.Expense, transition *ExpenseTransition) error {
+	if c.PaymentQueue == nil || !transition.HasSideEffect(ExpenseEffectEnqueuePayment) {
+		return nil
+	}
+
+	job := model.PaymentJob{
+		ExpenseID:  expense.ID,
+		AmountIDR:  expense.AmountIDR,
+		ExternalID: expense.ID.String(),
+	}
+	if err := c.PaymentQueue.Enqueue(tx, job); err != nil {
+		c.Log.Warnf("Failed to enqueue payment for expense %s: %+v", expense.ID, err)
+		return utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+	return nil
+}
+
+func isManager(auth *model.Auth) bool {
+	if auth == nil {
+		return false
+	}
+	return auth.Role == constants.RoleManager
+}
+
+func isReviewer(auth *model.Auth) bool {
+	if auth == nil {
+		return false
+	}
+	return isApproverRole(auth.Role)
+}
+
+func ensureExpectedVersion(expense *entity.Expense, expected *int64) error {
+	if expected != nil && *expected != expense.Version {
+		return utils.Error(messages.ErrExpenseVersionMismatch, http.StatusPreconditionFailed, nil)
+	}
+	return nil
+}
+
+func canViewExpense(auth *model.Auth, expense *entity.Expense) bool {
+	return isReviewer(auth) || expense.UserID == auth.UserID
+}
+
+func (c *ExpenseUseCase) createApprovalSteps(tx *gorm.DB, expense *entity.Expense, roles []string) error {
+	if len(roles) == 0 {
+		return nil
+	}
+
+	first, err := c.ApprovalRepository.NextStepNumber(tx, expense.ID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i, role := range roles {
+		approval := &entity.Approval{
+			ExpenseID:    expense.ID,
+			Step:         first + i,
+			RequiredRole: role,
+			Status:       constants.ApprovalStatusPending,
+		}
+		if i == 0 {
+			approval.ActivatedAt = &now
+		}
+		if err := c.ApprovalRepository.Create(tx, approval); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *ExpenseUseCase) currentApprovalStep(tx *gorm.DB, expense *entity.Expense) (*entity.Approval, error) {
+	approval := new(entity.Approval)
+	err := c.ApprovalRepository.FindCurrentStep(tx, approval, expense.ID)
+	if err == nil {
+		return approval, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// Expenses submitted before approval chains existed have no steps; treat
+	// them as a single manager step.
+	if err := c.createApprovalSteps(tx, expense, []string{constants.RoleManager}); err != nil {
+		return nil, err
+	}
+	if err := c.ApprovalRepository.FindCurrentStep(tx, approval, expense.ID); err != nil {
+		return nil, err
+	}
+	return approval, nil
+}
+
+func (c *ExpenseUseCase) ensureCanDecide(tx *gorm.DB, expense *entity.Expense, approval *entity.Approval, auth *model.Auth) error {
+	if expense.UserID == auth.UserID {
+		return utils.Error(messages.ErrSelfApproval, http.StatusForbidden, nil)
+	}
+
+	route, err := c.approvalRoute(tx, expense.UserID, approval.RequiredRole)
+	if err != nil {
+		c.Log.Warnf("Failed to load reporting line: %+v", err)
+		return utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+	if route.canDecide(auth.UserID, approval.EscalationLevel) {
+		return nil
+	}
+
+	if approval.RequiredRole != auth.Role {
+		return utils.Error(messages.ErrApprovalStepRole, http.StatusForbidden, nil)
+	}
+	if len(route.approvers) == 0 {
+		// Open roles (finance by default) are shared desks outside the
+		// reporting line; any other role must be held by someone in it.
+		if c.ApprovalPolicy.IsOpenRole(approval.RequiredRole) {
+			return nil
+		}
+		return utils.Error(messages.ErrNoApproverInChain, http.StatusForbidden, nil)
+	}
+	return utils.Error(messages.ErrApproverOutsideChain, http.StatusForbidden, nil)
+}
+
+type approvalRoute struct {
+	approvers  []entity.User
+	escalation []entity.User
+}
+
+func (r *approvalRoute) canDecide(userID uuid.UUID, escalationLevel int) bool {
+	for _, approver := range r.approvers {
+		if approver.ID == userID {
+			return true
+		}
+	}
+	for i := 0; i < escalationLevel && i < len(r.escalation); i++ {
+		if r.escalation[i].ID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *ExpenseUseCase) approvalRoute(db *gorm.DB, requesterID uuid.UUID, role string) (*approvalRoute, error) {
+	route := &approvalRoute{}
+	if c.UserRepository == nil {
+		return route, nil
+	}
+
+	chain, err := c.UserRepository.ListManagementChain(db, requesterID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range chain {
+		// Overdue steps escalate, one level at a time, to the approvers above
+		// the nearest holder of the role.
+		if len(route.approvers) > 0 && isApproverRole(user.Role) {
+			route.escalation = append(route.escalation, user)
+		}
+		if user.Role == role {
+			route.approvers = append(route.approvers, user)
+		}
+	}
+	return route, nil
+}
+
+func (c *ExpenseUseCase) decideApprovalStep(tx *gorm.DB, approval *entity.Approval, auth *model.Auth, status string, notes string) error {
+	now := time.Now()
+	approverID := auth.UserID
+	approval.ApproverID = &approverID
+	approval.Status = status
+	approval.Notes = strings.TrimSpace(notes)
+	approval.DecidedAt = &now
+	return c.ApprovalRepository.Update(tx, approval)
+}
+
+func (c *ExpenseUseCase) runSideEffects(ctx context.Context, expense *entity.Expense, transition *ExpenseTransition, approverRole string) {
+	if transition.HasSideEffect(ExpenseEffectEnqueuePayment) && c.PaymentQueue != nil {
+		c.PaymentQueue.Notify()
+	}
+	if transition.HasSideEffect(ExpenseEffectNotifyApprover) && approverRole != "" {
+		if err := c.notifyApprovalRequest(ctx, expense, approverRole); err != nil {
+			c.Log.Warnf("Failed to send approval notification: %+v", err)
+		}
+	}
+}
+
+func firstStep(steps []string) string {
+	if len(steps) == 0 {
+		return ""
+	}
+	return steps[0]
+}
+
+func (c *ExpenseUseCase) notifyApprovalRequest(ctx context.Context, expense *entity.Expense, role string) error {
+	if c.EmailSender == nil || c.UserRepository == nil {
+		return nil
+	}
+
+	db := c.DB.WithContext(ctx)
+	route, err := c.approvalRoute(db, expense.UserID, role)
+	if err != nil {
+		return err
+	}
+
+	var approvers []entity.User
+	switch {
+	case len(route.approvers) > 0:
+		approvers = route.approvers[:1]
+	case c.ApprovalPolicy.IsOpenRole(role):
+		approvers, err = c.UserRepository.ListByRole(db, role)
+		if err != nil {
+			return err
+		}
+	default:
+		c.Log.Warnf("No %s in the reporting line of requester %s for expense %s", role, expense.UserID, expense.ID)
+		return nil
+	}
+
+	subject := fmt.Sprintf("Approval diperlukan: %s", expense.Description)
+	return c.sendApprovalEmail(ctx, db, expense, role, approvers, subject, "Pengajuan pengeluaran baru membutuhkan persetujuan.")
+}
+
+func (c *ExpenseUseCase) EscalateApprovals(ctx context.Context, limit int) (int, error) {
+	if c.EscalationAfter <= 0 {
+		return 0, nil
+	}
+
+	db := c.DB.WithContext(ctx)
+	now := time.Now()
+	approvals, err := c.ApprovalRepository.ListEscalationDue(db, now.Add(-c.EscalationAfter), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	escalated := 0
+	for i := range approvals {
+		approval := &approvals[i]
+
+		expense := new(entity.Expense)
+		if err := c.ExpenseRepository.FindById(db, expense, approval.ExpenseID); err != nil {
+			c.Log.Warnf("Failed to load expense %s for escalation: %+v", approval.ExpenseID, err)
+			continue
+		}
+		route, err := c.approvalRoute(db, expense.UserID, approval.RequiredRole)
+		if err != nil {
+			c.Log.Warnf("Failed to load reporting line for expense %s: %+v", expense.ID, err)
+			continue
+		}
+
+		// The level moves on even when nobody is left above, so the step is
+		// not picked up again until another escalation period has passed.
+		level := approval.EscalationLevel + 1
+		marked, err := c.ApprovalRepository.MarkEscalated(db, approval, level, now)
+		if err != nil {
+			c.Log.Warnf("Failed to escalate approval %s: %+v", approval.ID, err)
+			continue
+		}
+		if !marked {
+			continue
+		}
+		if level > len(route.escalation) {
+			c.Log.Warnf("Approval step %d for expense %s is overdue with no one left to escalate to", approval.Step, expense.ID)
+			continue
+		}
+
+		target := route.escalation[level-1]
+		subject := fmt.Sprintf("Eskalasi approval: %s", expense.Description)
+		if err := c.sendApprovalEmail(ctx, db, expense, target.Role, []entity.User{target}, subject, "Pengajuan pengeluaran belum diputuskan dan dieskalasi kepada Anda."); err != nil {
+			c.Log.Warnf("Failed to send escalation notification: %+v", err)
+		}
+		escalated++
+	}
+	return escalated, nil
+}
+
+func (c *ExpenseUseCase) sendApprovalEmail(ctx context.Context, db *gorm.DB, expense *entity.Expense, role string, approvers []entity.User, subject string, intro string) error {
+	if c.EmailSender == nil || c.UserRepository == nil {
+		return nil
+	}
+
+	recipients := make([]string, 0, len(approvers))
+	for _, approver := range approvers {
+		if approver.Email != "" && approver.ID != expense.UserID {
+			recipients = append(recipients, approver.Email)
+		}
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	requestor := &entity.User{}
 	if err := c.UserRepository.FindById(db, requestor, expense.UserID); err != nil {
 		if c.Log != nil {
 			c.Log.Warnf("Failed to load requestor for approval email: %+v", err)
@@ -471,9 +1531,10 @@ func (c *ExpenseUseCase) notifyApprovalRequest(ctx context.Context, expense *ent
 		requestorName = "Karyawan"
 	}
 
-	subject := fmt.Sprintf("Approval diperlukan: %s", expense.Description)
 	body := fmt.Sprintf(
-		"Halo Manager,\n\nPengajuan pengeluaran baru membutuhkan persetujuan.\n\nPengaju: %s (%s)\nJumlah: %s\nDeskripsi: %s\nWaktu: %s\n\nSilakan login untuk memberi keputusan.\nID Pengajuan: %s\n",
+		"Halo %s,\n\n%s\n\nPengaju: %s (%s)\nJumlah: %s\nDeskripsi: %s\nWaktu: %s\n\nSilakan login untuk memberi keputusan.\nID Pengajuan: %s\n",
+		approverTitle(role),
+		intro,
 		requestorName,
 		requestor.Email,
 		utils.FormatIDR(expense.AmountIDR),
@@ -488,3 +1549,10 @@ func (c *ExpenseUseCase) notifyApprovalRequest(ctx context.Context, expense *ent
 		Body:    body,
 	})
 }
+
+func approverTitle(role string) string {
+	if role == "" {
+		return "Approver"
+	}
+	return strings.ToUpper(role[:1]) + role[1:]
+}
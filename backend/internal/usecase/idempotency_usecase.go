@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/repository"
+	"go-expense-management-system/internal/utils"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type IdempotencyUseCase struct {
+	DB          *gorm.DB
+	Log         *logrus.Logger
+	Repository  *repository.IdempotencyKeyRepository
+	TTL         time.Duration
+	LockTimeout time.Duration
+}
+
+func NewIdempotencyUseCase(
+	db *gorm.DB,
+	logger *logrus.Logger,
+	idempotencyRepository *repository.IdempotencyKeyRepository,
+	ttl time.Duration,
+	lockTimeout time.Duration,
+) *IdempotencyUseCase {
+	return &IdempotencyUseCase{
+		DB:          db,
+		Log:         logger,
+		Repository:  idempotencyRepository,
+		TTL:         ttl,
+		LockTimeout: lockTimeout,
+	}
+}
+
+func (c *IdempotencyUseCase) Begin(ctx context.Context, request *model.IdempotencyRequest) (*model.IdempotentResponse, error) {
+	if len(request.Key) > constants.IdempotencyKeyMaxLength {
+		return nil, utils.Error(messages.ErrIdempotencyKeyInvalid, http.StatusBadRequest, nil)
+	}
+
+	db := c.DB.WithContext(ctx)
+	now := time.Now()
+
+	if err := c.Repository.DeleteExpired(db, request.UserID, now); err != nil {
+		c.Log.Warnf("Failed to purge expired idempotency keys: %+v", err)
+	}
+
+	record, reserved, err := c.reserve(db, request, now)
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return &model.IdempotentResponse{ID: record.ID}, nil
+	}
+
+	if record.Method != request.Method || record.Path != request.Path || record.RequestHash != request.RequestHash {
+		return nil, utils.Error(messages.ErrIdempotencyKeyReused, http.StatusUnprocessableEntity, nil)
+	}
+
+	if record.Status == constants.IdempotencyStatusCompleted {
+		return toIdempotentReplay(record), nil
+	}
+
+	if record.UpdatedAt.After(now.Add(-c.LockTimeout)) {
+		return nil, utils.Error(messages.ErrIdempotencyInProgress, http.StatusConflict, nil)
+	}
+
+	c.Log.Warnf("Taking over abandoned idempotency key %s for user %s", record.Key, record.UserID)
+	if err := c.Repository.Release(db, record.ID); err != nil {
+		c.Log.Warnf("Failed to release idempotency key: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	record, reserved, err = c.reserve(db, request, now)
+	if err != nil {
+		return nil, err
+	}
+	if !reserved {
+		return nil, utils.Error(messages.ErrIdempotencyInProgress, http.StatusConflict, nil)
+	}
+	return &model.IdempotentResponse{ID: record.ID}, nil
+}
+
+func (c *IdempotencyUseCase) Complete(ctx context.Context, id uuid.UUID, response *model.IdempotentResponse) error {
+	headers, err := json.Marshal(response.Headers)
+	if err != nil {
+		return utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	if err := c.Repository.Complete(c.DB.WithContext(ctx), id, response.StatusCode, string(headers), response.Body); err != nil {
+		c.Log.Warnf("Failed to store idempotent response: %+v", err)
+		return utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+	return nil
+}
+
+func (c *IdempotencyUseCase) Release(ctx context.Context, id uuid.UUID) error {
+	if err := c.Repository.Release(c.DB.WithContext(ctx), id); err != nil {
+		c.Log.Warnf("Failed to release idempotency key: %+v", err)
+		return utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+	return nil
+}
+
+func (c *IdempotencyUseCase) reserve(db *gorm.DB, request *model.IdempotencyRequest, now time.Time) (*entity.IdempotencyKey, bool, error) {
+	record := &entity.IdempotencyKey{
+		UserID:      request.UserID,
+		Key:         request.Key,
+		Method:      request.Method,
+		Path:        request.Path,
+		RequestHash: request.RequestHash,
+		Status:      constants.IdempotencyStatusProcessing,
+		ExpiresAt:   now.Add(c.TTL),
+	}
+
+	reserved, err := c.Repository.Reserve(db, record)
+	if err != nil {
+		c.Log.Warnf("Failed to reserve idempotency key: %+v", err)
+		return nil, false, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+	if reserved {
+		return record, true, nil
+	}
+
+	existing := new(entity.IdempotencyKey)
+	if err := c.Repository.FindByUserAndKey(db, existing, request.UserID, request.Key); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, utils.Error(messages.ErrIdempotencyInProgress, http.StatusConflict, err)
+		}
+		c.Log.Warnf("Failed to find idempotency key: %+v", err)
+		return nil, false, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+	return existing, false, nil
+}
+
+func toIdempotentReplay(record *entity.IdempotencyKey) *model.IdempotentResponse {
+	headers := map[string]string{}
+	if record.ResponseHeaders != "" {
+		_ = json.Unmarshal([]byte(record.ResponseHeaders), &headers)
+	}
+
+	return &model.IdempotentResponse{
+		ID:         record.ID,
+		Replayed:   true,
+		StatusCode: record.ResponseStatus,
+		Headers:    headers,
+		Body:       record.ResponseBody,
+	}
+}
@@ -3,12 +3,29 @@ package usecase
 import (
 	"context"
 	"go-expense-management-system/internal/model"
+
+	"gorm.io/gorm"
 )
 
 type PaymentProcessor interface {
 	Process(ctx context.Context, request model.PaymentRequest) (*model.PaymentResponse, error)
 }
 
+type PaymentStatusChecker interface {
+	Get(ctx context.Context, externalID string) (*model.PaymentResponse, error)
+}
+
+type PaymentBulkProcessor interface {
+	ProcessBulk(ctx context.Context, request model.BulkPaymentRequest) (*model.BulkPaymentResponse, error)
+}
+
+type PaymentProvider interface {
+	PaymentProcessor
+	PaymentStatusChecker
+	PaymentBulkProcessor
+}
+
 type PaymentQueue interface {
-	Enqueue(job model.PaymentJob) bool
+	Enqueue(db *gorm.DB, job model.PaymentJob) error
+	Notify()
 }
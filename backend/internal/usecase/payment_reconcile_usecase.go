@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/repository"
+	"go-expense-management-system/internal/utils"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type PaymentReconcileUseCase struct {
+	DB                *gorm.DB
+	Log               *logrus.Logger
+	ExpenseRepository *repository.ExpenseRepository
+	PaymentRepository *repository.PaymentRepository
+	StatusChecker     PaymentStatusChecker
+	StateMachine      *ExpenseStateMachine
+	StaleAfter        time.Duration
+	BatchSize         int
+}
+
+func NewPaymentReconcileUseCase(
+	db *gorm.DB,
+	logger *logrus.Logger,
+	expenseRepository *repository.ExpenseRepository,
+	historyRepository *repository.ExpenseStatusHistoryRepository,
+	paymentRepository *repository.PaymentRepository,
+	statusChecker PaymentStatusChecker,
+	staleAfter time.Duration,
+	batchSize int,
+) *PaymentReconcileUseCase {
+	if staleAfter <= 0 {
+		staleAfter = 15 * time.Minute
+	}
+	if batchSize <= 0 {
+		batchSize = 50
+	}
+
+	return &PaymentReconcileUseCase{
+		DB:                db,
+		Log:               logger,
+		ExpenseRepository: expenseRepository,
+		PaymentRepository: paymentRepository,
+		StatusChecker:     statusChecker,
+		StateMachine:      NewExpenseStateMachine(logger, expenseRepository, historyRepository),
+		StaleAfter:        staleAfter,
+		BatchSize:         batchSize,
+	}
+}
+
+func (c *PaymentReconcileUseCase) Reconcile(ctx context.Context) (model.PaymentReconcileResult, error) {
+	var result model.PaymentReconcileResult
+	if c.StatusChecker == nil {
+		return result, utils.Error(messages.ErrPaymentFailed, http.StatusBadGateway, nil)
+	}
+
+	payments, err := c.PaymentRepository.ListUnconfirmed(c.DB.WithContext(ctx), time.Now().Add(-c.StaleAfter), c.BatchSize)
+	if err != nil {
+		c.Log.Warnf("Failed to list unconfirmed payments: %+v", err)
+		return result, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	for i := range payments {
+		result.Checked++
+		outcome, err := c.reconcileOne(ctx, &payments[i])
+		if err != nil {
+			c.Log.Warnf("Failed to reconcile payment for expense %s: %+v", payments[i].ExpenseID, err)
+			result.Errors++
+			continue
+		}
+
+		switch outcome {
+		case constants.PaymentProviderStatusCompleted:
+			result.Completed++
+		case constants.PaymentProviderStatusFailed:
+			result.Failed++
+		default:
+			result.Pending++
+		}
+	}
+
+	return result, nil
+}
+
+func (c *PaymentReconcileUseCase) reconcileOne(ctx context.Context, payment *entity.Payment) (string, error) {
+	remote, err := c.StatusChecker.Get(ctx, payment.ExternalID)
+	if err != nil {
+		return "", err
+	}
+
+	// an unknown payment is inconclusive: the request may not have reached the provider yet
+	providerPaymentID, providerStatus, outcome := "", "not_found", constants.PaymentProviderStatusPending
+	if remote != nil {
+		providerPaymentID, providerStatus = remote.ID, remote.Status
+		outcome = PaymentOutcome(remote.Status)
+	} else {
+		c.Log.Warnf("Payment provider does not know payment %s for expense %s, leaving it in processing", payment.ExternalID, payment.ExpenseID)
+	}
+	if outcome == "" {
+		return "", fmt.Errorf("unknown payment provider status %q", providerStatus)
+	}
+
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	lastError := ""
+	if outcome == constants.PaymentProviderStatusFailed {
+		lastError = "payment provider reported status " + providerStatus
+	}
+	if err := c.PaymentRepository.UpdateProviderStatus(tx, payment.ID, providerPaymentID, providerStatus, lastError); err != nil {
+		return "", err
+	}
+
+	expense := new(entity.Expense)
+	if err := c.ExpenseRepository.FindById(tx, expense, payment.ExpenseID); err != nil {
+		return "", err
+	}
+
+	notes := fmt.Sprintf("Reconciled with payment provider: status %s", providerStatus)
+	if _, err := settleExpensePayment(tx, c.StateMachine, expense, outcome, notes); err != nil {
+		var httpErr utils.HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Status() != http.StatusConflict {
+			return "", err
+		}
+		c.Log.Infof("Expense %s changed while reconciling its payment, skipping", expense.ID)
+		return constants.PaymentProviderStatusPending, nil
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return "", err
+	}
+
+	if outcome != constants.PaymentProviderStatusPending {
+		c.Log.Infof("Reconciled payment for expense %s: provider status %s", expense.ID, providerStatus)
+	}
+	return outcome, nil
+}
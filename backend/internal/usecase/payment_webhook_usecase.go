@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/repository"
+	"go-expense-management-system/internal/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type PaymentWebhookUseCase struct {
+	DB                *gorm.DB
+	Log               *logrus.Logger
+	ExpenseRepository *repository.ExpenseRepository
+	PaymentRepository *repository.PaymentRepository
+	EventRepository   *repository.PaymentWebhookEventRepository
+	StateMachine      *ExpenseStateMachine
+	Secret            string
+	Tolerance         time.Duration
+}
+
+func NewPaymentWebhookUseCase(
+	db *gorm.DB,
+	logger *logrus.Logger,
+	expenseRepository *repository.ExpenseRepository,
+	historyRepository *repository.ExpenseStatusHistoryRepository,
+	paymentRepository *repository.PaymentRepository,
+	eventRepository *repository.PaymentWebhookEventRepository,
+	secret string,
+	tolerance time.Duration,
+) *PaymentWebhookUseCase {
+	if tolerance <= 0 {
+		tolerance = 5 * time.Minute
+	}
+
+	return &PaymentWebhookUseCase{
+		DB:                db,
+		Log:               logger,
+		ExpenseRepository: expenseRepository,
+		PaymentRepository: paymentRepository,
+		EventRepository:   eventRepository,
+		StateMachine:      NewExpenseStateMachine(logger, expenseRepository, historyRepository),
+		Secret:            secret,
+		Tolerance:         tolerance,
+	}
+}
+
+func (c *PaymentWebhookUseCase) Handle(ctx context.Context, request *model.PaymentWebhookRequest) (*model.PaymentWebhookResponse, error) {
+	if c.Secret == "" {
+		return nil, utils.Error(messages.ErrWebhookNotConfigured, http.StatusServiceUnavailable, nil)
+	}
+
+	signedAt, err := c.verify(request, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	event := new(model.PaymentWebhookEvent)
+	if err := json.Unmarshal(request.Payload, event); err != nil {
+		return nil, utils.Error(messages.ErrInvalidWebhookPayload, http.StatusBadRequest, err)
+	}
+	event.ID = strings.TrimSpace(event.ID)
+	if event.ID == "" || len(event.ID) > 100 || (event.Data.ID == "" && event.Data.ExternalID == "") {
+		return nil, utils.Error(messages.ErrInvalidWebhookPayload, http.StatusBadRequest, nil)
+	}
+
+	outcome := PaymentOutcome(event.Data.Status)
+	if outcome == "" || outcome == constants.PaymentProviderStatusPending {
+		return nil, utils.Error(messages.ErrInvalidWebhookPayload, http.StatusBadRequest, nil)
+	}
+
+	db := c.DB.WithContext(ctx)
+	if err := c.EventRepository.DeleteReceivedBefore(db, time.Now().Add(-2*c.Tolerance)); err != nil {
+		c.Log.Warnf("Failed to purge old payment webhook events: %+v", err)
+	}
+
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	payment, err := c.PaymentRepository.FindByReference(tx, event.Data.ID, event.Data.ExternalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.Error(messages.ErrPaymentNotFound, http.StatusNotFound, err)
+		}
+		c.Log.Warnf("Failed to load payment for webhook %s: %+v", event.ID, err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	recorded, err := c.EventRepository.Record(tx, &entity.PaymentWebhookEvent{
+		EventID:    event.ID,
+		ExpenseID:  &payment.ExpenseID,
+		Status:     outcome,
+		SignedAt:   signedAt,
+		ReceivedAt: time.Now(),
+	})
+	if err != nil {
+		c.Log.Warnf("Failed to record payment webhook %s: %+v", event.ID, err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+	if !recorded {
+		// already applied: acknowledge so the provider stops retrying, but change nothing
+		expense := new(entity.Expense)
+		if err := c.ExpenseRepository.FindById(tx, expense, payment.ExpenseID); err != nil {
+			return nil, utils.Error(messages.ErrExpenseNotFound, http.StatusNotFound, err)
+		}
+		c.Log.Infof("Ignoring replayed payment webhook %s for expense %s", event.ID, expense.ID)
+		return &model.PaymentWebhookResponse{
+			EventID:   event.ID,
+			ExpenseID: expense.ID,
+			Status:    expense.Status,
+			Replayed:  true,
+		}, nil
+	}
+
+	failureReason := strings.TrimSpace(event.Data.FailureReason)
+	if outcome == constants.PaymentProviderStatusFailed && failureReason == "" {
+		failureReason = "payment provider reported status " + strings.TrimSpace(event.Data.Status)
+	}
+	if err := c.PaymentRepository.UpdateProviderStatus(tx, payment.ID, event.Data.ID, event.Data.Status, failureReason); err != nil {
+		c.Log.Warnf("Failed to update payment %s: %+v", payment.ID, err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	expense := new(entity.Expense)
+	if err := c.ExpenseRepository.FindById(tx, expense, payment.ExpenseID); err != nil {
+		return nil, utils.Error(messages.ErrExpenseNotFound, http.StatusNotFound, err)
+	}
+
+	notes := ""
+	if outcome == constants.PaymentProviderStatusFailed {
+		notes = failureReason
+	}
+	settled, err := settleExpensePayment(tx, c.StateMachine, expense, outcome, notes)
+	if err != nil {
+		return nil, err
+	}
+	if !settled {
+		c.Log.Infof("Ignoring payment webhook %s for expense %s in status %s", event.ID, expense.ID, expense.Status)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed to commit transaction: %+v", err)
+		return nil, utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
+	}
+
+	return &model.PaymentWebhookResponse{
+		EventID:   event.ID,
+		ExpenseID: expense.ID,
+		Status:    expense.Status,
+	}, nil
+}
+
+func (c *PaymentWebhookUseCase) verify(request *model.PaymentWebhookRequest, now time.Time) (time.Time, error) {
+	seconds, err := strconv.ParseInt(strings.TrimSpace(request.Timestamp), 10, 64)
+	if err != nil {
+		return time.Time{}, utils.Error(messages.ErrInvalidWebhookSignature, http.StatusUnauthorized, err)
+	}
+	if !utils.VerifyWebhookSignature(c.Secret, strings.TrimSpace(request.Timestamp), request.Payload, request.Signature) {
+		return time.Time{}, utils.Error(messages.ErrInvalidWebhookSignature, http.StatusUnauthorized, nil)
+	}
+
+	signedAt := time.Unix(seconds, 0)
+	if signedAt.Before(now.Add(-c.Tolerance)) || signedAt.After(now.Add(c.Tolerance)) {
+		return time.Time{}, utils.Error(messages.ErrWebhookTimestampExpired, http.StatusUnauthorized, nil)
+	}
+	return signedAt, nil
+}
+
+func settleExpensePayment(tx *gorm.DB, machine *ExpenseStateMachine, expense *entity.Expense, outcome, notes string) (bool, error) {
+	if expense.Status != constants.ExpenseStatusPaymentProcessing {
+		return false, nil
+	}
+
+	switch outcome {
+	case constants.PaymentProviderStatusCompleted:
+		now := time.Now()
+		expense.ProcessedAt = &now
+		if _, err := machine.Transition(tx, expense, ExpenseEventCompletePayment, nil, notes); err != nil {
+			expense.ProcessedAt = nil
+			return false, err
+		}
+	case constants.PaymentProviderStatusFailed:
+		if _, err := machine.Transition(tx, expense, ExpenseEventFailPayment, nil, notes); err != nil {
+			return false, err
+		}
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+func PaymentOutcome(status string) string {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case constants.PaymentProviderStatusPending, constants.PaymentProviderStatusProcessing, constants.PaymentProviderStatusAwaitingManualTransfer, "queued":
+		return constants.PaymentProviderStatusPending
+	case constants.PaymentProviderStatusCompleted, "success", "succeeded", "paid", "settled":
+		return constants.PaymentProviderStatusCompleted
+	case constants.PaymentProviderStatusFailed, "rejected", "cancelled", "canceled", "expired":
+		return constants.PaymentProviderStatusFailed
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,626 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/model/converter"
+	"go-expense-management-system/internal/repository"
+	"go-expense-management-system/internal/utils"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type PayoutUseCase struct {
+	DB                    *gorm.DB
+	Log                   *logrus.Logger
+	ExpenseRepository     *repository.ExpenseRepository
+	PaymentRepository     *repository.PaymentRepository
+	BankAccountRepository *repository.UserBankAccountRepository
+	BatchRepository       *repository.PayoutBatchRepository
+	Provider              PaymentBulkProcessor
+	StateMachine          *ExpenseStateMachine
+	Enabled               bool
+	MaxItems              int
+	Timeout               time.Duration
+}
+
+func NewPayoutUseCase(
+	db *gorm.DB,
+	logger *logrus.Logger,
+	expenseRepository *repository.ExpenseRepository,
+	historyRepository *repository.ExpenseStatusHistoryRepository,
+	paymentRepository *repository.PaymentRepository,
+	bankAccountRepository *repository.UserBankAccountRepository,
+	batchRepository *repository.PayoutBatchRepository,
+	provider PaymentBulkProcessor,
+	enabled bool,
+	maxItems int,
+	timeout time.Duration,
+) *PayoutUseCase {
+	if maxItems <= 0 {
+		maxItems = 500
+	}
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+
+	return &PayoutUseCase{
+		DB:                    db,
+		Log:                   logger,
+		ExpenseRepository:     expenseRepository,
+		PaymentRepository:     paymentRepository,
+		BankAccountRepository: bankAccountRepository,
+		BatchRepository:       batchRepository,
+		Provider:              provider,
+		StateMachine:          NewExpenseStateMachine(logger, expenseRepository, historyRepository),
+		Enabled:               enabled,
+		MaxItems:              maxItems,
+		Timeout:               timeout,
+	}
+}
+
+func (c *PayoutUseCase) List(ctx context.Context, auth *model.Auth, request *model.ListPayoutBatchRequest) ([]model.PayoutBatchResponse, model.PageMetadata, error) {
+	if !isManager(auth) {
+		return nil, model.PageMetadata{}, utils.Error(messages.Forbidden, http.StatusForbidden, nil)
+	}
+
+	page := request.Page
+	if page < 1 {
+		page = 1
+	}
+	size := request.Size
+	if size < 1 {
+		size = 10
+	}
+
+	batches, total, err := c.BatchRepository.List(c.DB.WithContext(ctx), request.Status, (page-1)*size, size)
+	if err != nil {
+		c.Log.Warnf("Failed to list payout batches: %+v", err)
+		return nil, model.PageMetadata{}, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	responses := make([]model.PayoutBatchResponse, 0, len(batches))
+	for i := range batches {
+		responses = append(responses, *converter.PayoutBatchToResponse(&batches[i]))
+	}
+	return responses, utils.NewPageMetadata(page, size, total), nil
+}
+
+func (c *PayoutUseCase) Get(ctx context.Context, auth *model.Auth, batchID uuid.UUID) (*model.PayoutBatchResponse, error) {
+	if !isManager(auth) {
+		return nil, utils.Error(messages.Forbidden, http.StatusForbidden, nil)
+	}
+
+	batch, err := c.BatchRepository.FindWithItems(c.DB.WithContext(ctx), batchID, false)
+	if err != nil {
+		return nil, c.batchLookupError(err)
+	}
+	return converter.PayoutBatchToResponse(batch), nil
+}
+
+func (c *PayoutUseCase) Create(ctx context.Context, auth *model.Auth) (*model.PayoutBatchResponse, error) {
+	if !isManager(auth) {
+		return nil, utils.Error(messages.Forbidden, http.StatusForbidden, nil)
+	}
+	if !c.Enabled {
+		return nil, utils.Error(messages.ErrPayoutBatchDisabled, http.StatusConflict, nil)
+	}
+
+	createdBy := auth.UserID
+	batch, err := c.build(ctx, &createdBy)
+	if err != nil {
+		return nil, err
+	}
+	return converter.PayoutBatchToResponse(batch), nil
+}
+
+func (c *PayoutUseCase) CreateScheduled(ctx context.Context) error {
+	batch, err := c.build(ctx, nil)
+	if err != nil {
+		var httpErr utils.HTTPError
+		if errors.As(err, &httpErr) && httpErr.Status() == http.StatusUnprocessableEntity {
+			c.Log.Info("Scheduled payout run found no approved expenses")
+			return nil
+		}
+		return err
+	}
+
+	c.Log.Infof("Scheduled payout run drafted batch %s with %d expenses totalling %d IDR", batch.ID, batch.ItemCount, batch.TotalAmountIDR)
+	return nil
+}
+
+func (c *PayoutUseCase) Approve(ctx context.Context, auth *model.Auth, batchID uuid.UUID) (*model.PayoutBatchResponse, error) {
+	if !isManager(auth) {
+		return nil, utils.Error(messages.Forbidden, http.StatusForbidden, nil)
+	}
+	if !c.Enabled {
+		return nil, utils.Error(messages.ErrPayoutBatchDisabled, http.StatusConflict, nil)
+	}
+	if c.Provider == nil {
+		return nil, utils.Error(messages.ErrPaymentFailed, http.StatusBadGateway, nil)
+	}
+
+	batch, requests, err := c.startBatch(ctx, auth, batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(requests) > 0 {
+		if batch, err = c.submitBatch(ctx, batch.ID, requests); err != nil {
+			return nil, err
+		}
+	}
+
+	return converter.PayoutBatchToResponse(batch), nil
+}
+
+func (c *PayoutUseCase) submitBatch(ctx context.Context, batchID uuid.UUID, requests []model.PaymentRequest) (*entity.PayoutBatch, error) {
+	ctx = context.WithoutCancel(ctx)
+	submitCtx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	response, submitErr := c.Provider.ProcessBulk(submitCtx, model.BulkPaymentRequest{
+		ExternalID: batchID.String(),
+		Items:      requests,
+	})
+	if submitErr != nil {
+		c.Log.Warnf("Bulk payout failed for batch %s: %+v", batchID, submitErr)
+	}
+	return c.settleBatch(ctx, batchID, response, submitErr)
+}
+
+func (c *PayoutUseCase) Sync(ctx context.Context) (int, error) {
+	ids, err := c.BatchRepository.ListProcessingIDs(c.DB.WithContext(ctx))
+	if err != nil {
+		c.Log.Warnf("Failed to list processing payout batches: %+v", err)
+		return 0, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	finished := 0
+	for _, id := range ids {
+		done, err := c.resubmitBatch(ctx, id)
+		if err != nil {
+			c.Log.Warnf("Failed to resubmit payout batch %s: %+v", id, err)
+		}
+		if !done {
+			done, err = c.syncBatch(ctx, id)
+		}
+		if err != nil {
+			c.Log.Warnf("Failed to sync payout batch %s: %+v", id, err)
+			continue
+		}
+		if done {
+			finished++
+		}
+	}
+	return finished, nil
+}
+
+func (c *PayoutUseCase) build(ctx context.Context, createdBy *uuid.UUID) (*entity.PayoutBatch, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.BatchRepository.Lock(tx); err != nil {
+		c.Log.Warnf("Failed to lock payout batches: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	cancelled, err := c.BatchRepository.CancelDrafts(tx)
+	if err != nil {
+		c.Log.Warnf("Failed to cancel draft payout batches: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	candidates, err := c.BatchRepository.ListCandidates(tx, c.MaxItems)
+	if err != nil {
+		c.Log.Warnf("Failed to list payout candidates: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+	if len(candidates) == 0 {
+		return nil, utils.Error(messages.ErrPayoutNothingToPay, http.StatusUnprocessableEntity, nil)
+	}
+
+	batch := &entity.PayoutBatch{
+		Status:    constants.PayoutBatchStatusDraft,
+		CreatedBy: createdBy,
+		Items:     make([]entity.PayoutBatchItem, 0, len(candidates)),
+	}
+	for _, candidate := range candidates {
+		batch.ItemCount++
+		batch.TotalAmountIDR += candidate.AmountIDR
+		batch.Items = append(batch.Items, entity.PayoutBatchItem{
+			ExpenseID:         candidate.ExpenseID,
+			UserID:            candidate.UserID,
+			AmountIDR:         candidate.AmountIDR,
+			BankCode:          candidate.BankCode,
+			AccountNumber:     candidate.AccountNumber,
+			AccountHolderName: candidate.AccountHolderName,
+			Status:            constants.PayoutItemStatusPending,
+		})
+	}
+
+	if err := c.BatchRepository.Create(tx, batch); err != nil {
+		c.Log.Warnf("Failed to create payout batch: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed to commit transaction: %+v", err)
+		return nil, utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
+	}
+
+	if cancelled > 0 {
+		c.Log.Infof("Payout batch %s replaces %d earlier draft batch(es)", batch.ID, cancelled)
+	}
+	return batch, nil
+}
+
+func (c *PayoutUseCase) startBatch(ctx context.Context, auth *model.Auth, batchID uuid.UUID) (*entity.PayoutBatch, []model.PaymentRequest, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.BatchRepository.Lock(tx); err != nil {
+		c.Log.Warnf("Failed to lock payout batches: %+v", err)
+		return nil, nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	batch, err := c.BatchRepository.FindWithItems(tx, batchID, true)
+	if err != nil {
+		return nil, nil, c.batchLookupError(err)
+	}
+	if batch.Status != constants.PayoutBatchStatusDraft {
+		return nil, nil, utils.Error(messages.ErrPayoutBatchNotDraft, http.StatusConflict, nil)
+	}
+
+	now := time.Now()
+	requests := make([]model.PaymentRequest, 0, len(batch.Items))
+	for i := range batch.Items {
+		item := &batch.Items[i]
+		request, err := c.startItem(tx, item, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		if request != nil {
+			requests = append(requests, *request)
+		}
+		if err := c.BatchRepository.SaveItem(tx, item); err != nil {
+			c.Log.Warnf("Failed to update payout item %s: %+v", item.ID, err)
+			return nil, nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+		}
+	}
+
+	approvedBy := auth.UserID
+	batch.ApprovedBy = &approvedBy
+	batch.ApprovedAt = &now
+	batch.Status = constants.PayoutBatchStatusProcessing
+	refreshPayoutBatchStatus(batch, now)
+	if err := c.BatchRepository.SaveBatch(tx, batch); err != nil {
+		c.Log.Warnf("Failed to update payout batch %s: %+v", batch.ID, err)
+		return nil, nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed to commit transaction: %+v", err)
+		return nil, nil, utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
+	}
+
+	return batch, requests, nil
+}
+
+func (c *PayoutUseCase) startItem(tx *gorm.DB, item *entity.PayoutBatchItem, now time.Time) (*model.PaymentRequest, error) {
+	expense := new(entity.Expense)
+	if err := c.ExpenseRepository.FindById(tx, expense, item.ExpenseID); err != nil {
+		return nil, utils.Error(messages.ErrExpenseNotFound, http.StatusNotFound, err)
+	}
+
+	if expense.ProcessedAt != nil || !slices.Contains(repository.PayoutEligibleStatuses, expense.Status) {
+		item.Status = constants.PayoutItemStatusSkipped
+		item.FailureReason = "Expense is no longer waiting for payout (status " + expense.Status + ")"
+		return nil, nil
+	}
+
+	account, err := c.BankAccountRepository.FindVerifiedByUserID(tx, expense.UserID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			c.Log.Warnf("Failed to find bank account for user %s: %+v", expense.UserID, err)
+			return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+		}
+		// like the payment queue, leave the expense payable for a later batch
+		item.Status = constants.PayoutItemStatusSkipped
+		item.FailureReason = messages.ErrBankAccountNotVerified
+		if err := c.StateMachine.Note(tx, expense, payoutHeldNote); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	if _, err := c.StateMachine.Transition(tx, expense, ExpenseEventStartPayment, nil, "Included in payout batch "+item.BatchID.String()); err != nil {
+		return nil, err
+	}
+
+	externalID := expense.ID.String()
+	if err := c.PaymentRepository.RecordRequest(tx, expense.ID, externalID, expense.AmountIDR, now); err != nil {
+		c.Log.Warnf("Failed to record payment request for expense %s: %+v", expense.ID, err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	item.AmountIDR = expense.AmountIDR
+	item.BankCode = account.BankCode
+	item.AccountNumber = account.AccountNumber
+	item.AccountHolderName = account.AccountHolderName
+
+	request := payoutItemRequest(item)
+	return &request, nil
+}
+
+func payoutItemRequest(item *entity.PayoutBatchItem) model.PaymentRequest {
+	externalID := item.ExpenseID.String()
+	return model.PaymentRequest{
+		Amount:            item.AmountIDR,
+		ExternalID:        externalID,
+		BankCode:          item.BankCode,
+		AccountNumber:     item.AccountNumber,
+		AccountHolderName: item.AccountHolderName,
+		Description:       "Expense reimbursement " + externalID,
+	}
+}
+
+// resubmitBatch sends the bulk transfer again for a processing batch the provider never
+// acknowledged, e.g. because the bulk call timed out or the process stopped before making it.
+func (c *PayoutUseCase) resubmitBatch(ctx context.Context, batchID uuid.UUID) (bool, error) {
+	if c.Provider == nil {
+		return false, nil
+	}
+
+	batch, err := c.BatchRepository.FindWithItems(c.DB.WithContext(ctx), batchID, false)
+	if err != nil {
+		return false, err
+	}
+	// leave batches alone while their approval may still be waiting on the bulk call
+	if batch.SubmittedAt != nil || batch.ApprovedAt == nil || time.Since(*batch.ApprovedAt) < c.Timeout {
+		return false, nil
+	}
+
+	requests := make([]model.PaymentRequest, 0, len(batch.Items))
+	for i := range batch.Items {
+		if batch.Items[i].Status == constants.PayoutItemStatusPending {
+			requests = append(requests, payoutItemRequest(&batch.Items[i]))
+		}
+	}
+	if len(requests) == 0 {
+		return false, nil
+	}
+
+	c.Log.Infof("Resubmitting payout batch %s with %d unacknowledged items", batch.ID, len(requests))
+	if batch, err = c.submitBatch(ctx, batch.ID, requests); err != nil {
+		return false, err
+	}
+	return batch.Status != constants.PayoutBatchStatusProcessing, nil
+}
+
+func (c *PayoutUseCase) settleBatch(ctx context.Context, batchID uuid.UUID, response *model.BulkPaymentResponse, submitErr error) (*entity.PayoutBatch, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	batch, err := c.BatchRepository.FindWithItems(tx, batchID, true)
+	if err != nil {
+		return nil, c.batchLookupError(err)
+	}
+
+	results := make(map[string]model.PaymentResponse)
+	if response != nil {
+		batch.ProviderBatchID = response.ID
+		for _, result := range response.Items {
+			results[result.ExternalID] = result
+		}
+	}
+	now := time.Now()
+	status := paymentErrorStatus(submitErr)
+	// a conflict means the provider already holds a bulk transfer with this batch ID
+	if submitErr == nil || status == http.StatusConflict {
+		batch.SubmittedAt = &now
+	}
+	if submitErr != nil {
+		batch.LastError = submitErr.Error()
+	}
+
+	for i := range batch.Items {
+		item := &batch.Items[i]
+		if item.Status != constants.PayoutItemStatusPending {
+			continue
+		}
+
+		outcome, reason := constants.PaymentProviderStatusFailed, "Bulk payout failed: "+batch.LastError
+		if submitErr != nil {
+			if err := c.PaymentRepository.RecordError(tx, item.ExpenseID, submitErr.Error(), now); err != nil {
+				return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+			}
+			// only a definitive rejection fails the items; otherwise the transfer may still go
+			// through, so they stay processing for webhooks, reconciliation or a resubmit
+			if !isPaymentRejection(status) {
+				continue
+			}
+		} else {
+			result, ok := results[item.ExpenseID.String()]
+			if !ok {
+				continue
+			}
+			if err := c.PaymentRepository.RecordResponse(tx, item.ExpenseID, result.ID, result.Status, now); err != nil {
+				return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+			}
+			item.ProviderPaymentID = result.ID
+			item.ProviderStatus = result.Status
+			outcome = PaymentOutcome(result.Status)
+			reason = result.FailureReason
+			if reason == "" {
+				reason = "payment provider reported status " + result.Status
+			}
+		}
+
+		if err := c.settleItem(tx, item, outcome, reason); err != nil {
+			return nil, err
+		}
+		if err := c.BatchRepository.SaveItem(tx, item); err != nil {
+			return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+		}
+	}
+
+	refreshPayoutBatchStatus(batch, now)
+	if err := c.BatchRepository.SaveBatch(tx, batch); err != nil {
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed to commit transaction: %+v", err)
+		return nil, utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
+	}
+
+	return batch, nil
+}
+
+func (c *PayoutUseCase) settleItem(tx *gorm.DB, item *entity.PayoutBatchItem, outcome, reason string) error {
+	if outcome != constants.PaymentProviderStatusCompleted && outcome != constants.PaymentProviderStatusFailed {
+		return nil
+	}
+
+	expense := new(entity.Expense)
+	if err := c.ExpenseRepository.FindById(tx, expense, item.ExpenseID); err != nil {
+		return utils.Error(messages.ErrExpenseNotFound, http.StatusNotFound, err)
+	}
+
+	notes := ""
+	if outcome == constants.PaymentProviderStatusFailed {
+		notes = reason
+	}
+	if _, err := settleExpensePayment(tx, c.StateMachine, expense, outcome, notes); err != nil {
+		var httpErr utils.HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Status() != http.StatusConflict {
+			return err
+		}
+		c.Log.Infof("Expense %s changed while settling payout item %s, leaving it to sync", expense.ID, item.ID)
+		return nil
+	}
+
+	item.Status = outcome
+	if outcome == constants.PaymentProviderStatusFailed {
+		item.FailureReason = reason
+	}
+	return nil
+}
+
+func (c *PayoutUseCase) syncBatch(ctx context.Context, batchID uuid.UUID) (bool, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	batch, err := c.BatchRepository.FindWithItems(tx, batchID, true)
+	if err != nil {
+		return false, err
+	}
+	if batch.Status != constants.PayoutBatchStatusProcessing {
+		return false, nil
+	}
+
+	for i := range batch.Items {
+		item := &batch.Items[i]
+		if item.Status != constants.PayoutItemStatusPending {
+			continue
+		}
+
+		expense := new(entity.Expense)
+		if err := c.ExpenseRepository.FindById(tx, expense, item.ExpenseID); err != nil {
+			return false, err
+		}
+
+		switch expense.Status {
+		case constants.ExpenseStatusCompleted:
+			item.Status = constants.PayoutItemStatusCompleted
+		case constants.ExpenseStatusPaymentProcessing:
+			continue
+		default:
+			item.Status = constants.PayoutItemStatusFailed
+			item.FailureReason = fmt.Sprintf("Payment did not complete, expense is %s", expense.Status)
+		}
+		if err := c.BatchRepository.SaveItem(tx, item); err != nil {
+			return false, err
+		}
+	}
+
+	refreshPayoutBatchStatus(batch, time.Now())
+	if err := c.BatchRepository.SaveBatch(tx, batch); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return false, err
+	}
+	return batch.Status != constants.PayoutBatchStatusProcessing, nil
+}
+
+func paymentErrorStatus(err error) int {
+	var statusErr interface{ HTTPStatus() int }
+	if errors.As(err, &statusErr) {
+		return statusErr.HTTPStatus()
+	}
+	return 0
+}
+
+func isPaymentRejection(status int) bool {
+	switch status {
+	case http.StatusRequestTimeout, http.StatusConflict, http.StatusTooManyRequests:
+		return false
+	default:
+		return status >= http.StatusBadRequest && status < http.StatusInternalServerError
+	}
+}
+
+func (c *PayoutUseCase) batchLookupError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return utils.Error(messages.ErrPayoutBatchNotFound, http.StatusNotFound, err)
+	}
+	c.Log.Warnf("Failed to find payout batch: %+v", err)
+	return utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+}
+
+func refreshPayoutBatchStatus(batch *entity.PayoutBatch, now time.Time) {
+	if batch.Status != constants.PayoutBatchStatusProcessing {
+		return
+	}
+
+	batch.Status = PayoutBatchStatus(batch.Items)
+	if batch.Status != constants.PayoutBatchStatusProcessing {
+		batch.CompletedAt = &now
+	}
+}
+
+func PayoutBatchStatus(items []entity.PayoutBatchItem) string {
+	completed, failed := 0, 0
+	for _, item := range items {
+		switch item.Status {
+		case constants.PayoutItemStatusPending:
+			return constants.PayoutBatchStatusProcessing
+		case constants.PayoutItemStatusCompleted:
+			completed++
+		case constants.PayoutItemStatusFailed:
+			failed++
+		}
+	}
+
+	switch {
+	case failed == 0:
+		return constants.PayoutBatchStatusCompleted
+	case completed == 0:
+		return constants.PayoutBatchStatusFailed
+	default:
+		return constants.PayoutBatchStatusPartiallyFailed
+	}
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/repository"
+	"go-expense-management-system/internal/utils"
+	"math"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ReportUseCase struct {
+	DB               *gorm.DB
+	Log              *logrus.Logger
+	ReportRepository *repository.ReportRepository
+}
+
+func NewReportUseCase(db *gorm.DB, logger *logrus.Logger, reportRepository *repository.ReportRepository) *ReportUseCase {
+	return &ReportUseCase{
+		DB:               db,
+		Log:              logger,
+		ReportRepository: reportRepository,
+	}
+}
+
+func (c *ReportUseCase) Summary(ctx context.Context, auth *model.Auth, request *model.ReportSummaryRequest) (*model.ReportSummaryResponse, error) {
+	if !isManager(auth) {
+		return nil, utils.Error(messages.Forbidden, http.StatusForbidden, nil)
+	}
+	if request.From != nil && request.To != nil && !request.From.Before(*request.To) {
+		return nil, utils.Error(messages.ErrInvalidReportRange, http.StatusBadRequest, nil)
+	}
+
+	tx := c.DB.WithContext(ctx).Begin(&sql.TxOptions{ReadOnly: true, Isolation: sql.LevelRepeatableRead})
+	defer tx.Rollback()
+
+	filter := repository.ReportFilter{From: request.From, To: request.To}
+	response := &model.ReportSummaryResponse{From: request.From, To: request.To}
+
+	totals, err := c.ReportRepository.Totals(tx, filter)
+	if err != nil {
+		c.Log.Warnf("Failed to summarize expenses: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+	response.TotalCount = totals.Count
+	response.TotalAmountIDR = totals.TotalIDR
+	response.TotalAmountIDRFormatted = utils.FormatIDR(totals.TotalIDR)
+
+	groupings := []struct {
+		target *[]model.ReportGroupResponse
+		load   func(*gorm.DB, repository.ReportFilter) ([]repository.ReportGroup, error)
+	}{
+		{target: &response.ByStatus, load: c.ReportRepository.ByStatus},
+		{target: &response.ByMonth, load: c.ReportRepository.ByMonth},
+		{target: &response.ByRequester, load: c.ReportRepository.ByRequester},
+		{target: &response.ByCategory, load: c.ReportRepository.ByCategory},
+	}
+	for _, grouping := range groupings {
+		groups, err := grouping.load(tx, filter)
+		if err != nil {
+			c.Log.Warnf("Failed to group expenses: %+v", err)
+			return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+		}
+		*grouping.target = reportGroupsToResponse(groups)
+	}
+
+	approval, err := c.ReportRepository.ApprovalTurnaround(tx, filter)
+	if err != nil {
+		c.Log.Warnf("Failed to compute approval turnaround: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+	response.ApprovalTurnaround = reportDurationToResponse(approval)
+
+	payment, err := c.ReportRepository.PaymentCompletion(tx, filter)
+	if err != nil {
+		c.Log.Warnf("Failed to compute payment completion time: %+v", err)
+		return nil, utils.Error(messages.InternalServerError, http.StatusInternalServerError, err)
+	}
+	response.PaymentCompletion = reportDurationToResponse(payment)
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed to commit transaction: %+v", err)
+		return nil, utils.Error(messages.ErrCommitTransaction, http.StatusInternalServerError, err)
+	}
+
+	return response, nil
+}
+
+func reportGroupsToResponse(groups []repository.ReportGroup) []model.ReportGroupResponse {
+	responses := make([]model.ReportGroupResponse, 0, len(groups))
+	for _, group := range groups {
+		label := group.Label
+		if label == group.Key {
+			label = ""
+		}
+		responses = append(responses, model.ReportGroupResponse{
+			Key:                     group.Key,
+			Label:                   label,
+			Count:                   group.Count,
+			TotalAmountIDR:          group.TotalIDR,
+			TotalAmountIDRFormatted: utils.FormatIDR(group.TotalIDR),
+		})
+	}
+	return responses
+}
+
+func reportDurationToResponse(duration repository.ReportDuration) model.ReportDurationResponse {
+	return model.ReportDurationResponse{
+		Count:          duration.Count,
+		AverageSeconds: math.Round(duration.AverageSeconds),
+		AverageHours:   math.Round(duration.AverageSeconds/36) / 100,
+	}
+}
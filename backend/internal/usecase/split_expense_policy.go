@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/utils"
+	"time"
+)
+
+type SplitExpensePolicy struct {
+	Window           time.Duration
+	SameCategory     bool
+	MatchDescription bool
+	MinSimilarity    float64
+}
+
+func (p *SplitExpensePolicy) Enabled() bool {
+	return p != nil && p.Window > 0
+}
+
+func (p *SplitExpensePolicy) Related(description string, candidates []entity.Expense) []entity.Expense {
+	if !p.MatchDescription {
+		return candidates
+	}
+
+	related := make([]entity.Expense, 0, len(candidates))
+	for _, candidate := range candidates {
+		if utils.TextSimilarity(description, candidate.Description) >= p.MinSimilarity {
+			related = append(related, candidate)
+		}
+	}
+	return related
+}
@@ -0,0 +1,12 @@
+package usecase
+
+import (
+	"context"
+	"io"
+)
+
+type ReceiptStorage interface {
+	Put(ctx context.Context, key string, content io.Reader) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
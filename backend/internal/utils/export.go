@@ -0,0 +1,209 @@
+package utils
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"go-expense-management-system/internal/constants"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var ErrUnsupportedExportFormat = errors.New("unsupported export format")
+
+type ExportWriter interface {
+	WriteHeader(columns []string) error
+	WriteRow(values []any) error
+	Close() error
+}
+
+func NewExportWriter(format string, w io.Writer) (ExportWriter, error) {
+	switch strings.ToLower(format) {
+	case constants.ExportFormatCSV:
+		return NewCSVExportWriter(w), nil
+	case constants.ExportFormatXLSX:
+		return NewXLSXExportWriter(w), nil
+	default:
+		return nil, ErrUnsupportedExportFormat
+	}
+}
+
+func ExportContentType(format string) string {
+	switch strings.ToLower(format) {
+	case constants.ExportFormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+type csvExportWriter struct {
+	writer *csv.Writer
+	rows   int
+}
+
+func NewCSVExportWriter(w io.Writer) ExportWriter {
+	return &csvExportWriter{writer: csv.NewWriter(w)}
+}
+
+func (w *csvExportWriter) WriteHeader(columns []string) error {
+	return w.writer.Write(columns)
+}
+
+func (w *csvExportWriter) WriteRow(values []any) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = exportCellText(value)
+		if _, ok := value.(string); ok {
+			record[i] = escapeCSVFormula(record[i])
+		}
+	}
+	if err := w.writer.Write(record); err != nil {
+		return err
+	}
+
+	w.rows++
+	if w.rows%100 == 0 {
+		w.writer.Flush()
+	}
+	return w.writer.Error()
+}
+
+func (w *csvExportWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+type xlsxExportWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+	err     error
+}
+
+func NewXLSXExportWriter(w io.Writer) ExportWriter {
+	return &xlsxExportWriter{archive: zip.NewWriter(w)}
+}
+
+func (w *xlsxExportWriter) WriteHeader(columns []string) error {
+	values := make([]any, len(columns))
+	for i, column := range columns {
+		values[i] = column
+	}
+	return w.WriteRow(values)
+}
+
+func (w *xlsxExportWriter) WriteRow(values []any) error {
+	if err := w.open(); err != nil {
+		return err
+	}
+
+	w.sheet.WriteString("<row>")
+	for _, value := range values {
+		switch v := value.(type) {
+		case int:
+			fmt.Fprintf(w.sheet, `<c t="n"><v>%d</v></c>`, v)
+		case int64:
+			fmt.Fprintf(w.sheet, `<c t="n"><v>%d</v></c>`, v)
+		case float64:
+			fmt.Fprintf(w.sheet, `<c t="n"><v>%s</v></c>`, strconv.FormatFloat(v, 'f', -1, 64))
+		default:
+			text := exportCellText(value)
+			if text == "" {
+				w.sheet.WriteString("<c/>")
+				continue
+			}
+			w.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+			xml.EscapeText(w.sheet, []byte(text))
+			w.sheet.WriteString("</t></is></c>")
+		}
+	}
+	_, err := w.sheet.WriteString("</row>")
+	return err
+}
+
+func (w *xlsxExportWriter) Close() error {
+	if err := w.open(); err != nil {
+		return err
+	}
+	if _, err := w.sheet.WriteString(xlsxSheetEnd); err != nil {
+		return err
+	}
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.archive.Close()
+}
+
+func (w *xlsxExportWriter) open() error {
+	if w.sheet != nil || w.err != nil {
+		return w.err
+	}
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{name: "[Content_Types].xml", content: xlsxContentTypes},
+		{name: "_rels/.rels", content: xlsxRootRels},
+		{name: "xl/workbook.xml", content: xlsxWorkbook},
+		{name: "xl/_rels/workbook.xml.rels", content: xlsxWorkbookRels},
+	}
+	for _, part := range parts {
+		file, err := w.archive.Create(part.name)
+		if err != nil {
+			w.err = err
+			return err
+		}
+		if _, err := io.WriteString(file, part.content); err != nil {
+			w.err = err
+			return err
+		}
+	}
+
+	sheet, err := w.archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		w.err = err
+		return err
+	}
+	w.sheet = bufio.NewWriter(sheet)
+	_, w.err = w.sheet.WriteString(xlsxSheetStart)
+	return w.err
+}
+
+func exportCellText(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func escapeCSVFormula(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
@@ -18,9 +18,46 @@ func NewPageMetadata(page, size int, total int64) model.PageMetadata {
 	return model.PageMetadata{
 		CurrentPage: page,
 		PageSize:    size,
-		TotalItem:   total,
-		TotalPage:   totalPage,
+		TotalItem:   &total,
+		TotalPage:   &totalPage,
 		HasNext:     int64(page) < totalPage,
 		HasPrevious: page > 1,
 	}
 }
+
+func NewPageMetadataWithoutTotal(page, size int, hasNext bool) model.PageMetadata {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = 10
+	}
+
+	return model.PageMetadata{
+		CurrentPage: page,
+		PageSize:    size,
+		HasNext:     hasNext,
+		HasPrevious: page > 1,
+	}
+}
+
+func NewCursorPageMetadata(size int, cursor *Cursor, hasMore bool, first, last *Cursor, total *int64) model.PageMetadata {
+	meta := model.PageMetadata{
+		PageSize:  size,
+		TotalItem: total,
+	}
+	if first == nil || last == nil {
+		return meta
+	}
+
+	backward := cursor != nil && cursor.Backward
+	if hasMore || backward {
+		meta.HasNext = true
+		meta.NextCursor = EncodeCursor(Cursor{Time: last.Time, ID: last.ID})
+	}
+	if (cursor != nil && !backward) || (backward && hasMore) {
+		meta.HasPrevious = true
+		meta.PrevCursor = EncodeCursor(Cursor{Time: first.Time, ID: first.ID, Backward: true})
+	}
+	return meta
+}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+func SignWebhookPayload(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func VerifyWebhookSignature(secret, timestamp string, payload []byte, signature string) bool {
+	if secret == "" || timestamp == "" || signature == "" {
+		return false
+	}
+
+	provided, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signature), "sha256="))
+	if err != nil {
+		return false
+	}
+	expected, _ := hex.DecodeString(SignWebhookPayload(secret, timestamp, payload))
+	return hmac.Equal(provided, expected)
+}
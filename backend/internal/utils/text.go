@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+func NormalizeText(value string) string {
+	var builder strings.Builder
+	space := false
+	for _, r := range strings.ToLower(value) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			builder.WriteRune(r)
+			space = false
+			continue
+		}
+		if !space && builder.Len() > 0 {
+			builder.WriteRune(' ')
+			space = true
+		}
+	}
+	return strings.TrimSpace(builder.String())
+}
+
+func TextSimilarity(a, b string) float64 {
+	a = NormalizeText(a)
+	b = NormalizeText(b)
+	if a == b {
+		return 1
+	}
+
+	left := bigrams(a)
+	right := bigrams(b)
+	if len(left) == 0 || len(right) == 0 {
+		return 0
+	}
+
+	matches := 0
+	for gram, count := range left {
+		matches += min(count, right[gram])
+	}
+
+	total := 0
+	for _, count := range left {
+		total += count
+	}
+	for _, count := range right {
+		total += count
+	}
+	return float64(2*matches) / float64(total)
+}
+
+func bigrams(value string) map[string]int {
+	runes := []rune(value)
+	grams := make(map[string]int, len(runes))
+	for i := 0; i+1 < len(runes); i++ {
+		grams[string(runes[i:i+2])]++
+	}
+	return grams
+}
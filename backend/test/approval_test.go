@@ -0,0 +1,301 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/usecase"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApproveAdvancesThroughSteps(t *testing.T) {
+	db := newTestDB(t)
+	director := createTestUser(t, db, constants.RoleDirector, nil)
+	finance := createTestUser(t, db, constants.RoleFinance, &director.ID)
+	manager := createTestUser(t, db, constants.RoleManager, &finance.ID)
+	employee := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+	category := createTestCategory(t, db, 1_000_000, nil)
+
+	expenseUseCase := newTestExpenseUseCase(db, nil)
+	ctx := context.Background()
+
+	created, err := expenseUseCase.Create(ctx, authFor(employee), &model.CreateExpenseRequest{CategoryID: category.ID, AmountIDR: 25_000_000, Description: "Conference sponsorship"})
+	require.NoError(t, err)
+	require.Equal(t, constants.ExpenseStatusAwaitingApproval, created.Status)
+
+	var approvals []entity.Approval
+	require.NoError(t, db.Where("expense_id = ?", created.ID).Order("step").Find(&approvals).Error)
+	require.Len(t, approvals, 3)
+	require.Equal(t, []string{constants.RoleManager, constants.RoleFinance, constants.RoleDirector},
+		[]string{approvals[0].RequiredRole, approvals[1].RequiredRole, approvals[2].RequiredRole})
+
+	_, err = expenseUseCase.Approve(ctx, authFor(finance), created.ID, &model.ApproveExpenseRequest{})
+	requireHTTPStatus(t, err, http.StatusForbidden)
+
+	response, err := expenseUseCase.Approve(ctx, authFor(manager), created.ID, &model.ApproveExpenseRequest{})
+	require.NoError(t, err)
+	require.Equal(t, constants.ExpenseStatusAwaitingApproval, response.Status)
+
+	response, err = expenseUseCase.Approve(ctx, authFor(finance), created.ID, &model.ApproveExpenseRequest{})
+	require.NoError(t, err)
+	require.Equal(t, constants.ExpenseStatusAwaitingApproval, response.Status)
+
+	response, err = expenseUseCase.Approve(ctx, authFor(director), created.ID, &model.ApproveExpenseRequest{Notes: "Budgeted"})
+	require.NoError(t, err)
+	require.Equal(t, constants.ExpenseStatusApproved, response.Status)
+
+	history := latestTestHistory(t, db, created.ID)
+	require.Equal(t, constants.ExpenseStatusAwaitingApproval, history.PreviousStatus)
+	require.Equal(t, constants.ExpenseStatusApproved, history.NewStatus)
+	require.Equal(t, "Budgeted", history.Notes)
+}
+
+func TestRejectSkipsRemainingSteps(t *testing.T) {
+	db := newTestDB(t)
+	finance := createTestUser(t, db, constants.RoleFinance, nil)
+	manager := createTestUser(t, db, constants.RoleManager, &finance.ID)
+	employee := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+	category := createTestCategory(t, db, 1_000_000, nil)
+
+	expenseUseCase := newTestExpenseUseCase(db, nil)
+	ctx := context.Background()
+
+	created, err := expenseUseCase.Create(ctx, authFor(employee), &model.CreateExpenseRequest{CategoryID: category.ID, AmountIDR: 6_000_000, Description: "Team offsite"})
+	require.NoError(t, err)
+
+	_, err = expenseUseCase.Approve(ctx, authFor(manager), created.ID, &model.ApproveExpenseRequest{})
+	require.NoError(t, err)
+
+	response, err := expenseUseCase.Reject(ctx, authFor(finance), created.ID, &model.ApproveExpenseRequest{Notes: "Over policy"})
+	require.NoError(t, err)
+	require.Equal(t, constants.ExpenseStatusRejected, response.Status)
+
+	var pending int64
+	require.NoError(t, db.Model(&entity.Approval{}).Where("expense_id = ? AND status = ?", created.ID, constants.ApprovalStatusPending).Count(&pending).Error)
+	require.Zero(t, pending)
+}
+
+func TestApproveResolvesReportingLine(t *testing.T) {
+	db := newTestDB(t)
+	director := createTestUser(t, db, constants.RoleDirector, nil)
+	seniorManager := createTestUser(t, db, constants.RoleManager, &director.ID)
+	manager := createTestUser(t, db, constants.RoleManager, &seniorManager.ID)
+	employee := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+	otherManager := createTestUser(t, db, constants.RoleManager, &director.ID)
+	unmanaged := createTestUser(t, db, constants.RoleEmployee, nil)
+	finance := createTestUser(t, db, constants.RoleFinance, nil)
+	category := createTestCategory(t, db, 1_000_000, nil)
+
+	tests := []struct {
+		name      string
+		requester *entity.User
+		approved  []*entity.User
+		approver  *entity.User
+		openRoles []string
+		message   string
+	}{
+		{name: "direct-manager", requester: employee, approver: manager},
+		{name: "skip-level-manager", requester: employee, approver: seniorManager},
+		{name: "manager-outside-chain", requester: employee, approver: otherManager, message: messages.ErrApproverOutsideChain},
+		{name: "director-on-manager-step", requester: employee, approver: director, message: messages.ErrApprovalStepRole},
+		{name: "no-manager-in-chain", requester: unmanaged, approver: otherManager, message: messages.ErrNoApproverInChain},
+		{name: "self-approval", requester: manager, approver: manager, message: messages.ErrSelfApproval},
+		{name: "open-finance-step", requester: employee, approved: []*entity.User{manager}, approver: finance, openRoles: []string{constants.RoleFinance}},
+		{name: "closed-finance-step", requester: employee, approved: []*entity.User{manager}, approver: finance, message: messages.ErrNoApproverInChain},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expenseUseCase := newTestExpenseUseCase(db, nil)
+			expenseUseCase.ApprovalPolicy.OpenRoles = tt.openRoles
+			ctx := context.Background()
+
+			amount := int64(2_000_000)
+			if len(tt.approved) > 0 {
+				amount = 6_000_000
+			}
+			created, err := expenseUseCase.Create(ctx, authFor(tt.requester), &model.CreateExpenseRequest{CategoryID: category.ID, AmountIDR: amount, Description: "Client dinner " + tt.name})
+			require.NoError(t, err)
+			for _, approver := range tt.approved {
+				_, err := expenseUseCase.Approve(ctx, authFor(approver), created.ID, &model.ApproveExpenseRequest{})
+				require.NoError(t, err)
+			}
+
+			_, err = expenseUseCase.Approve(ctx, authFor(tt.approver), created.ID, &model.ApproveExpenseRequest{})
+			if tt.message == "" {
+				require.NoError(t, err)
+				return
+			}
+			requireHTTPError(t, err, http.StatusForbidden, tt.message)
+		})
+	}
+}
+
+type capturingEmailSender struct {
+	requests []model.EmailRequest
+}
+
+func (s *capturingEmailSender) Send(_ context.Context, request model.EmailRequest) error {
+	s.requests = append(s.requests, request)
+	return nil
+}
+
+func TestEscalateApprovalsMovesUpTheChain(t *testing.T) {
+	db := newTestDB(t)
+	director := createTestUser(t, db, constants.RoleDirector, nil)
+	seniorManager := createTestUser(t, db, constants.RoleManager, &director.ID)
+	manager := createTestUser(t, db, constants.RoleManager, &seniorManager.ID)
+	employee := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+	category := createTestCategory(t, db, 1_000_000, nil)
+
+	emails := &capturingEmailSender{}
+	expenseUseCase := newTestExpenseUseCase(db, nil)
+	expenseUseCase.EmailSender = emails
+	expenseUseCase.EscalationAfter = time.Hour
+	ctx := context.Background()
+
+	created, err := expenseUseCase.Create(ctx, authFor(employee), &model.CreateExpenseRequest{CategoryID: category.ID, AmountIDR: 2_000_000, Description: "Client dinner"})
+	require.NoError(t, err)
+	require.Len(t, emails.requests, 1)
+	require.Equal(t, []string{manager.Email}, emails.requests[0].To)
+
+	escalated, err := expenseUseCase.EscalateApprovals(ctx, 10)
+	require.NoError(t, err)
+	require.Zero(t, escalated)
+
+	_, err = expenseUseCase.Approve(ctx, authFor(director), created.ID, &model.ApproveExpenseRequest{})
+	requireHTTPStatus(t, err, http.StatusForbidden)
+
+	overdue := func() {
+		require.NoError(t, db.Model(&entity.Approval{}).Where("expense_id = ?", created.ID).
+			Updates(map[string]any{"activated_at": time.Now().Add(-3 * time.Hour), "escalated_at": time.Now().Add(-2 * time.Hour)}).Error)
+	}
+
+	overdue()
+	escalated, err = expenseUseCase.EscalateApprovals(ctx, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, escalated)
+	require.Equal(t, []string{seniorManager.Email}, emails.requests[len(emails.requests)-1].To)
+
+	escalated, err = expenseUseCase.EscalateApprovals(ctx, 10)
+	require.NoError(t, err)
+	require.Zero(t, escalated)
+
+	overdue()
+	escalated, err = expenseUseCase.EscalateApprovals(ctx, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, escalated)
+	require.Equal(t, []string{director.Email}, emails.requests[len(emails.requests)-1].To)
+
+	response, err := expenseUseCase.Approve(ctx, authFor(director), created.ID, &model.ApproveExpenseRequest{})
+	require.NoError(t, err)
+	require.Equal(t, constants.ExpenseStatusApproved, response.Status)
+}
+
+func TestCreateAutoApprovesBelowThreshold(t *testing.T) {
+	db := newTestDB(t)
+	employee := createTestUser(t, db, constants.RoleEmployee, nil)
+	category := createTestCategory(t, db, 1_000_000, nil)
+
+	response, err := newTestExpenseUseCase(db, nil).Create(context.Background(), authFor(employee), &model.CreateExpenseRequest{CategoryID: category.ID, AmountIDR: 999_999, Description: "Taxi"})
+	require.NoError(t, err)
+	require.Equal(t, constants.ExpenseStatusAutoApproved, response.Status)
+
+	var steps int64
+	require.NoError(t, db.Model(&entity.Approval{}).Where("expense_id = ?", response.ID).Count(&steps).Error)
+	require.Zero(t, steps)
+}
+
+func TestParseApprovalTiers(t *testing.T) {
+	tiers, err := usecase.ParseApprovalTiers("5000000:manager,finance; 1000000:manager")
+	require.NoError(t, err)
+
+	policy := usecase.NewApprovalPolicy(tiers)
+	require.Equal(t, []string{constants.RoleManager}, policy.Steps(2000000, 0))
+	require.Equal(t, []string{constants.RoleManager, constants.RoleFinance}, policy.Steps(7000000, 0))
+
+	tiers, err = usecase.ParseApprovalTiers(">5000000:manager,finance;1000000:manager")
+	require.NoError(t, err)
+	policy = usecase.NewApprovalPolicy(tiers)
+	require.Equal(t, []string{constants.RoleManager}, policy.Steps(5000000, 0))
+	require.Equal(t, []string{constants.RoleManager, constants.RoleFinance}, policy.Steps(5000001, 0))
+
+	invalid := []string{"manager", "abc:manager", "1000000:", "1000000:employee", ">:manager", ">-5:manager"}
+	for _, raw := range invalid {
+		_, err := usecase.ParseApprovalTiers(raw)
+		require.Error(t, err, raw)
+	}
+}
+
+func TestDefaultApprovalTiersDirectorAboveTwentyMillion(t *testing.T) {
+	policy := usecase.NewApprovalPolicy(nil)
+	require.Equal(t, []string{constants.RoleManager}, policy.Steps(1_000_000, 0))
+	require.Equal(t, []string{constants.RoleManager, constants.RoleFinance}, policy.Steps(5_000_000, 0))
+	require.Equal(t, []string{constants.RoleManager, constants.RoleFinance}, policy.Steps(20_000_000, 0))
+	require.Equal(t, []string{constants.RoleManager, constants.RoleFinance, constants.RoleDirector}, policy.Steps(20_000_001, 0))
+}
+
+func TestSplitExpensePolicyRelated(t *testing.T) {
+	candidates := []entity.Expense{
+		{Description: "Team lunch client visit"},
+		{Description: "Team lunch client visit (2)"},
+		{Description: "Parking"},
+	}
+
+	policy := &usecase.SplitExpensePolicy{Window: 72 * time.Hour}
+	require.True(t, policy.Enabled())
+	require.Len(t, policy.Related("Team lunch", candidates), 3)
+
+	policy.MatchDescription = true
+	policy.MinSimilarity = 0.6
+	require.Len(t, policy.Related("team lunch - client visit", candidates), 2)
+
+	var disabled *usecase.SplitExpensePolicy
+	require.False(t, disabled.Enabled())
+}
+
+func TestCreateRoutesSplitExpenseToApproval(t *testing.T) {
+	db := newTestDB(t)
+	manager := createTestUser(t, db, constants.RoleManager, nil)
+	meals := createTestCategory(t, db, 1_000_000, nil)
+	transport := createTestCategory(t, db, 1_000_000, nil)
+
+	expenseUseCase := newTestExpenseUseCase(db, nil)
+	expenseUseCase.SplitPolicy = &usecase.SplitExpensePolicy{Window: 72 * time.Hour}
+	ctx := context.Background()
+
+	create := func(employee *entity.User, category *entity.ExpenseCategory, amount int64) *model.ExpenseResponse {
+		response, err := expenseUseCase.Create(ctx, authFor(employee), &model.CreateExpenseRequest{CategoryID: category.ID, AmountIDR: amount, Description: "Client dinner"})
+		require.NoError(t, err)
+		return response
+	}
+
+	// earlier claims count towards the threshold whatever their category
+	employee := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+	require.Equal(t, constants.ExpenseStatusAutoApproved, create(employee, meals, 600_000).Status)
+	split := create(employee, transport, 500_000)
+	require.Equal(t, constants.ExpenseStatusAwaitingApproval, split.Status)
+
+	history := latestTestHistory(t, db, split.ID)
+	require.Equal(t, constants.ExpenseStatusAwaitingApproval, history.NewStatus)
+	require.Contains(t, history.Notes, "Possible split expense")
+	require.Contains(t, history.Notes, "1 auto-approved expense(s)")
+
+	var steps int64
+	require.NoError(t, db.Model(&entity.Approval{}).Where("expense_id = ?", split.ID).Count(&steps).Error)
+	require.NotZero(t, steps)
+
+	// restricted to the same category, the other category is not summed
+	expenseUseCase.SplitPolicy.SameCategory = true
+	other := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+	require.Equal(t, constants.ExpenseStatusAutoApproved, create(other, meals, 600_000).Status)
+	require.Equal(t, constants.ExpenseStatusAutoApproved, create(other, transport, 500_000).Status)
+	require.Equal(t, constants.ExpenseStatusAwaitingApproval, create(other, transport, 500_000).Status)
+}
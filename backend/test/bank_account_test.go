@@ -0,0 +1,142 @@
+package test
+
+import (
+	"context"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-expense-management-system/internal/background"
+	"go-expense-management-system/internal/config"
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/delivery/http"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/repository"
+	"go-expense-management-system/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateBankAccount(t *testing.T) {
+	db := newTestDB(t)
+	employee := createTestUser(t, db, constants.RoleEmployee, nil)
+	createTestBankAccount(t, db, employee.ID, true)
+
+	log := newTestLogger()
+	bankAccountUseCase := usecase.NewBankAccountUseCase(db, log, repository.NewUserBankAccountRepository(log),
+		repository.NewUserRepository(log), repository.NewPaymentJobRepository(log))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	controller := http.NewBankAccountController(bankAccountUseCase, log, config.NewValidator())
+	router.PUT("/users/me/bank-account", func(ctx *gin.Context) {
+		ctx.Set("auth", authFor(employee))
+	}, controller.Update)
+
+	update := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(nethttp.MethodPut, "/users/me/bank-account", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := update(`{"bank_code":"BCA","account_number":"12-3456-789","account_holder_name":"Test Holder"}`)
+	require.Equal(t, nethttp.StatusBadRequest, rec.Code)
+
+	// saving the same details keeps the verification
+	rec = update(`{"bank_code":"bca","account_number":"1234567890","account_holder_name":"Test Holder"}`)
+	require.Equal(t, nethttp.StatusOK, rec.Code)
+	account, err := bankAccountUseCase.Me(context.Background(), authFor(employee))
+	require.NoError(t, err)
+	require.True(t, account.IsVerified)
+
+	rec = update(`{"bank_code":"BCA","account_number":"5555555555","account_holder_name":"Test Holder"}`)
+	require.Equal(t, nethttp.StatusOK, rec.Code)
+	account, err = bankAccountUseCase.Me(context.Background(), authFor(employee))
+	require.NoError(t, err)
+	require.False(t, account.IsVerified)
+	require.Nil(t, account.VerifiedAt)
+	require.Equal(t, "5555555555", account.AccountNumber)
+}
+
+func TestPaymentWaitsForVerifiedBankAccount(t *testing.T) {
+	db := newTestDB(t)
+	manager := createTestUser(t, db, constants.RoleManager, nil)
+	employee := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+	account := createTestBankAccount(t, db, employee.ID, false)
+	expense := createTestExpense(t, db, employee.ID, constants.ExpenseStatusApproved, 750_000)
+
+	var mu sync.Mutex
+	requests := make([]model.PaymentRequest, 0)
+	expenseUseCase := newTestExpenseUseCase(db, nil)
+	expenseUseCase.PaymentProcessor = paymentProcessorFunc(func(_ context.Context, request model.PaymentRequest) (*model.PaymentResponse, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, request)
+		return &model.PaymentResponse{ID: "pay_1", ExternalID: request.ExternalID, Status: constants.PaymentProviderStatusCompleted}, nil
+	})
+
+	queue := newTestPaymentQueue(db, time.Minute)
+	worker := background.NewPaymentWorker(queue, 1, 10, 3, time.Second, time.Minute, time.Second, 10*time.Millisecond, newTestLogger(),
+		expenseUseCase.ProcessPayment, expenseUseCase.MarkPaymentFailed)
+	require.NoError(t, queue.Enqueue(db, model.PaymentJob{ExpenseID: expense.ID, AmountIDR: expense.AmountIDR, ExternalID: expense.ID.String()}))
+	worker.Start()
+	t.Cleanup(func() { _ = worker.Stop(context.Background()) })
+
+	require.Eventually(t, func() bool {
+		return findTestPaymentJob(t, db, expense).Status == constants.PaymentJobStatusHeld
+	}, 5*time.Second, 10*time.Millisecond)
+
+	job := findTestPaymentJob(t, db, expense)
+	require.Zero(t, job.Attempts)
+	require.NotEmpty(t, job.LastError)
+	requireTestExpenseStatus(t, db, expense, constants.ExpenseStatusApproved)
+	history := latestTestHistory(t, db, expense.ID)
+	require.Equal(t, constants.ExpenseStatusApproved, history.PreviousStatus)
+	require.Equal(t, constants.ExpenseStatusApproved, history.NewStatus)
+	require.Contains(t, history.Notes, "verified bank account")
+	mu.Lock()
+	require.Empty(t, requests)
+	mu.Unlock()
+
+	// a held job is neither claimed again nor rescanned
+	enqueued, err := queue.Rescan(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, enqueued)
+
+	log := newTestLogger()
+	bankAccountUseCase := usecase.NewBankAccountUseCase(db, log, repository.NewUserBankAccountRepository(log),
+		repository.NewUserRepository(log), repository.NewPaymentJobRepository(log))
+	verified, err := bankAccountUseCase.Verify(context.Background(), employee.Email)
+	require.NoError(t, err)
+	require.True(t, verified.IsVerified)
+	require.NotNil(t, verified.VerifiedAt)
+
+	require.Eventually(t, func() bool {
+		return findTestPaymentJob(t, db, expense).Status == constants.PaymentJobStatusCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+	requireTestExpenseStatus(t, db, expense, constants.ExpenseStatusCompleted)
+	mu.Lock()
+	require.Len(t, requests, 1)
+	require.Equal(t, account.AccountNumber, requests[0].AccountNumber)
+	mu.Unlock()
+}
+
+func TestVerifyBankAccountUnknownUser(t *testing.T) {
+	db := newTestDB(t)
+	log := newTestLogger()
+	bankAccountUseCase := usecase.NewBankAccountUseCase(db, log, repository.NewUserBankAccountRepository(log),
+		repository.NewUserRepository(log), repository.NewPaymentJobRepository(log))
+
+	_, err := bankAccountUseCase.Verify(context.Background(), "nobody@example.com")
+	requireHTTPStatus(t, err, nethttp.StatusNotFound)
+
+	employee := createTestUser(t, db, constants.RoleEmployee, nil)
+	_, err = bankAccountUseCase.Verify(context.Background(), employee.Email)
+	requireHTTPStatus(t, err, nethttp.StatusNotFound)
+}
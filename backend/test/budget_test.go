@@ -0,0 +1,85 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/repository"
+	"go-expense-management-system/internal/usecase"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBudgetPeriodWindow(t *testing.T) {
+	now := time.Date(2024, time.August, 17, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		period    string
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{name: "monthly", period: constants.BudgetPeriodMonthly, wantStart: time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC), wantEnd: time.Date(2024, time.September, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "quarterly", period: constants.BudgetPeriodQuarterly, wantStart: time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC), wantEnd: time.Date(2024, time.October, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		start, end := usecase.BudgetPeriodWindow(tt.period, now)
+		require.Equal(t, tt.wantStart, start, tt.name)
+		require.Equal(t, tt.wantEnd, end, tt.name)
+	}
+}
+
+func TestBudgetCheck(t *testing.T) {
+	userID := uuid.New()
+	departmentID := uuid.New()
+
+	check := &usecase.BudgetCheck{Exceeded: []usecase.BudgetStatus{
+		{
+			Budget: entity.Budget{UserID: &userID, Period: constants.BudgetPeriodMonthly, LimitIDR: 5000000, Enforcement: constants.BudgetEnforcementRequireApproval},
+			Usage:  repository.BudgetUsage{SpentIDR: 3000000, PendingIDR: 1500000},
+		},
+	}}
+	require.False(t, check.Blocked())
+	require.Equal(t, "Exceeds monthly user budget (remaining Rp 500.000)", check.Note())
+
+	check.Exceeded = append(check.Exceeded, usecase.BudgetStatus{
+		Budget: entity.Budget{DepartmentID: &departmentID, Period: constants.BudgetPeriodQuarterly, LimitIDR: 1000000, Enforcement: constants.BudgetEnforcementBlock},
+		Usage:  repository.BudgetUsage{SpentIDR: 2000000},
+	})
+	require.True(t, check.Blocked())
+	require.Equal(t, int64(0), check.Exceeded[1].RemainingIDR())
+}
+
+func TestCreateEnforcesBudgets(t *testing.T) {
+	db := newTestDB(t)
+	manager := createTestUser(t, db, constants.RoleManager, nil)
+	employee := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+	category := createTestCategory(t, db, 1_000_000, nil)
+
+	require.NoError(t, db.Create(&entity.Budget{UserID: &employee.ID, Period: constants.BudgetPeriodMonthly, LimitIDR: 1_000_000, Enforcement: constants.BudgetEnforcementRequireApproval, IsActive: true}).Error)
+
+	expenseUseCase := newTestExpenseUseCase(db, nil)
+	ctx := context.Background()
+
+	first, err := expenseUseCase.Create(ctx, authFor(employee), &model.CreateExpenseRequest{CategoryID: category.ID, AmountIDR: 700_000, Description: "Taxi"})
+	require.NoError(t, err)
+	require.Equal(t, constants.ExpenseStatusAutoApproved, first.Status)
+
+	second, err := expenseUseCase.Create(ctx, authFor(employee), &model.CreateExpenseRequest{CategoryID: category.ID, AmountIDR: 400_000, Description: "Hotel breakfast"})
+	require.NoError(t, err)
+	require.Equal(t, constants.ExpenseStatusAwaitingApproval, second.Status)
+	require.Equal(t, "Exceeds monthly user budget (remaining Rp 300.000)", latestTestHistory(t, db, second.ID).Notes)
+
+	require.NoError(t, db.Model(&entity.Budget{}).Where("user_id = ?", employee.ID).Update("enforcement", constants.BudgetEnforcementBlock).Error)
+
+	_, err = expenseUseCase.Create(ctx, authFor(employee), &model.CreateExpenseRequest{CategoryID: category.ID, AmountIDR: 200_000, Description: "Parking"})
+	requireHTTPError(t, err, http.StatusUnprocessableEntity, messages.ErrBudgetExceeded)
+}
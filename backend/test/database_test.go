@@ -0,0 +1,155 @@
+package test
+
+import (
+	"io"
+	"testing"
+
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/repository"
+	"go-expense-management-system/internal/usecase"
+	"go-expense-management-system/internal/utils"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file:"+uuid.NewString()+"?mode=memory&cache=shared&_pragma=busy_timeout(5000)"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	require.NoError(t, db.AutoMigrate(&entity.Department{}, &entity.User{}, &entity.Budget{}, &entity.ExpenseCategory{}, &entity.Expense{}, &entity.ExpenseReceipt{}, &entity.Approval{}, &entity.ExpenseStatusHistory{}, &entity.UserBankAccount{}, &entity.PaymentJob{}, &entity.Payment{}, &entity.PaymentWebhookEvent{}, &entity.PayoutBatch{}, &entity.PayoutBatchItem{}, &entity.IdempotencyKey{}))
+	return db
+}
+
+func newTestLogger() *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return log
+}
+
+func createTestUser(t *testing.T, db *gorm.DB, role string, managerID *uuid.UUID) *entity.User {
+	t.Helper()
+
+	id := uuid.New()
+	user := &entity.User{
+		ID:           id,
+		Name:         role + " " + id.String()[:8],
+		Email:        id.String() + "@example.com",
+		Role:         role,
+		ManagerID:    managerID,
+		PasswordHash: "-",
+	}
+	require.NoError(t, db.Create(user).Error)
+	return user
+}
+
+func createTestCategory(t *testing.T, db *gorm.DB, threshold int64, receiptAbove *int64) *entity.ExpenseCategory {
+	t.Helper()
+
+	category := &entity.ExpenseCategory{
+		Code:                    "cat-" + uuid.NewString()[:8],
+		Name:                    "Test category",
+		MinAmountIDR:            10_000,
+		MaxAmountIDR:            50_000_000,
+		ApprovalThresholdIDR:    threshold,
+		ReceiptRequiredAboveIDR: receiptAbove,
+		IsActive:                true,
+	}
+	require.NoError(t, db.Create(category).Error)
+	return category
+}
+
+func createTestBankAccount(t *testing.T, db *gorm.DB, userID uuid.UUID, verified bool) *entity.UserBankAccount {
+	t.Helper()
+
+	account := &entity.UserBankAccount{
+		UserID:            userID,
+		BankCode:          "BCA",
+		AccountNumber:     "1234567890",
+		AccountHolderName: "Test Holder",
+		IsVerified:        verified,
+	}
+	require.NoError(t, db.Create(account).Error)
+	return account
+}
+
+func newTestExpenseUseCase(db *gorm.DB, duplicatePolicy *usecase.DuplicateExpensePolicy) *usecase.ExpenseUseCase {
+	log := newTestLogger()
+	userRepository := repository.NewUserRepository(log)
+	return usecase.NewExpenseUseCase(
+		db,
+		log,
+		repository.NewExpenseRepository(log),
+		repository.NewApprovalRepository(log),
+		repository.NewExpenseStatusHistoryRepository(log),
+		userRepository,
+		repository.NewExpenseCategoryRepository(log),
+		repository.NewExpenseReceiptRepository(log),
+		repository.NewPaymentRepository(log),
+		repository.NewUserBankAccountRepository(log),
+		usecase.NewBudgetTracker(log, repository.NewBudgetRepository(log), userRepository),
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		duplicatePolicy,
+	)
+}
+
+func authFor(user *entity.User) *model.Auth {
+	return &model.Auth{UserID: user.ID, Role: user.Role}
+}
+
+func createTestExpense(t *testing.T, db *gorm.DB, userID uuid.UUID, status string, amount int64) *entity.Expense {
+	t.Helper()
+
+	expense := &entity.Expense{
+		UserID:      userID,
+		AmountIDR:   amount,
+		Description: "Test expense",
+		Status:      status,
+		Version:     1,
+	}
+	require.NoError(t, db.Create(expense).Error)
+	return expense
+}
+
+func requireHTTPStatus(t *testing.T, err error, status int) {
+	t.Helper()
+
+	var httpErr utils.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, status, httpErr.Status())
+}
+
+func requireHTTPError(t *testing.T, err error, status int, message string) {
+	t.Helper()
+
+	var httpErr utils.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, status, httpErr.Status())
+	require.Equal(t, message, httpErr.Message())
+}
+
+func latestTestHistory(t *testing.T, db *gorm.DB, expenseID uuid.UUID) entity.ExpenseStatusHistory {
+	t.Helper()
+
+	var history entity.ExpenseStatusHistory
+	require.NoError(t, db.Where("expense_id = ?", expenseID).Order("created_at desc").Order("id").First(&history).Error)
+	return history
+}
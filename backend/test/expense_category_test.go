@@ -0,0 +1,86 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateEnforcesCategoryLimits(t *testing.T) {
+	db := newTestDB(t)
+	manager := createTestUser(t, db, constants.RoleManager, nil)
+	employee := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+	category := createTestCategory(t, db, 1_000_000, nil)
+	inactive := createTestCategory(t, db, 1_000_000, nil)
+	require.NoError(t, db.Model(inactive).Update("is_active", false).Error)
+
+	tests := []struct {
+		name       string
+		categoryID uuid.UUID
+		amount     int64
+		message    string
+	}{
+		{name: "below-category-min", categoryID: category.ID, amount: 9_999, message: messages.ErrInvalidExpenseAmount},
+		{name: "above-category-max", categoryID: category.ID, amount: 50_000_001, message: messages.ErrInvalidExpenseAmount},
+		{name: "inactive-category", categoryID: inactive.ID, amount: 100_000, message: messages.ErrCategoryInactive},
+		{name: "unknown-category", categoryID: uuid.New(), amount: 100_000, message: messages.ErrCategoryNotFound},
+		{name: "at-category-min", categoryID: category.ID, amount: 10_000},
+		{name: "at-category-max", categoryID: category.ID, amount: 50_000_000},
+	}
+
+	expenseUseCase := newTestExpenseUseCase(db, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := expenseUseCase.Create(context.Background(), authFor(employee), &model.CreateExpenseRequest{CategoryID: tt.categoryID, AmountIDR: tt.amount, Description: "Hotel " + tt.name})
+			if tt.message == "" {
+				require.NoError(t, err)
+				return
+			}
+			requireHTTPError(t, err, http.StatusBadRequest, tt.message)
+		})
+	}
+
+	var count int64
+	require.NoError(t, db.Model(&entity.Expense{}).Count(&count).Error)
+	require.EqualValues(t, 2, count)
+}
+
+func TestCreateRequiresReceiptAboveCategoryLimit(t *testing.T) {
+	db := newTestDB(t)
+	manager := createTestUser(t, db, constants.RoleManager, nil)
+	employee := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+	receiptAbove := int64(200_000)
+	category := createTestCategory(t, db, 1_000_000, &receiptAbove)
+
+	expenseUseCase := newTestExpenseUseCase(db, nil)
+	ctx := context.Background()
+
+	withReceipt, err := expenseUseCase.Create(ctx, authFor(employee), &model.CreateExpenseRequest{CategoryID: category.ID, AmountIDR: 300_000, Description: "Train ticket", ReceiptURL: "https://files.example.com/r/train.pdf"})
+	require.NoError(t, err)
+	require.Equal(t, constants.ExpenseStatusAutoApproved, withReceipt.Status)
+
+	underLimit, err := expenseUseCase.Create(ctx, authFor(employee), &model.CreateExpenseRequest{CategoryID: category.ID, AmountIDR: 200_000, Description: "Taxi"})
+	require.NoError(t, err)
+	require.Equal(t, constants.ExpenseStatusAutoApproved, underLimit.Status)
+
+	missingReceipt, err := expenseUseCase.Create(ctx, authFor(employee), &model.CreateExpenseRequest{CategoryID: category.ID, AmountIDR: 300_000, Description: "Hotel breakfast"})
+	require.NoError(t, err)
+	require.Equal(t, constants.ExpenseStatusAwaitingApproval, missingReceipt.Status)
+
+	_, err = expenseUseCase.Approve(ctx, authFor(manager), missingReceipt.ID, &model.ApproveExpenseRequest{})
+	requireHTTPError(t, err, http.StatusConflict, messages.ErrReceiptRequired)
+
+	require.NoError(t, db.Create(&entity.ExpenseReceipt{ExpenseID: missingReceipt.ID, UploadedBy: employee.ID, FileName: "breakfast.jpg", StorageKey: "receipts/breakfast.jpg", ContentType: "image/jpeg", SizeBytes: 1024, SHA256: "abc"}).Error)
+
+	approved, err := expenseUseCase.Approve(ctx, authFor(manager), missingReceipt.ID, &model.ApproveExpenseRequest{})
+	require.NoError(t, err)
+	require.Equal(t, constants.ExpenseStatusApproved, approved.Status)
+}
@@ -0,0 +1,74 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/usecase"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateFlagsPossibleDuplicate(t *testing.T) {
+	original := model.CreateExpenseRequest{AmountIDR: 250_000, Description: "Taxi to airport", ReceiptURL: "https://files.example.com/r/1.jpg"}
+
+	tests := []struct {
+		name            string
+		request         model.CreateExpenseRequest
+		cancelOriginal  bool
+		otherRequester  bool
+		wantDuplicateOf bool
+	}{
+		{name: "same-receipt-url", request: model.CreateExpenseRequest{AmountIDR: 90_000, Description: "Lunch", ReceiptURL: original.ReceiptURL}, wantDuplicateOf: true},
+		{name: "same-amount-similar-description", request: model.CreateExpenseRequest{AmountIDR: 250_000, Description: "taxi to the airport"}, wantDuplicateOf: true},
+		{name: "same-amount-different-description", request: model.CreateExpenseRequest{AmountIDR: 250_000, Description: "Hotel breakfast"}},
+		{name: "similar-description-different-amount", request: model.CreateExpenseRequest{AmountIDR: 260_000, Description: "Taxi to airport"}},
+		{name: "original-cancelled", request: original, cancelOriginal: true},
+		{name: "other-requester", request: original, otherRequester: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			manager := createTestUser(t, db, constants.RoleManager, nil)
+			employee := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+			category := createTestCategory(t, db, 1_000_000, nil)
+
+			expenseUseCase := newTestExpenseUseCase(db, &usecase.DuplicateExpensePolicy{Window: 30 * 24 * time.Hour, MinSimilarity: 0.8})
+			ctx := context.Background()
+
+			first := original
+			first.CategoryID = category.ID
+			created, err := expenseUseCase.Create(ctx, authFor(employee), &first)
+			require.NoError(t, err)
+			require.Nil(t, created.PossibleDuplicateOf)
+			if tt.cancelOriginal {
+				require.NoError(t, db.Model(&entity.Expense{}).Where("id = ?", created.ID).Update("status", constants.ExpenseStatusCancelled).Error)
+			}
+
+			requester := employee
+			if tt.otherRequester {
+				requester = createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+			}
+			request := tt.request
+			request.CategoryID = category.ID
+			response, err := expenseUseCase.Create(ctx, authFor(requester), &request)
+			require.NoError(t, err)
+
+			var expense entity.Expense
+			require.NoError(t, db.Take(&expense, "id = ?", response.ID).Error)
+			if !tt.wantDuplicateOf {
+				require.Nil(t, expense.PossibleDuplicateOf)
+				return
+			}
+			require.NotNil(t, expense.PossibleDuplicateOf)
+			require.Equal(t, created.ID, *expense.PossibleDuplicateOf)
+			require.Equal(t, created.ID, *response.PossibleDuplicateOf)
+			require.Contains(t, latestTestHistory(t, db, response.ID).Notes, "Possible duplicate of expense "+created.ID.String())
+		})
+	}
+}
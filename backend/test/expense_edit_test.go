@@ -0,0 +1,149 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"go-expense-management-system/internal/background"
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func findTestApprovals(t *testing.T, db *gorm.DB, expenseID uuid.UUID) []entity.Approval {
+	t.Helper()
+
+	var approvals []entity.Approval
+	require.NoError(t, db.Where("expense_id = ?", expenseID).Order("step").Find(&approvals).Error)
+	return approvals
+}
+
+func TestUpdateReplacesPendingApprovalChain(t *testing.T) {
+	db := newTestDB(t)
+	finance := createTestUser(t, db, constants.RoleFinance, nil)
+	manager := createTestUser(t, db, constants.RoleManager, &finance.ID)
+	employee := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+	category := createTestCategory(t, db, 1_000_000, nil)
+
+	expenseUseCase := newTestExpenseUseCase(db, nil)
+	ctx := context.Background()
+
+	created, err := expenseUseCase.Create(ctx, authFor(employee), &model.CreateExpenseRequest{CategoryID: category.ID, AmountIDR: 6_000_000, Description: "Team offsite"})
+	require.NoError(t, err)
+	_, err = expenseUseCase.Approve(ctx, authFor(manager), created.ID, &model.ApproveExpenseRequest{})
+	require.NoError(t, err)
+
+	amount := int64(2_000_000)
+	description := "Team offsite, reduced"
+	_, err = expenseUseCase.Update(ctx, authFor(manager), &model.UpdateExpenseRequest{ID: created.ID, AmountIDR: &amount})
+	requireHTTPStatus(t, err, http.StatusForbidden)
+
+	updated, err := expenseUseCase.Update(ctx, authFor(employee), &model.UpdateExpenseRequest{ID: created.ID, AmountIDR: &amount, Description: &description})
+	require.NoError(t, err)
+	require.Equal(t, constants.ExpenseStatusAwaitingApproval, updated.Status)
+	require.Equal(t, amount, updated.AmountIDR)
+	require.Equal(t, description, updated.Description)
+
+	approvals := findTestApprovals(t, db, created.ID)
+	require.Len(t, approvals, 3)
+	require.Equal(t, constants.ApprovalStatusApproved, approvals[0].Status)
+	require.Equal(t, constants.ApprovalStatusSkipped, approvals[1].Status)
+	require.Equal(t, constants.RoleFinance, approvals[1].RequiredRole)
+	require.Equal(t, constants.ApprovalStatusPending, approvals[2].Status)
+	require.Equal(t, constants.RoleManager, approvals[2].RequiredRole)
+
+	history := latestTestHistory(t, db, created.ID)
+	require.Equal(t, constants.ExpenseStatusAwaitingApproval, history.NewStatus)
+	require.Contains(t, history.Notes, "Expense updated by requester")
+
+	approved, err := expenseUseCase.Approve(ctx, authFor(manager), created.ID, &model.ApproveExpenseRequest{})
+	require.NoError(t, err)
+	require.Equal(t, constants.ExpenseStatusApproved, approved.Status)
+}
+
+func TestUpdateBelowThresholdAutoApproves(t *testing.T) {
+	db := newTestDB(t)
+	manager := createTestUser(t, db, constants.RoleManager, nil)
+	employee := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+	category := createTestCategory(t, db, 1_000_000, nil)
+
+	expenseUseCase := newTestExpenseUseCase(db, nil)
+	expenseUseCase.PaymentQueue = background.NewPaymentWorker(newTestPaymentQueue(db, time.Minute), 1, 10, 3, time.Second, time.Minute, time.Second, time.Hour, nil, nil, nil)
+	ctx := context.Background()
+
+	created, err := expenseUseCase.Create(ctx, authFor(employee), &model.CreateExpenseRequest{CategoryID: category.ID, AmountIDR: 2_000_000, Description: "Hotel"})
+	require.NoError(t, err)
+
+	amount := int64(500_000)
+	updated, err := expenseUseCase.Update(ctx, authFor(employee), &model.UpdateExpenseRequest{ID: created.ID, AmountIDR: &amount})
+	require.NoError(t, err)
+	require.Equal(t, constants.ExpenseStatusAutoApproved, updated.Status)
+
+	approvals := findTestApprovals(t, db, created.ID)
+	require.Len(t, approvals, 1)
+	require.Equal(t, constants.ApprovalStatusSkipped, approvals[0].Status)
+
+	job := findTestPaymentJob(t, db, &entity.Expense{ID: created.ID})
+	require.Equal(t, constants.PaymentJobStatusPending, job.Status)
+
+	_, err = expenseUseCase.Update(ctx, authFor(employee), &model.UpdateExpenseRequest{ID: created.ID, AmountIDR: &amount})
+	requireHTTPStatus(t, err, http.StatusConflict)
+}
+
+func TestCancelAndResubmit(t *testing.T) {
+	db := newTestDB(t)
+	manager := createTestUser(t, db, constants.RoleManager, nil)
+	employee := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+	colleague := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+	category := createTestCategory(t, db, 1_000_000, nil)
+
+	expenseUseCase := newTestExpenseUseCase(db, nil)
+	ctx := context.Background()
+
+	created, err := expenseUseCase.Create(ctx, authFor(employee), &model.CreateExpenseRequest{CategoryID: category.ID, AmountIDR: 2_000_000, Description: "Client dinner"})
+	require.NoError(t, err)
+
+	_, err = expenseUseCase.Resubmit(ctx, authFor(employee), created.ID, &model.ExpenseActionRequest{})
+	requireHTTPStatus(t, err, http.StatusConflict)
+
+	_, err = expenseUseCase.Reject(ctx, authFor(manager), created.ID, &model.ApproveExpenseRequest{Notes: "Missing attendees"})
+	require.NoError(t, err)
+
+	_, err = expenseUseCase.Resubmit(ctx, authFor(colleague), created.ID, &model.ExpenseActionRequest{})
+	requireHTTPStatus(t, err, http.StatusForbidden)
+
+	resubmitted, err := expenseUseCase.Resubmit(ctx, authFor(employee), created.ID, &model.ExpenseActionRequest{Notes: "Added attendees"})
+	require.NoError(t, err)
+	require.Equal(t, constants.ExpenseStatusAwaitingApproval, resubmitted.Status)
+
+	approvals := findTestApprovals(t, db, created.ID)
+	require.Len(t, approvals, 2)
+	require.Equal(t, constants.ApprovalStatusRejected, approvals[0].Status)
+	require.Equal(t, constants.ApprovalStatusPending, approvals[1].Status)
+	require.Equal(t, 2, approvals[1].Step)
+
+	_, err = expenseUseCase.Cancel(ctx, authFor(manager), created.ID, &model.ExpenseActionRequest{})
+	requireHTTPStatus(t, err, http.StatusForbidden)
+
+	cancelled, err := expenseUseCase.Cancel(ctx, authFor(employee), created.ID, &model.ExpenseActionRequest{Notes: "Trip called off"})
+	require.NoError(t, err)
+	require.Equal(t, constants.ExpenseStatusCancelled, cancelled.Status)
+
+	approvals = findTestApprovals(t, db, created.ID)
+	require.Equal(t, constants.ApprovalStatusSkipped, approvals[1].Status)
+
+	history := latestTestHistory(t, db, created.ID)
+	require.Equal(t, constants.ExpenseStatusCancelled, history.NewStatus)
+	require.Equal(t, "Trip called off", history.Notes)
+
+	_, err = expenseUseCase.Resubmit(ctx, authFor(employee), created.ID, &model.ExpenseActionRequest{})
+	requireHTTPStatus(t, err, http.StatusConflict)
+	_, err = expenseUseCase.Approve(ctx, authFor(manager), created.ID, &model.ApproveExpenseRequest{})
+	requireHTTPStatus(t, err, http.StatusConflict)
+}
@@ -0,0 +1,118 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListFiltersAndSortsExpenses(t *testing.T) {
+	db := newTestDB(t)
+	manager := createTestUser(t, db, constants.RoleManager, nil)
+	alice := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+	bob := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+	travel := createTestCategory(t, db, 1_000_000, nil)
+	meals := createTestCategory(t, db, 1_000_000, nil)
+
+	base := time.Date(2024, time.August, 1, 9, 0, 0, 0, time.UTC)
+	seed := func(user *entity.User, category *entity.ExpenseCategory, amount int64, status string, day int) uuid.UUID {
+		expense := &entity.Expense{
+			UserID:      user.ID,
+			CategoryID:  &category.ID,
+			AmountIDR:   amount,
+			Description: "Expense",
+			Status:      status,
+			Version:     1,
+			SubmittedAt: base.AddDate(0, 0, day),
+		}
+		require.NoError(t, db.Create(expense).Error)
+		return expense.ID
+	}
+
+	taxi := seed(alice, travel, 150_000, constants.ExpenseStatusAutoApproved, 0)
+	flight := seed(alice, travel, 3_500_000, constants.ExpenseStatusAwaitingApproval, 1)
+	lunch := seed(alice, meals, 80_000, constants.ExpenseStatusCompleted, 2)
+	hotel := seed(bob, travel, 2_000_000, constants.ExpenseStatusApproved, 3)
+	require.NoError(t, db.Model(&entity.Expense{}).Where("id = ?", lunch).Update("possible_duplicate_of", taxi).Error)
+
+	minAmount := int64(100_000)
+	maxAmount := int64(2_000_000)
+	from := base.AddDate(0, 0, 1)
+	to := base.AddDate(0, 0, 3)
+
+	tests := []struct {
+		name    string
+		auth    *model.Auth
+		request model.ListExpenseRequest
+		want    []uuid.UUID
+	}{
+		{name: "employee-sees-own", auth: authFor(alice), want: []uuid.UUID{lunch, flight, taxi}},
+		{name: "reviewer-sees-all", auth: authFor(manager), want: []uuid.UUID{hotel, lunch, flight, taxi}},
+		{name: "reviewer-by-user", auth: authFor(manager), request: model.ListExpenseRequest{UserID: &bob.ID}, want: []uuid.UUID{hotel}},
+		{name: "employee-cannot-widen", auth: authFor(alice), request: model.ListExpenseRequest{UserID: &bob.ID}, want: []uuid.UUID{lunch, flight, taxi}},
+		{name: "status", auth: authFor(manager), request: model.ListExpenseRequest{Statuses: []string{"approved", "auto_approved"}}, want: []uuid.UUID{hotel, taxi}},
+		{name: "category", auth: authFor(manager), request: model.ListExpenseRequest{CategoryID: &meals.ID}, want: []uuid.UUID{lunch}},
+		{name: "amount-range", auth: authFor(manager), request: model.ListExpenseRequest{MinAmountIDR: &minAmount, MaxAmountIDR: &maxAmount}, want: []uuid.UUID{hotel, taxi}},
+		{name: "submitted-range", auth: authFor(manager), request: model.ListExpenseRequest{SubmittedFrom: &from, SubmittedTo: &to}, want: []uuid.UUID{lunch, flight}},
+		{name: "possible-duplicate", auth: authFor(manager), request: model.ListExpenseRequest{PossibleDuplicate: true}, want: []uuid.UUID{lunch}},
+		{name: "sort-amount-desc", auth: authFor(manager), request: model.ListExpenseRequest{Sort: "amount_idr:desc"}, want: []uuid.UUID{flight, hotel, taxi, lunch}},
+		{name: "sort-status-then-amount", auth: authFor(alice), request: model.ListExpenseRequest{Sort: "status,-amount_idr"}, want: []uuid.UUID{taxi, flight, lunch}},
+	}
+
+	expenseUseCase := newTestExpenseUseCase(db, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := tt.request
+			responses, page, err := expenseUseCase.List(context.Background(), tt.auth, &request)
+			require.NoError(t, err)
+
+			got := make([]uuid.UUID, 0, len(responses))
+			for _, response := range responses {
+				got = append(got, response.ID)
+			}
+			require.Equal(t, tt.want, got)
+			require.NotNil(t, page.TotalItem)
+			require.EqualValues(t, len(tt.want), *page.TotalItem)
+		})
+	}
+}
+
+func TestListRejectsInvalidFilters(t *testing.T) {
+	db := newTestDB(t)
+	manager := createTestUser(t, db, constants.RoleManager, nil)
+
+	minAmount := int64(500_000)
+	maxAmount := int64(100_000)
+	from := time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, -1)
+
+	tests := []struct {
+		name    string
+		request model.ListExpenseRequest
+		message string
+	}{
+		{name: "unknown-status", request: model.ListExpenseRequest{Statuses: []string{"archived"}}, message: messages.ErrInvalidExpenseFilter},
+		{name: "inverted-amount-range", request: model.ListExpenseRequest{MinAmountIDR: &minAmount, MaxAmountIDR: &maxAmount}, message: messages.ErrInvalidExpenseFilter},
+		{name: "inverted-date-range", request: model.ListExpenseRequest{SubmittedFrom: &from, SubmittedTo: &to}, message: messages.ErrInvalidExpenseFilter},
+		{name: "unknown-sort-field", request: model.ListExpenseRequest{Sort: "description"}, message: messages.ErrInvalidExpenseSort},
+		{name: "sort-injection", request: model.ListExpenseRequest{Sort: "amount_idr;drop table expenses"}, message: messages.ErrInvalidExpenseSort},
+	}
+
+	expenseUseCase := newTestExpenseUseCase(db, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := tt.request
+			_, _, err := expenseUseCase.List(context.Background(), authFor(manager), &request)
+			requireHTTPError(t, err, http.StatusBadRequest, tt.message)
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/integration/storage"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/repository"
+	"go-expense-management-system/internal/usecase"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var (
+	testJPEG = append([]byte{0xff, 0xd8, 0xff, 0xe0, 0x00, 0x10, 'J', 'F', 'I', 'F', 0x00}, bytes.Repeat([]byte{0x01}, 64)...)
+	testPNG  = append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0x02}, 64)...)
+	testPDF  = []byte("%PDF-1.4\n1 0 obj\n<<>>\nendobj\n")
+)
+
+func newTestReceiptUseCase(t *testing.T, db *gorm.DB, maxSizeBytes int64, duplicatePolicy *usecase.DuplicateExpensePolicy) *usecase.ExpenseReceiptUseCase {
+	t.Helper()
+
+	log := newTestLogger()
+	local, err := storage.NewLocalStorage(t.TempDir(), log)
+	require.NoError(t, err)
+	return usecase.NewExpenseReceiptUseCase(
+		db,
+		log,
+		repository.NewExpenseRepository(log),
+		repository.NewExpenseReceiptRepository(log),
+		local,
+		maxSizeBytes,
+		duplicatePolicy,
+	)
+}
+
+func uploadTestReceipt(receiptUseCase *usecase.ExpenseReceiptUseCase, user *entity.User, expenseID uuid.UUID, name string, content []byte) (*model.ExpenseReceiptResponse, error) {
+	return receiptUseCase.Upload(context.Background(), authFor(user), &model.UploadReceiptRequest{
+		ExpenseID: expenseID,
+		FileName:  name,
+		Size:      int64(len(content)),
+		Content:   bytes.NewReader(content),
+	})
+}
+
+func TestUploadReceiptAllowsOnlyJPEGPNGAndPDF(t *testing.T) {
+	db := newTestDB(t)
+	employee := createTestUser(t, db, constants.RoleEmployee, nil)
+	expense := createTestExpense(t, db, employee.ID, constants.ExpenseStatusAwaitingApproval, 250_000)
+	receiptUseCase := newTestReceiptUseCase(t, db, 1<<20, nil)
+
+	for contentType, content := range map[string][]byte{"image/jpeg": testJPEG, "image/png": testPNG, "application/pdf": testPDF} {
+		receipt, err := uploadTestReceipt(receiptUseCase, employee, expense.ID, "receipt", content)
+		require.NoError(t, err, contentType)
+		require.Equal(t, contentType, receipt.ContentType)
+		require.EqualValues(t, len(content), receipt.SizeBytes)
+		require.Len(t, receipt.SHA256, 64)
+	}
+
+	// the type comes from the content, not from the file name
+	_, err := uploadTestReceipt(receiptUseCase, employee, expense.ID, "taxi.pdf", []byte("plain text pretending to be a receipt"))
+	requireHTTPStatus(t, err, http.StatusUnsupportedMediaType)
+	_, err = uploadTestReceipt(receiptUseCase, employee, expense.ID, "taxi.gif", []byte("GIF89a\x01\x00\x01\x00"))
+	requireHTTPStatus(t, err, http.StatusUnsupportedMediaType)
+	_, err = uploadTestReceipt(receiptUseCase, employee, expense.ID, "empty.pdf", nil)
+	requireHTTPStatus(t, err, http.StatusBadRequest)
+
+	var stored int64
+	require.NoError(t, db.Model(&entity.ExpenseReceipt{}).Where("expense_id = ?", expense.ID).Count(&stored).Error)
+	require.EqualValues(t, 3, stored)
+}
+
+func TestUploadReceiptEnforcesSizeCap(t *testing.T) {
+	db := newTestDB(t)
+	employee := createTestUser(t, db, constants.RoleEmployee, nil)
+	expense := createTestExpense(t, db, employee.ID, constants.ExpenseStatusAwaitingApproval, 250_000)
+	receiptUseCase := newTestReceiptUseCase(t, db, 1024, nil)
+
+	large := append(append([]byte{}, testPDF...), bytes.Repeat([]byte("x"), 1024)...)
+	_, err := uploadTestReceipt(receiptUseCase, employee, expense.ID, "large.pdf", large)
+	requireHTTPStatus(t, err, http.StatusRequestEntityTooLarge)
+
+	// a client that understates the size is still cut off while streaming
+	_, err = receiptUseCase.Upload(context.Background(), authFor(employee), &model.UploadReceiptRequest{
+		ExpenseID: expense.ID,
+		FileName:  "large.pdf",
+		Size:      100,
+		Content:   bytes.NewReader(large),
+	})
+	requireHTTPStatus(t, err, http.StatusRequestEntityTooLarge)
+
+	var stored int64
+	require.NoError(t, db.Model(&entity.ExpenseReceipt{}).Where("expense_id = ?", expense.ID).Count(&stored).Error)
+	require.Zero(t, stored)
+
+	_, err = uploadTestReceipt(receiptUseCase, employee, expense.ID, "small.pdf", testPDF)
+	require.NoError(t, err)
+}
+
+func TestUploadReceiptOwnerOnlyAndOpenExpenses(t *testing.T) {
+	db := newTestDB(t)
+	manager := createTestUser(t, db, constants.RoleManager, nil)
+	employee := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+	colleague := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+	expense := createTestExpense(t, db, employee.ID, constants.ExpenseStatusAwaitingApproval, 250_000)
+	receiptUseCase := newTestReceiptUseCase(t, db, 1<<20, nil)
+
+	_, err := uploadTestReceipt(receiptUseCase, manager, expense.ID, "taxi.pdf", testPDF)
+	requireHTTPStatus(t, err, http.StatusForbidden)
+	_, err = uploadTestReceipt(receiptUseCase, colleague, expense.ID, "taxi.pdf", testPDF)
+	requireHTTPStatus(t, err, http.StatusForbidden)
+	_, err = uploadTestReceipt(receiptUseCase, employee, uuid.New(), "taxi.pdf", testPDF)
+	requireHTTPStatus(t, err, http.StatusNotFound)
+
+	for _, status := range []string{constants.ExpenseStatusCompleted, constants.ExpenseStatusCancelled} {
+		closed := createTestExpense(t, db, employee.ID, status, 250_000)
+		_, err = uploadTestReceipt(receiptUseCase, employee, closed.ID, "taxi.pdf", testPDF)
+		requireHTTPStatus(t, err, http.StatusConflict)
+	}
+
+	for _, status := range []string{constants.ExpenseStatusRejected, constants.ExpenseStatusApproved, constants.ExpenseStatusPaymentFailed} {
+		open := createTestExpense(t, db, employee.ID, status, 250_000)
+		_, err = uploadTestReceipt(receiptUseCase, employee, open.ID, "taxi.pdf", testPDF)
+		require.NoError(t, err, status)
+	}
+}
+
+func TestUploadReceiptFlagsDuplicateFile(t *testing.T) {
+	db := newTestDB(t)
+	employee := createTestUser(t, db, constants.RoleEmployee, nil)
+	colleague := createTestUser(t, db, constants.RoleEmployee, nil)
+	original := createTestExpense(t, db, employee.ID, constants.ExpenseStatusAwaitingApproval, 250_000)
+	second := createTestExpense(t, db, employee.ID, constants.ExpenseStatusAwaitingApproval, 90_000)
+	other := createTestExpense(t, db, colleague.ID, constants.ExpenseStatusAwaitingApproval, 90_000)
+	receiptUseCase := newTestReceiptUseCase(t, db, 1<<20, &usecase.DuplicateExpensePolicy{Window: 30 * 24 * time.Hour, MinSimilarity: 0.8})
+
+	_, err := uploadTestReceipt(receiptUseCase, employee, original.ID, "taxi.png", testPNG)
+	require.NoError(t, err)
+	_, err = uploadTestReceipt(receiptUseCase, employee, second.ID, "copy.png", testPNG)
+	require.NoError(t, err)
+	_, err = uploadTestReceipt(receiptUseCase, colleague, other.ID, "taxi.png", testPNG)
+	require.NoError(t, err)
+
+	duplicateOf := func(expense *entity.Expense) *uuid.UUID {
+		var stored entity.Expense
+		require.NoError(t, db.First(&stored, "id = ?", expense.ID).Error)
+		return stored.PossibleDuplicateOf
+	}
+	require.NotNil(t, duplicateOf(second))
+	require.Equal(t, original.ID, *duplicateOf(second))
+	require.Nil(t, duplicateOf(original))
+	// the same file from another requester is not a duplicate claim
+	require.Nil(t, duplicateOf(other))
+}
+
+func TestDownloadReceiptFollowsExpenseVisibility(t *testing.T) {
+	db := newTestDB(t)
+	manager := createTestUser(t, db, constants.RoleManager, nil)
+	employee := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+	colleague := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+	expense := createTestExpense(t, db, employee.ID, constants.ExpenseStatusAwaitingApproval, 250_000)
+	receiptUseCase := newTestReceiptUseCase(t, db, 1<<20, nil)
+	expenseUseCase := newTestExpenseUseCase(db, nil)
+	ctx := context.Background()
+
+	receipt, err := uploadTestReceipt(receiptUseCase, employee, expense.ID, "taxi.pdf", testPDF)
+	require.NoError(t, err)
+
+	// the same owner/reviewer rule as ExpenseUseCase.Get
+	for _, user := range []*entity.User{employee, manager} {
+		_, err := expenseUseCase.Get(ctx, authFor(user), expense.ID)
+		require.NoError(t, err, user.Role)
+
+		download, err := receiptUseCase.Download(ctx, authFor(user), expense.ID, receipt.ID)
+		require.NoError(t, err, user.Role)
+		require.Equal(t, "taxi.pdf", download.FileName)
+		require.Equal(t, "application/pdf", download.ContentType)
+		content, err := io.ReadAll(download.Content)
+		require.NoError(t, download.Content.Close())
+		require.NoError(t, err)
+		require.Equal(t, testPDF, content)
+	}
+
+	_, err = expenseUseCase.Get(ctx, authFor(colleague), expense.ID)
+	requireHTTPStatus(t, err, http.StatusForbidden)
+	_, err = receiptUseCase.Download(ctx, authFor(colleague), expense.ID, receipt.ID)
+	requireHTTPStatus(t, err, http.StatusForbidden)
+
+	// a receipt is only served through the expense it belongs to
+	otherExpense := createTestExpense(t, db, colleague.ID, constants.ExpenseStatusAwaitingApproval, 90_000)
+	_, err = receiptUseCase.Download(ctx, authFor(manager), otherExpense.ID, receipt.ID)
+	requireHTTPStatus(t, err, http.StatusNotFound)
+	_, err = receiptUseCase.Download(ctx, authFor(employee), expense.ID, uuid.New())
+	requireHTTPStatus(t, err, http.StatusNotFound)
+}
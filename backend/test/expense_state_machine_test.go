@@ -0,0 +1,83 @@
+package test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/usecase"
+	"go-expense-management-system/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpenseStateMachineResolve(t *testing.T) {
+	machine := usecase.NewExpenseStateMachine(nil, nil, nil)
+
+	ownerID := uuid.New()
+	owner := &model.Auth{UserID: ownerID, Role: constants.RoleEmployee}
+	manager := &model.Auth{UserID: uuid.New(), Role: constants.RoleManager}
+	finance := &model.Auth{UserID: uuid.New(), Role: constants.RoleFinance}
+	stranger := &model.Auth{UserID: uuid.New(), Role: constants.RoleEmployee}
+
+	tests := []struct {
+		name       string
+		from       string
+		event      usecase.ExpenseEvent
+		actor      *model.Auth
+		wantTo     string
+		wantStatus int
+		wantEffect usecase.ExpenseSideEffect
+	}{
+		{name: "submit", from: "", event: usecase.ExpenseEventSubmit, actor: owner, wantTo: constants.ExpenseStatusAwaitingApproval, wantEffect: usecase.ExpenseEffectNotifyApprover},
+		{name: "submit-auto-approved", from: "", event: usecase.ExpenseEventAutoApprove, actor: owner, wantTo: constants.ExpenseStatusAutoApproved, wantEffect: usecase.ExpenseEffectEnqueuePayment},
+		{name: "approve", from: constants.ExpenseStatusAwaitingApproval, event: usecase.ExpenseEventApprove, actor: finance, wantTo: constants.ExpenseStatusApproved, wantEffect: usecase.ExpenseEffectEnqueuePayment},
+		{name: "approve-by-employee", from: constants.ExpenseStatusAwaitingApproval, event: usecase.ExpenseEventApprove, actor: owner, wantStatus: http.StatusForbidden},
+		{name: "approve-completed", from: constants.ExpenseStatusCompleted, event: usecase.ExpenseEventApprove, actor: manager, wantStatus: http.StatusConflict},
+		{name: "reject", from: constants.ExpenseStatusAwaitingApproval, event: usecase.ExpenseEventReject, actor: manager, wantTo: constants.ExpenseStatusRejected},
+		{name: "reject-rejected", from: constants.ExpenseStatusRejected, event: usecase.ExpenseEventReject, actor: manager, wantStatus: http.StatusConflict},
+		{name: "edit", from: constants.ExpenseStatusAwaitingApproval, event: usecase.ExpenseEventEdit, actor: owner, wantTo: constants.ExpenseStatusAwaitingApproval, wantEffect: usecase.ExpenseEffectNotifyApprover},
+		{name: "edit-by-stranger", from: constants.ExpenseStatusAwaitingApproval, event: usecase.ExpenseEventEdit, actor: stranger, wantStatus: http.StatusForbidden},
+		{name: "edit-approved", from: constants.ExpenseStatusApproved, event: usecase.ExpenseEventEdit, actor: owner, wantStatus: http.StatusConflict},
+		{name: "cancel-pending", from: constants.ExpenseStatusAwaitingApproval, event: usecase.ExpenseEventCancel, actor: owner, wantTo: constants.ExpenseStatusCancelled},
+		{name: "cancel-rejected", from: constants.ExpenseStatusRejected, event: usecase.ExpenseEventCancel, actor: owner, wantTo: constants.ExpenseStatusCancelled},
+		{name: "cancel-by-manager", from: constants.ExpenseStatusAwaitingApproval, event: usecase.ExpenseEventCancel, actor: manager, wantStatus: http.StatusForbidden},
+		{name: "cancel-completed", from: constants.ExpenseStatusCompleted, event: usecase.ExpenseEventCancel, actor: owner, wantStatus: http.StatusConflict},
+		{name: "resubmit", from: constants.ExpenseStatusRejected, event: usecase.ExpenseEventResubmit, actor: owner, wantTo: constants.ExpenseStatusAwaitingApproval, wantEffect: usecase.ExpenseEffectNotifyApprover},
+		{name: "resubmit-cancelled", from: constants.ExpenseStatusCancelled, event: usecase.ExpenseEventResubmit, actor: owner, wantStatus: http.StatusConflict},
+		{name: "start-payment", from: constants.ExpenseStatusApproved, event: usecase.ExpenseEventStartPayment, actor: nil, wantTo: constants.ExpenseStatusPaymentProcessing},
+		{name: "start-payment-by-user", from: constants.ExpenseStatusApproved, event: usecase.ExpenseEventStartPayment, actor: manager, wantStatus: http.StatusForbidden},
+		{name: "start-payment-pending", from: constants.ExpenseStatusAwaitingApproval, event: usecase.ExpenseEventStartPayment, actor: nil, wantStatus: http.StatusConflict},
+		{name: "complete-payment", from: constants.ExpenseStatusPaymentProcessing, event: usecase.ExpenseEventCompletePayment, actor: nil, wantTo: constants.ExpenseStatusCompleted},
+		{name: "fail-payment", from: constants.ExpenseStatusPaymentProcessing, event: usecase.ExpenseEventFailPayment, actor: nil, wantTo: constants.ExpenseStatusPaymentFailed},
+		{name: "fail-completed-payment", from: constants.ExpenseStatusCompleted, event: usecase.ExpenseEventFailPayment, actor: nil, wantStatus: http.StatusConflict},
+		{name: "retry-payment", from: constants.ExpenseStatusPaymentFailed, event: usecase.ExpenseEventRetryPayment, actor: manager, wantTo: constants.ExpenseStatusApproved, wantEffect: usecase.ExpenseEffectEnqueuePayment},
+		{name: "retry-payment-by-finance", from: constants.ExpenseStatusPaymentFailed, event: usecase.ExpenseEventRetryPayment, actor: finance, wantStatus: http.StatusForbidden},
+		{name: "retry-payment-not-failed", from: constants.ExpenseStatusApproved, event: usecase.ExpenseEventRetryPayment, actor: manager, wantStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		expense := &entity.Expense{ID: uuid.New(), UserID: ownerID, Status: tt.from}
+		transition, err := machine.Resolve(expense, tt.event, tt.actor)
+
+		if tt.wantStatus != 0 {
+			var httpErr utils.HTTPError
+			require.True(t, errors.As(err, &httpErr), tt.name)
+			require.Equal(t, tt.wantStatus, httpErr.Status(), tt.name)
+			continue
+		}
+
+		require.NoError(t, err, tt.name)
+		require.Equal(t, tt.wantTo, transition.To, tt.name)
+		if tt.wantEffect != "" {
+			require.True(t, transition.HasSideEffect(tt.wantEffect), tt.name)
+		} else {
+			require.Empty(t, transition.SideEffects, tt.name)
+		}
+		require.Equal(t, tt.from, expense.Status, tt.name)
+	}
+}
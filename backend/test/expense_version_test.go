@@ -0,0 +1,96 @@
+package test
+
+import (
+	"context"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/delivery/http"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApproveHonoursIfMatch(t *testing.T) {
+	db := newTestDB(t)
+	manager := createTestUser(t, db, constants.RoleManager, nil)
+	employee := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+	category := createTestCategory(t, db, 1_000_000, nil)
+
+	expenseUseCase := newTestExpenseUseCase(db, nil)
+	created, err := expenseUseCase.Create(context.Background(), authFor(employee), &model.CreateExpenseRequest{CategoryID: category.ID, AmountIDR: 2_000_000, Description: "Client dinner"})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, created.Version)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	controller := http.NewExpenseController(expenseUseCase, newTestLogger(), validator.New())
+	router.PUT("/expenses/:id/approve", func(ctx *gin.Context) {
+		ctx.Set("auth", authFor(manager))
+	}, controller.Approve)
+
+	approve := func(ifMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(nethttp.MethodPut, "/expenses/"+created.ID.String()+"/approve", nil)
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := approve("not-a-version")
+	require.Equal(t, nethttp.StatusBadRequest, rec.Code)
+	require.Contains(t, rec.Body.String(), messages.ErrInvalidIfMatch)
+
+	rec = approve(`"2"`)
+	require.Equal(t, nethttp.StatusPreconditionFailed, rec.Code)
+	require.Contains(t, rec.Body.String(), messages.ErrExpenseVersionMismatch)
+
+	rec = approve(`W/"` + strconv.FormatInt(created.Version, 10) + `"`)
+	require.Equal(t, nethttp.StatusOK, rec.Code)
+	require.Equal(t, `"2"`, rec.Header().Get("ETag"))
+
+	var expense entity.Expense
+	require.NoError(t, db.Take(&expense, "id = ?", created.ID).Error)
+	require.Equal(t, constants.ExpenseStatusApproved, expense.Status)
+	require.EqualValues(t, 2, expense.Version)
+}
+
+func TestStaleExpenseVersionConflicts(t *testing.T) {
+	db := newTestDB(t)
+	manager := createTestUser(t, db, constants.RoleManager, nil)
+	employee := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+	category := createTestCategory(t, db, 1_000_000, nil)
+
+	expenseUseCase := newTestExpenseUseCase(db, nil)
+	ctx := context.Background()
+
+	created, err := expenseUseCase.Create(ctx, authFor(employee), &model.CreateExpenseRequest{CategoryID: category.ID, AmountIDR: 2_000_000, Description: "Client dinner"})
+	require.NoError(t, err)
+
+	stale := new(entity.Expense)
+	require.NoError(t, db.Take(stale, "id = ?", created.ID).Error)
+
+	description := "Client dinner with partners"
+	_, err = expenseUseCase.Update(ctx, authFor(employee), &model.UpdateExpenseRequest{ID: created.ID, Description: &description})
+	require.NoError(t, err)
+
+	_, err = expenseUseCase.StateMachine.Transition(db, stale, usecase.ExpenseEventCancel, authFor(employee), "")
+	requireHTTPError(t, err, nethttp.StatusConflict, messages.ErrExpenseModified)
+	require.Equal(t, constants.ExpenseStatusAwaitingApproval, stale.Status)
+
+	var current entity.Expense
+	require.NoError(t, db.Take(&current, "id = ?", created.ID).Error)
+	require.Equal(t, constants.ExpenseStatusAwaitingApproval, current.Status)
+	require.Equal(t, description, current.Description)
+	require.EqualValues(t, 2, current.Version)
+}
@@ -0,0 +1,150 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/delivery/http/middleware"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/repository"
+	"go-expense-management-system/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newTestIdempotencyUseCase(db *gorm.DB) *usecase.IdempotencyUseCase {
+	log := newTestLogger()
+	return usecase.NewIdempotencyUseCase(db, log, repository.NewIdempotencyKeyRepository(log), time.Hour, time.Minute)
+}
+
+func newTestIdempotencyRouter(auth *model.Auth, idempotencyUseCase *usecase.IdempotencyUseCase, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/expenses", func(ctx *gin.Context) {
+		ctx.Set("auth", auth)
+	}, middleware.NewIdempotency(idempotencyUseCase), handler)
+	return router
+}
+
+func postIdempotent(router *gin.Engine, ctx context.Context, key string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/expenses", strings.NewReader(body)).WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(constants.IdempotencyKeyHeader, key)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotencyReplaysFirstResponse(t *testing.T) {
+	db := newTestDB(t)
+	user := createTestUser(t, db, constants.RoleEmployee, nil)
+
+	calls := 0
+	router := newTestIdempotencyRouter(authFor(user), newTestIdempotencyUseCase(db), func(ctx *gin.Context) {
+		calls++
+		ctx.Header("ETag", `"1"`)
+		ctx.JSON(http.StatusCreated, gin.H{"call": calls})
+	})
+	ctx := context.Background()
+
+	first := postIdempotent(router, ctx, "create-1", `{"amount_idr": 250000, "description": "Taxi"}`)
+	require.Equal(t, http.StatusCreated, first.Code)
+	require.Empty(t, first.Header().Get(constants.IdempotencyReplayedHeader))
+
+	replay := postIdempotent(router, ctx, "create-1", `{"description": "Taxi", "amount_idr": 250000}`)
+	require.Equal(t, http.StatusCreated, replay.Code)
+	require.Equal(t, "true", replay.Header().Get(constants.IdempotencyReplayedHeader))
+	require.Equal(t, `"1"`, replay.Header().Get("ETag"))
+	require.JSONEq(t, first.Body.String(), replay.Body.String())
+	require.Equal(t, 1, calls)
+
+	conflict := postIdempotent(router, ctx, "create-1", `{"amount_idr": 300000, "description": "Taxi"}`)
+	require.Equal(t, http.StatusUnprocessableEntity, conflict.Code)
+	require.Contains(t, conflict.Body.String(), messages.ErrIdempotencyKeyReused)
+	require.Equal(t, 1, calls)
+
+	other := postIdempotent(router, ctx, "create-2", `{"amount_idr": 300000, "description": "Taxi"}`)
+	require.Equal(t, http.StatusCreated, other.Code)
+	require.Equal(t, 2, calls)
+}
+
+func TestIdempotencyRejectsKeyInFlight(t *testing.T) {
+	db := newTestDB(t)
+	user := createTestUser(t, db, constants.RoleEmployee, nil)
+	idempotencyUseCase := newTestIdempotencyUseCase(db)
+
+	body := `{"amount_idr": 250000}`
+	var router *gin.Engine
+	var retry *httptest.ResponseRecorder
+	router = newTestIdempotencyRouter(authFor(user), idempotencyUseCase, func(ctx *gin.Context) {
+		retry = postIdempotent(router, context.Background(), "create-1", body)
+		ctx.JSON(http.StatusCreated, gin.H{})
+	})
+
+	rec := postIdempotent(router, context.Background(), "create-1", body)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	require.Equal(t, http.StatusConflict, retry.Code)
+	require.Contains(t, retry.Body.String(), messages.ErrIdempotencyInProgress)
+}
+
+func TestIdempotencySettlesKeyAfterDisconnect(t *testing.T) {
+	db := newTestDB(t)
+	user := createTestUser(t, db, constants.RoleEmployee, nil)
+
+	status := http.StatusCreated
+	calls := 0
+	disconnect := func() {}
+	router := newTestIdempotencyRouter(authFor(user), newTestIdempotencyUseCase(db), func(ctx *gin.Context) {
+		calls++
+		disconnect()
+		ctx.JSON(status, gin.H{"call": calls})
+	})
+
+	disconnected := func(key string) *httptest.ResponseRecorder {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		disconnect = cancel
+		return postIdempotent(router, ctx, key, `{"amount_idr": 250000}`)
+	}
+
+	require.Equal(t, http.StatusCreated, disconnected("create-1").Code)
+
+	var record entity.IdempotencyKey
+	require.NoError(t, db.Where("user_id = ? AND key = ?", user.ID, "create-1").Take(&record).Error)
+	require.Equal(t, constants.IdempotencyStatusCompleted, record.Status)
+	require.Equal(t, http.StatusCreated, record.ResponseStatus)
+
+	replay := postIdempotent(router, context.Background(), "create-1", `{"amount_idr": 250000}`)
+	require.Equal(t, "true", replay.Header().Get(constants.IdempotencyReplayedHeader))
+	require.Equal(t, 1, calls)
+
+	status = http.StatusInternalServerError
+	require.Equal(t, http.StatusInternalServerError, disconnected("create-2").Code)
+
+	var count int64
+	require.NoError(t, db.Model(&entity.IdempotencyKey{}).Where("user_id = ? AND key = ?", user.ID, "create-2").Count(&count).Error)
+	require.Zero(t, count)
+}
+
+func TestIdempotencyRejectsOversizedBody(t *testing.T) {
+	db := newTestDB(t)
+	user := createTestUser(t, db, constants.RoleEmployee, nil)
+
+	router := newTestIdempotencyRouter(authFor(user), newTestIdempotencyUseCase(db), func(ctx *gin.Context) {
+		t.Fatal("handler must not run for an oversized body")
+	})
+
+	body := `{"description": "` + strings.Repeat("a", constants.IdempotencyMaxBodyBytes) + `"}`
+	rec := postIdempotent(router, context.Background(), "create-1", body)
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	require.Contains(t, rec.Body.String(), messages.ErrRequestBodyTooLarge)
+}
@@ -0,0 +1,164 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/integration/payment"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/usecase"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentClientGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		switch r.URL.Path {
+		case "/v1/payments/exp-1":
+			w.Write([]byte(`{"data":{"id":"pay_1","external_id":"exp-1","status":"completed"},"message":"ok"}`))
+		case "/v1/payments/missing":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := payment.NewClient(server.URL, time.Second, nil)
+
+	found, err := client.Get(context.Background(), "exp-1")
+	require.NoError(t, err)
+	require.Equal(t, "pay_1", found.ID)
+	require.Equal(t, "exp-1", found.ExternalID)
+	require.Equal(t, "completed", found.Status)
+
+	missing, err := client.Get(context.Background(), "missing")
+	require.NoError(t, err)
+	require.Nil(t, missing)
+
+	_, err = client.Get(context.Background(), "broken")
+	require.Error(t, err)
+}
+
+func TestPaymentClientProcessSendsDisbursementFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/payments", r.URL.Path)
+		require.Equal(t, "Bearer secret-key", r.Header.Get("Authorization"))
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, "014", body["bank_code"])
+		require.Equal(t, "1234567890", body["account_number"])
+		require.Equal(t, "John Doe", body["account_holder_name"])
+
+		w.Write([]byte(`{"data":{"id":"pay_1","external_id":"exp-1","status":"pending"}}`))
+	}))
+	defer server.Close()
+
+	client := payment.NewClient(server.URL, time.Second, nil)
+	client.APIKey = "secret-key"
+
+	response, err := client.Process(context.Background(), model.PaymentRequest{
+		Amount:            150000,
+		ExternalID:        "exp-1",
+		BankCode:          "014",
+		AccountNumber:     "1234567890",
+		AccountHolderName: "John Doe",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "pending", response.Status)
+}
+
+func TestPaymentClientProcessBulk(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/v1/payouts/bulk", r.URL.Path)
+
+		var body model.BulkPaymentRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, "batch-1", body.ExternalID)
+		require.Len(t, body.Items, 2)
+		require.Equal(t, "014", body.Items[0].BankCode)
+
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"data":{"id":"bulk_1","external_id":"batch-1","status":"processing","items":[` +
+			`{"id":"pay_1","external_id":"exp-1","status":"completed"},` +
+			`{"id":"pay_2","external_id":"exp-2","status":"failed","failure_reason":"account closed"}]}}`))
+	}))
+	defer server.Close()
+
+	client := payment.NewClient(server.URL, time.Second, nil)
+	response, err := client.ProcessBulk(context.Background(), model.BulkPaymentRequest{
+		ExternalID: "batch-1",
+		Items: []model.PaymentRequest{
+			{Amount: 100000, ExternalID: "exp-1", BankCode: "014", AccountNumber: "1234567890", AccountHolderName: "John"},
+			{Amount: 250000, ExternalID: "exp-2", BankCode: "008", AccountNumber: "9876543210", AccountHolderName: "Jane"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "bulk_1", response.ID)
+	require.Len(t, response.Items, 2)
+	require.Equal(t, "account closed", response.Items[1].FailureReason)
+
+	_, err = client.ProcessBulk(context.Background(), model.BulkPaymentRequest{ExternalID: "batch-1"})
+	require.Error(t, err)
+}
+
+func processTestPayment(t *testing.T, provider usecase.PaymentProvider) (*entity.Expense, *model.ExpenseDetailResponse) {
+	t.Helper()
+
+	db := newTestDB(t)
+	manager := createTestUser(t, db, constants.RoleManager, nil)
+	employee := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+	createTestBankAccount(t, db, employee.ID, true)
+	expense := createTestExpense(t, db, employee.ID, constants.ExpenseStatusApproved, 750_000)
+
+	expenseUseCase := newTestExpenseUseCase(db, nil)
+	expenseUseCase.PaymentProcessor = provider
+	job := model.PaymentJob{ExpenseID: expense.ID, AmountIDR: expense.AmountIDR, ExternalID: expense.ID.String()}
+	require.NoError(t, expenseUseCase.ProcessPayment(context.Background(), job))
+
+	detail, err := expenseUseCase.Get(context.Background(), authFor(manager), expense.ID)
+	require.NoError(t, err)
+	require.NotNil(t, detail.Payment)
+	return expense, detail
+}
+
+func TestFakeProviderSettlesPayments(t *testing.T) {
+	provider := payment.NewFakeProvider("")
+	expense, detail := processTestPayment(t, provider)
+	require.Equal(t, constants.ExpenseStatusCompleted, detail.Status)
+	require.Equal(t, constants.PaymentProviderStatusCompleted, detail.Payment.ProviderStatus)
+
+	// the ID is derived from the external ID, so a status lookup finds the same payment
+	fetched, err := provider.Get(context.Background(), expense.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, detail.Payment.ProviderPaymentID, fetched.ID)
+
+	_, detail = processTestPayment(t, payment.NewFakeProvider(constants.PaymentProviderStatusFailed))
+	require.Equal(t, constants.ExpenseStatusPaymentFailed, detail.Status)
+	require.Equal(t, constants.PaymentProviderStatusFailed, detail.Payment.ProviderStatus)
+}
+
+func TestManualProviderAwaitsTransfer(t *testing.T) {
+	provider := payment.NewManualProvider(nil)
+	expense, detail := processTestPayment(t, provider)
+	require.Equal(t, constants.ExpenseStatusPaymentProcessing, detail.Status)
+	require.Equal(t, constants.PaymentProviderStatusAwaitingManualTransfer, detail.Payment.ProviderStatus)
+	require.Equal(t, "manual_"+expense.ID.String(), detail.Payment.ProviderPaymentID)
+
+	bulk, err := provider.ProcessBulk(context.Background(), model.BulkPaymentRequest{
+		ExternalID: "batch-1",
+		Items:      []model.PaymentRequest{{Amount: expense.AmountIDR, ExternalID: expense.ID.String()}},
+	})
+	require.NoError(t, err)
+	require.Len(t, bulk.Items, 1)
+	require.Equal(t, constants.PaymentProviderStatusAwaitingManualTransfer, bulk.Items[0].Status)
+}
@@ -0,0 +1,90 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"go-expense-management-system/internal/background"
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/integration/payment"
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentWorkerDeadLettersExpense(t *testing.T) {
+	db := newTestDB(t)
+	queue := newTestPaymentQueue(db, time.Minute)
+	expenseUseCase := newTestExpenseUseCase(db, nil)
+
+	process := func(ctx context.Context, job model.PaymentJob) error {
+		return utils.Error(messages.ErrPaymentFailed, http.StatusBadGateway, &payment.APIError{StatusCode: http.StatusUnprocessableEntity, Body: "account closed"})
+	}
+	worker := background.NewPaymentWorker(queue, 1, 10, 3, time.Second, time.Minute, time.Second, time.Hour, newTestLogger(), process, expenseUseCase.MarkPaymentFailed)
+
+	expense := enqueueTestPaymentJob(t, db, queue)
+	worker.Start()
+	t.Cleanup(func() { _ = worker.Stop(context.Background()) })
+
+	require.Eventually(t, func() bool {
+		return findTestPaymentJob(t, db, expense).Status == constants.PaymentJobStatusFailed
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.Eventually(t, func() bool {
+		var current entity.Expense
+		require.NoError(t, db.Take(&current, "id = ?", expense.ID).Error)
+		return current.Status == constants.ExpenseStatusPaymentFailed
+	}, 5*time.Second, 10*time.Millisecond)
+
+	history := latestTestHistory(t, db, expense.ID)
+	require.Equal(t, constants.ExpenseStatusApproved, history.PreviousStatus)
+	require.Equal(t, constants.ExpenseStatusPaymentFailed, history.NewStatus)
+	require.Contains(t, history.Notes, messages.ErrPaymentFailed)
+}
+
+func TestRetryPayment(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       string
+		verified   bool
+		wantStatus int
+	}{
+		{name: "manager", role: constants.RoleManager, verified: true},
+		{name: "employee", role: constants.RoleEmployee, verified: true, wantStatus: http.StatusForbidden},
+		{name: "unverified-account", role: constants.RoleManager, verified: false, wantStatus: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			queue := newTestPaymentQueue(db, time.Minute)
+			expenseUseCase := newTestExpenseUseCase(db, nil)
+			expenseUseCase.PaymentQueue = background.NewPaymentWorker(queue, 1, 10, 3, time.Second, time.Minute, time.Second, time.Hour, nil, nil, nil)
+
+			employee := createTestUser(t, db, constants.RoleEmployee, nil)
+			createTestBankAccount(t, db, employee.ID, tt.verified)
+			expense := createTestExpense(t, db, employee.ID, constants.ExpenseStatusPaymentFailed, 250_000)
+			actor := createTestUser(t, db, tt.role, nil)
+
+			response, err := expenseUseCase.RetryPayment(context.Background(), authFor(actor), expense.ID, &model.ExpenseActionRequest{})
+			if tt.wantStatus != 0 {
+				requireHTTPStatus(t, err, tt.wantStatus)
+
+				var count int64
+				require.NoError(t, db.Model(&entity.PaymentJob{}).Count(&count).Error)
+				require.Zero(t, count)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, constants.ExpenseStatusApproved, response.Status)
+			require.Equal(t, constants.PaymentJobStatusPending, findTestPaymentJob(t, db, expense).Status)
+			require.Equal(t, "Payment retry requested", latestTestHistory(t, db, expense.ID).Notes)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-expense-management-system/internal/background"
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/repository"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newTestPaymentQueue(db *gorm.DB, lease time.Duration) *background.PaymentJobQueue {
+	log := newTestLogger()
+	return background.NewPaymentJobQueue(db, log, repository.NewPaymentJobRepository(log), lease)
+}
+
+func enqueueTestPaymentJob(t *testing.T, db *gorm.DB, queue *background.PaymentJobQueue) *entity.Expense {
+	t.Helper()
+
+	employee := createTestUser(t, db, constants.RoleEmployee, nil)
+	expense := createTestExpense(t, db, employee.ID, constants.ExpenseStatusApproved, 250_000)
+	require.NoError(t, queue.Enqueue(db, model.PaymentJob{ExpenseID: expense.ID, AmountIDR: expense.AmountIDR, ExternalID: expense.ID.String()}))
+	return expense
+}
+
+func findTestPaymentJob(t *testing.T, db *gorm.DB, expense *entity.Expense) entity.PaymentJob {
+	t.Helper()
+
+	var job entity.PaymentJob
+	require.NoError(t, db.Where("expense_id = ?", expense.ID).Take(&job).Error)
+	return job
+}
+
+func TestPaymentJobQueueClaimAndComplete(t *testing.T) {
+	db := newTestDB(t)
+	queue := newTestPaymentQueue(db, time.Minute)
+	ctx := context.Background()
+
+	expense := enqueueTestPaymentJob(t, db, queue)
+	require.NoError(t, queue.Enqueue(db, model.PaymentJob{ExpenseID: expense.ID, AmountIDR: expense.AmountIDR, ExternalID: expense.ID.String()}))
+
+	var count int64
+	require.NoError(t, db.Model(&entity.PaymentJob{}).Count(&count).Error)
+	require.EqualValues(t, 1, count)
+
+	jobs, err := queue.Claim(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, 1, jobs[0].Attempts)
+	require.Equal(t, constants.PaymentJobStatusProcessing, jobs[0].Status)
+
+	again, err := queue.Claim(ctx, 10)
+	require.NoError(t, err)
+	require.Empty(t, again)
+
+	require.NoError(t, queue.Complete(ctx, &jobs[0]))
+	require.Equal(t, constants.PaymentJobStatusCompleted, findTestPaymentJob(t, db, expense).Status)
+}
+
+func TestPaymentJobQueueFencesExpiredLease(t *testing.T) {
+	db := newTestDB(t)
+	queue := newTestPaymentQueue(db, time.Minute)
+	ctx := context.Background()
+
+	expense := enqueueTestPaymentJob(t, db, queue)
+
+	stale, err := queue.Claim(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, stale, 1)
+
+	require.NoError(t, db.Model(&entity.PaymentJob{}).Where("id = ?", stale[0].ID).Update("locked_until", time.Now().Add(-time.Second)).Error)
+
+	current, err := queue.Claim(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, current, 1)
+	require.Equal(t, 2, current[0].Attempts)
+
+	require.ErrorIs(t, queue.Complete(ctx, &stale[0]), repository.ErrLeaseLost)
+	require.ErrorIs(t, queue.Fail(ctx, &stale[0], nil), repository.ErrLeaseLost)
+	require.Equal(t, constants.PaymentJobStatusProcessing, findTestPaymentJob(t, db, expense).Status)
+
+	require.NoError(t, queue.Retry(ctx, &current[0], time.Now(), nil))
+	require.Equal(t, constants.PaymentJobStatusPending, findTestPaymentJob(t, db, expense).Status)
+}
+
+func TestPaymentWorkerProcessesQueuedJob(t *testing.T) {
+	db := newTestDB(t)
+	queue := newTestPaymentQueue(db, time.Minute)
+
+	var processed atomic.Int32
+	process := func(ctx context.Context, job model.PaymentJob) error {
+		processed.Add(1)
+		return nil
+	}
+	worker := background.NewPaymentWorker(queue, 2, 10, 3, time.Second, time.Minute, time.Second, time.Hour, newTestLogger(), process, nil)
+
+	expense := enqueueTestPaymentJob(t, db, queue)
+	worker.Start()
+	t.Cleanup(func() { _ = worker.Stop(context.Background()) })
+
+	require.Eventually(t, func() bool {
+		return findTestPaymentJob(t, db, expense).Status == constants.PaymentJobStatusCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+	require.EqualValues(t, 1, processed.Load())
+}
+
+func TestExpenseApprovalEnqueuesPaymentJob(t *testing.T) {
+	db := newTestDB(t)
+	manager := createTestUser(t, db, constants.RoleManager, nil)
+	employee := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+	category := createTestCategory(t, db, 1_000_000, nil)
+
+	expenseUseCase := newTestExpenseUseCase(db, nil)
+	expenseUseCase.PaymentQueue = background.NewPaymentWorker(newTestPaymentQueue(db, time.Minute), 1, 10, 3, time.Second, time.Minute, time.Second, time.Hour, nil, nil, nil)
+	ctx := context.Background()
+
+	created, err := expenseUseCase.Create(ctx, authFor(employee), &model.CreateExpenseRequest{CategoryID: category.ID, AmountIDR: 2_000_000, Description: "Client dinner"})
+	require.NoError(t, err)
+
+	var count int64
+	require.NoError(t, db.Model(&entity.PaymentJob{}).Count(&count).Error)
+	require.Zero(t, count)
+
+	_, err = expenseUseCase.Approve(ctx, authFor(manager), created.ID, &model.ApproveExpenseRequest{})
+	require.NoError(t, err)
+
+	job := findTestPaymentJob(t, db, &entity.Expense{ID: created.ID})
+	require.Equal(t, constants.PaymentJobStatusPending, job.Status)
+	require.EqualValues(t, 2_000_000, job.AmountIDR)
+}
@@ -0,0 +1,94 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/repository"
+	"go-expense-management-system/internal/usecase"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type paymentStatusCheckerFunc func(ctx context.Context, externalID string) (*model.PaymentResponse, error)
+
+func (f paymentStatusCheckerFunc) Get(ctx context.Context, externalID string) (*model.PaymentResponse, error) {
+	return f(ctx, externalID)
+}
+
+func createTestStalePayment(t *testing.T, db *gorm.DB, expense *entity.Expense) {
+	t.Helper()
+
+	requestedAt := time.Now().Add(-time.Hour)
+	require.NoError(t, db.Create(&entity.Payment{
+		ExpenseID:   expense.ID,
+		ExternalID:  expense.ID.String(),
+		AmountIDR:   expense.AmountIDR,
+		Attempts:    1,
+		RequestedAt: &requestedAt,
+	}).Error)
+}
+
+func TestReconcileOnlySettlesConclusivePayments(t *testing.T) {
+	db := newTestDB(t)
+	employee := createTestUser(t, db, constants.RoleEmployee, nil)
+
+	unknown := createTestExpense(t, db, employee.ID, constants.ExpenseStatusPaymentProcessing, 500_000)
+	createTestStalePayment(t, db, unknown)
+
+	queued := createTestExpense(t, db, employee.ID, constants.ExpenseStatusPaymentProcessing, 600_000)
+	createTestStalePayment(t, db, queued)
+	require.NoError(t, db.Create(&entity.PaymentJob{
+		ExpenseID:  queued.ID,
+		AmountIDR:  queued.AmountIDR,
+		ExternalID: queued.ID.String(),
+		Status:     constants.PaymentJobStatusPending,
+		Attempts:   1,
+		NextRunAt:  time.Now().Add(time.Minute),
+	}).Error)
+
+	settled := createTestExpense(t, db, employee.ID, constants.ExpenseStatusPaymentProcessing, 700_000)
+	createTestStalePayment(t, db, settled)
+
+	checked := make([]string, 0)
+	checker := paymentStatusCheckerFunc(func(_ context.Context, externalID string) (*model.PaymentResponse, error) {
+		checked = append(checked, externalID)
+		if externalID == settled.ID.String() {
+			return &model.PaymentResponse{ID: "pay_1", ExternalID: externalID, Status: constants.PaymentProviderStatusCompleted}, nil
+		}
+		return nil, nil
+	})
+
+	log := newTestLogger()
+	reconcileUseCase := usecase.NewPaymentReconcileUseCase(
+		db,
+		log,
+		repository.NewExpenseRepository(log),
+		repository.NewExpenseStatusHistoryRepository(log),
+		repository.NewPaymentRepository(log),
+		checker,
+		time.Minute,
+		10,
+	)
+
+	result, err := reconcileUseCase.Reconcile(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, model.PaymentReconcileResult{Checked: 2, Completed: 1, Pending: 1}, result)
+	require.ElementsMatch(t, []string{unknown.ID.String(), settled.ID.String()}, checked)
+
+	statuses := map[*entity.Expense]string{
+		unknown: constants.ExpenseStatusPaymentProcessing,
+		queued:  constants.ExpenseStatusPaymentProcessing,
+		settled: constants.ExpenseStatusCompleted,
+	}
+	for expense, want := range statuses {
+		var stored entity.Expense
+		require.NoError(t, db.First(&stored, "id = ?", expense.ID).Error)
+		require.Equal(t, want, stored.Status, expense.ID)
+	}
+}
@@ -0,0 +1,63 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+type paymentProcessorFunc func(ctx context.Context, request model.PaymentRequest) (*model.PaymentResponse, error)
+
+func (f paymentProcessorFunc) Process(ctx context.Context, request model.PaymentRequest) (*model.PaymentResponse, error) {
+	return f(ctx, request)
+}
+
+func TestProcessPaymentRecordsProviderCalls(t *testing.T) {
+	db := newTestDB(t)
+	manager := createTestUser(t, db, constants.RoleManager, nil)
+	employee := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+	createTestBankAccount(t, db, employee.ID, true)
+	expense := createTestExpense(t, db, employee.ID, constants.ExpenseStatusApproved, 750_000)
+
+	providerErr := errors.New("provider unavailable")
+	expenseUseCase := newTestExpenseUseCase(db, nil)
+	expenseUseCase.PaymentProcessor = paymentProcessorFunc(func(_ context.Context, request model.PaymentRequest) (*model.PaymentResponse, error) {
+		if providerErr != nil {
+			return nil, providerErr
+		}
+		return &model.PaymentResponse{ID: "pay_123", ExternalID: request.ExternalID, Status: constants.PaymentProviderStatusCompleted}, nil
+	})
+	ctx := context.Background()
+	job := model.PaymentJob{ExpenseID: expense.ID, AmountIDR: expense.AmountIDR, ExternalID: expense.ID.String()}
+
+	require.Error(t, expenseUseCase.ProcessPayment(ctx, job))
+
+	detail, err := expenseUseCase.Get(ctx, authFor(manager), expense.ID)
+	require.NoError(t, err)
+	require.NotNil(t, detail.Payment)
+	require.Equal(t, 1, detail.Payment.Attempts)
+	require.EqualValues(t, 750_000, detail.Payment.AmountIDR)
+	require.Equal(t, expense.ID.String(), detail.Payment.ExternalID)
+	require.Contains(t, detail.Payment.LastError, "provider unavailable")
+	require.Empty(t, detail.Payment.ProviderPaymentID)
+	require.Equal(t, constants.ExpenseStatusPaymentProcessing, detail.Status)
+
+	providerErr = nil
+	require.NoError(t, expenseUseCase.ProcessPayment(ctx, job))
+
+	detail, err = expenseUseCase.Get(ctx, authFor(employee), expense.ID)
+	require.NoError(t, err)
+	require.Equal(t, constants.ExpenseStatusCompleted, detail.Status)
+	require.NotNil(t, detail.Payment)
+	require.Equal(t, 2, detail.Payment.Attempts)
+	require.Equal(t, "pay_123", detail.Payment.ProviderPaymentID)
+	require.Equal(t, constants.PaymentProviderStatusCompleted, detail.Payment.ProviderStatus)
+	require.Empty(t, detail.Payment.LastError)
+	require.NotNil(t, detail.Payment.RequestedAt)
+	require.NotNil(t, detail.Payment.RespondedAt)
+}
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/repository"
+	"go-expense-management-system/internal/usecase"
+	"go-expense-management-system/internal/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	payload := []byte(`{"id":"evt_1","data":{"external_id":"abc","status":"completed"}}`)
+	signature := utils.SignWebhookPayload("secret", "1700000000", payload)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		payload   []byte
+		signature string
+		want      bool
+	}{
+		{name: "valid", secret: "secret", timestamp: "1700000000", payload: payload, signature: signature, want: true},
+		{name: "valid with prefix", secret: "secret", timestamp: "1700000000", payload: payload, signature: "sha256=" + signature, want: true},
+		{name: "wrong secret", secret: "other", timestamp: "1700000000", payload: payload, signature: signature, want: false},
+		{name: "tampered timestamp", secret: "secret", timestamp: "1700000001", payload: payload, signature: signature, want: false},
+		{name: "tampered payload", secret: "secret", timestamp: "1700000000", payload: []byte(`{"id":"evt_2"}`), signature: signature, want: false},
+		{name: "not hex", secret: "secret", timestamp: "1700000000", payload: payload, signature: "zz", want: false},
+		{name: "missing secret", secret: "", timestamp: "1700000000", payload: payload, signature: signature, want: false},
+	}
+
+	for _, tt := range tests {
+		got := utils.VerifyWebhookSignature(tt.secret, tt.timestamp, tt.payload, tt.signature)
+		require.Equal(t, tt.want, got, tt.name)
+	}
+}
+
+func TestPaymentOutcome(t *testing.T) {
+	tests := []struct {
+		status string
+		want   string
+	}{
+		{status: "pending", want: constants.PaymentProviderStatusPending},
+		{status: "PROCESSING", want: constants.PaymentProviderStatusPending},
+		{status: "completed", want: constants.PaymentProviderStatusCompleted},
+		{status: "succeeded", want: constants.PaymentProviderStatusCompleted},
+		{status: "failed", want: constants.PaymentProviderStatusFailed},
+		{status: "rejected", want: constants.PaymentProviderStatusFailed},
+		{status: "", want: ""},
+		{status: "unknown", want: ""},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.want, usecase.PaymentOutcome(tt.status), tt.status)
+	}
+}
+
+func TestWebhookReplayIsAcknowledgedWithoutSideEffects(t *testing.T) {
+	db := newTestDB(t)
+	employee := createTestUser(t, db, constants.RoleEmployee, nil)
+	expense := createTestExpense(t, db, employee.ID, constants.ExpenseStatusPaymentProcessing, 750_000)
+	require.NoError(t, db.Create(&entity.Payment{ExpenseID: expense.ID, ExternalID: expense.ID.String(), AmountIDR: expense.AmountIDR}).Error)
+
+	log := newTestLogger()
+	webhookUseCase := usecase.NewPaymentWebhookUseCase(
+		db,
+		log,
+		repository.NewExpenseRepository(log),
+		repository.NewExpenseStatusHistoryRepository(log),
+		repository.NewPaymentRepository(log),
+		repository.NewPaymentWebhookEventRepository(log),
+		"secret",
+		time.Minute,
+	)
+	send := func(status string) (*model.PaymentWebhookResponse, error) {
+		payload := []byte(`{"id":"evt_1","data":{"id":"pay_1","external_id":"` + expense.ID.String() + `","status":"` + status + `"}}`)
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		return webhookUseCase.Handle(context.Background(), &model.PaymentWebhookRequest{
+			Timestamp: timestamp,
+			Signature: utils.SignWebhookPayload("secret", timestamp, payload),
+			Payload:   payload,
+		})
+	}
+
+	response, err := send("completed")
+	require.NoError(t, err)
+	require.False(t, response.Replayed)
+	require.Equal(t, constants.ExpenseStatusCompleted, response.Status)
+
+	var histories int64
+	require.NoError(t, db.Model(&entity.ExpenseStatusHistory{}).Where("expense_id = ?", expense.ID).Count(&histories).Error)
+
+	// the same event ID with a different outcome must not be applied
+	response, err = send("failed")
+	require.NoError(t, err)
+	require.True(t, response.Replayed)
+	require.Equal(t, constants.ExpenseStatusCompleted, response.Status)
+
+	var stored entity.Expense
+	require.NoError(t, db.First(&stored, "id = ?", expense.ID).Error)
+	require.Equal(t, constants.ExpenseStatusCompleted, stored.Status)
+
+	var payment entity.Payment
+	require.NoError(t, db.First(&payment, "expense_id = ?", expense.ID).Error)
+	require.Equal(t, "completed", payment.ProviderStatus)
+	require.Empty(t, payment.LastError)
+
+	var after int64
+	require.NoError(t, db.Model(&entity.ExpenseStatusHistory{}).Where("expense_id = ?", expense.ID).Count(&after).Error)
+	require.Equal(t, histories, after)
+}
@@ -0,0 +1,155 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-expense-management-system/internal/background"
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/integration/payment"
+	"go-expense-management-system/internal/messages"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	lowest := func(n int64) int64 { return 0 }
+	highest := func(n int64) int64 { return n - 1 }
+
+	tests := []struct {
+		name    string
+		attempt int
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{name: "first", attempt: 1, wantMin: time.Second, wantMax: 2 * time.Second},
+		{name: "second", attempt: 2, wantMin: 2 * time.Second, wantMax: 4 * time.Second},
+		{name: "fourth", attempt: 4, wantMin: 8 * time.Second, wantMax: 16 * time.Second},
+		{name: "capped", attempt: 30, wantMin: 30 * time.Second, wantMax: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.wantMin, background.RetryBackoff(2*time.Second, time.Minute, tt.attempt, lowest))
+			require.Equal(t, tt.wantMax, background.RetryBackoff(2*time.Second, time.Minute, tt.attempt, highest))
+		})
+	}
+}
+
+func TestIsRetryablePaymentError(t *testing.T) {
+	wrap := func(err error) error {
+		return utils.Error(messages.ErrPaymentFailed, http.StatusBadGateway, err)
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "network", err: wrap(&payment.APIError{Err: errors.New("connection refused")}), want: true},
+		{name: "server-error", err: wrap(&payment.APIError{StatusCode: http.StatusServiceUnavailable}), want: true},
+		{name: "rate-limited", err: wrap(&payment.APIError{StatusCode: http.StatusTooManyRequests}), want: true},
+		{name: "bad-request", err: wrap(&payment.APIError{StatusCode: http.StatusBadRequest}), want: false},
+		{name: "unprocessable", err: wrap(&payment.APIError{StatusCode: http.StatusUnprocessableEntity}), want: false},
+		{name: "provider-conflict", err: wrap(&payment.APIError{StatusCode: http.StatusConflict}), want: false},
+		{name: "deadline", err: wrap(context.DeadlineExceeded), want: true},
+		{name: "expense-missing", err: utils.Error(messages.ErrExpenseNotFound, http.StatusNotFound, nil), want: false},
+		{name: "expense-modified", err: utils.Error(messages.ErrExpenseModified, http.StatusConflict, nil), want: true},
+		{name: "plain", err: fmt.Errorf("unexpected"), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, background.IsRetryablePaymentError(tt.err))
+		})
+	}
+}
+
+func TestPaymentWorkerStopWithoutJobs(t *testing.T) {
+	worker := background.NewPaymentWorker(nil, 2, 10, 3, time.Second, time.Minute, time.Second, time.Second, nil, nil, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	require.NoError(t, worker.Stop(ctx))
+	require.NoError(t, worker.Stop(ctx))
+}
+
+func TestPaymentWorkerStopCancelsInFlightJob(t *testing.T) {
+	db := newTestDB(t)
+	queue := newTestPaymentQueue(db, time.Minute)
+	expense := enqueueTestPaymentJob(t, db, queue)
+
+	started := make(chan struct{})
+	var returned atomic.Bool
+	failed := false
+	worker := background.NewPaymentWorker(queue, 1, 10, 3, time.Second, time.Minute, time.Minute, time.Hour, newTestLogger(),
+		func(ctx context.Context, _ model.PaymentJob) error {
+			close(started)
+			<-ctx.Done()
+			// still busy for a moment after the cancellation, Stop has to wait for it
+			time.Sleep(50 * time.Millisecond)
+			returned.Store(true)
+			return ctx.Err()
+		},
+		func(context.Context, model.PaymentJob, string) error {
+			failed = true
+			return nil
+		},
+	)
+	worker.Start()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("payment job was not picked up")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, worker.Stop(ctx), context.DeadlineExceeded)
+	require.True(t, returned.Load())
+	require.False(t, failed)
+
+	job := findTestPaymentJob(t, db, expense)
+	require.Equal(t, constants.PaymentJobStatusPending, job.Status)
+	require.Zero(t, job.Attempts)
+	require.Nil(t, job.LockedUntil)
+}
+
+func TestWorkersStopCancelsRunningPasses(t *testing.T) {
+	started := make(chan struct{})
+	var returned atomic.Bool
+	reconciler := background.NewPaymentReconciler(time.Millisecond, newTestLogger(), func(ctx context.Context) (model.PaymentReconcileResult, error) {
+		if !returned.Load() {
+			close(started)
+		}
+		<-ctx.Done()
+		returned.Store(true)
+		return model.PaymentReconcileResult{}, ctx.Err()
+	})
+	escalator := background.NewApprovalEscalator(time.Hour, 10, newTestLogger(), func(context.Context, int) (int, error) {
+		return 0, nil
+	})
+
+	workers := &background.Workers{PaymentReconciler: reconciler, ApprovalEscalator: escalator}
+	workers.Start()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("reconciliation pass did not start")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, workers.Stop(ctx), context.DeadlineExceeded)
+	require.True(t, returned.Load())
+}
@@ -0,0 +1,255 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"go-expense-management-system/internal/background"
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/integration/payment"
+	"go-expense-management-system/internal/model"
+	"go-expense-management-system/internal/repository"
+	"go-expense-management-system/internal/usecase"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type bulkPaymentProcessorFunc func(ctx context.Context, request model.BulkPaymentRequest) (*model.BulkPaymentResponse, error)
+
+func (f bulkPaymentProcessorFunc) ProcessBulk(ctx context.Context, request model.BulkPaymentRequest) (*model.BulkPaymentResponse, error) {
+	return f(ctx, request)
+}
+
+func newTestPayoutUseCase(db *gorm.DB, provider usecase.PaymentBulkProcessor) *usecase.PayoutUseCase {
+	log := newTestLogger()
+	return usecase.NewPayoutUseCase(
+		db,
+		log,
+		repository.NewExpenseRepository(log),
+		repository.NewExpenseStatusHistoryRepository(log),
+		repository.NewPaymentRepository(log),
+		repository.NewUserBankAccountRepository(log),
+		repository.NewPayoutBatchRepository(log),
+		provider,
+		true,
+		10,
+		time.Minute,
+	)
+}
+
+func createTestPayee(t *testing.T, db *gorm.DB, managerID uuid.UUID, amount int64) *entity.Expense {
+	t.Helper()
+
+	employee := createTestUser(t, db, constants.RoleEmployee, &managerID)
+	createTestBankAccount(t, db, employee.ID, true)
+	return createTestExpense(t, db, employee.ID, constants.ExpenseStatusApproved, amount)
+}
+
+func requireTestExpenseStatus(t *testing.T, db *gorm.DB, expense *entity.Expense, status string) {
+	t.Helper()
+
+	var stored entity.Expense
+	require.NoError(t, db.First(&stored, "id = ?", expense.ID).Error)
+	require.Equal(t, status, stored.Status)
+}
+
+func TestApprovePayoutBatchSettlesItems(t *testing.T) {
+	db := newTestDB(t)
+	manager := createTestUser(t, db, constants.RoleManager, nil)
+	paid := createTestPayee(t, db, manager.ID, 300_000)
+	rejected := createTestPayee(t, db, manager.ID, 450_000)
+
+	unverified := createTestUser(t, db, constants.RoleEmployee, &manager.ID)
+	createTestBankAccount(t, db, unverified.ID, false)
+	waiting := createTestExpense(t, db, unverified.ID, constants.ExpenseStatusApproved, 200_000)
+
+	payoutUseCase := newTestPayoutUseCase(db, bulkPaymentProcessorFunc(func(_ context.Context, request model.BulkPaymentRequest) (*model.BulkPaymentResponse, error) {
+		response := &model.BulkPaymentResponse{ID: "bulk_1", ExternalID: request.ExternalID, Status: constants.PaymentProviderStatusProcessing}
+		for _, item := range request.Items {
+			status := constants.PaymentProviderStatusCompleted
+			if item.ExternalID == rejected.ID.String() {
+				status = constants.PaymentProviderStatusFailed
+			}
+			response.Items = append(response.Items, model.PaymentResponse{ID: "pay_" + item.ExternalID, ExternalID: item.ExternalID, Status: status})
+		}
+		return response, nil
+	}))
+	ctx := context.Background()
+
+	draft, err := payoutUseCase.Create(ctx, authFor(manager))
+	require.NoError(t, err)
+	require.Equal(t, 2, draft.ItemCount)
+	require.EqualValues(t, 750_000, draft.TotalAmountIDR)
+
+	batch, err := payoutUseCase.Approve(ctx, authFor(manager), draft.ID)
+	require.NoError(t, err)
+	require.Equal(t, constants.PayoutBatchStatusPartiallyFailed, batch.Status)
+	require.NotNil(t, batch.SubmittedAt)
+	require.Equal(t, "bulk_1", batch.ProviderBatchID)
+
+	requireTestExpenseStatus(t, db, paid, constants.ExpenseStatusCompleted)
+	requireTestExpenseStatus(t, db, rejected, constants.ExpenseStatusPaymentFailed)
+	requireTestExpenseStatus(t, db, waiting, constants.ExpenseStatusApproved)
+}
+
+func TestPayoutBatchSurvivesUnacknowledgedBulkCall(t *testing.T) {
+	db := newTestDB(t)
+	manager := createTestUser(t, db, constants.RoleManager, nil)
+	expense := createTestPayee(t, db, manager.ID, 300_000)
+
+	submitErr := error(&payment.APIError{Err: context.DeadlineExceeded})
+	calls := 0
+	payoutUseCase := newTestPayoutUseCase(db, bulkPaymentProcessorFunc(func(_ context.Context, request model.BulkPaymentRequest) (*model.BulkPaymentResponse, error) {
+		calls++
+		if submitErr != nil {
+			return nil, submitErr
+		}
+		return &model.BulkPaymentResponse{ID: "bulk_1", ExternalID: request.ExternalID, Items: []model.PaymentResponse{
+			{ID: "pay_1", ExternalID: request.Items[0].ExternalID, Status: constants.PaymentProviderStatusCompleted},
+		}}, nil
+	}))
+	ctx := context.Background()
+
+	draft, err := payoutUseCase.Create(ctx, authFor(manager))
+	require.NoError(t, err)
+
+	// a timeout is not a rejection: the provider may still pay the items
+	batch, err := payoutUseCase.Approve(ctx, authFor(manager), draft.ID)
+	require.NoError(t, err)
+	require.Equal(t, constants.PayoutBatchStatusProcessing, batch.Status)
+	require.Nil(t, batch.SubmittedAt)
+	require.Equal(t, constants.PayoutItemStatusPending, batch.Items[0].Status)
+	requireTestExpenseStatus(t, db, expense, constants.ExpenseStatusPaymentProcessing)
+
+	// the approval is still within the bulk call timeout, so sync leaves it alone
+	_, err = payoutUseCase.Sync(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+
+	submitErr = nil
+	require.NoError(t, db.Model(&entity.PayoutBatch{}).Where("id = ?", draft.ID).Update("approved_at", time.Now().Add(-time.Hour)).Error)
+	finished, err := payoutUseCase.Sync(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, finished)
+	require.Equal(t, 2, calls)
+	requireTestExpenseStatus(t, db, expense, constants.ExpenseStatusCompleted)
+
+	// once acknowledged the batch is never sent again
+	_, err = payoutUseCase.Sync(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+}
+
+func TestPayoutBatchFailsItemsOnRejection(t *testing.T) {
+	db := newTestDB(t)
+	manager := createTestUser(t, db, constants.RoleManager, nil)
+	expense := createTestPayee(t, db, manager.ID, 300_000)
+
+	payoutUseCase := newTestPayoutUseCase(db, bulkPaymentProcessorFunc(func(context.Context, model.BulkPaymentRequest) (*model.BulkPaymentResponse, error) {
+		return nil, &payment.APIError{StatusCode: http.StatusUnprocessableEntity, Status: "422 Unprocessable Entity", Err: errors.New("invalid account")}
+	}))
+	ctx := context.Background()
+
+	draft, err := payoutUseCase.Create(ctx, authFor(manager))
+	require.NoError(t, err)
+
+	batch, err := payoutUseCase.Approve(ctx, authFor(manager), draft.ID)
+	require.NoError(t, err)
+	require.Equal(t, constants.PayoutBatchStatusFailed, batch.Status)
+	require.Equal(t, constants.PayoutItemStatusFailed, batch.Items[0].Status)
+	requireTestExpenseStatus(t, db, expense, constants.ExpenseStatusPaymentFailed)
+}
+
+func TestPayoutBatchSkipsUnverifiedAccount(t *testing.T) {
+	db := newTestDB(t)
+	manager := createTestUser(t, db, constants.RoleManager, nil)
+	paid := createTestPayee(t, db, manager.ID, 300_000)
+	held := createTestPayee(t, db, manager.ID, 400_000)
+
+	var sent []model.PaymentRequest
+	payoutUseCase := newTestPayoutUseCase(db, bulkPaymentProcessorFunc(func(_ context.Context, request model.BulkPaymentRequest) (*model.BulkPaymentResponse, error) {
+		sent = request.Items
+		response := &model.BulkPaymentResponse{ID: "bulk_1"}
+		for _, item := range request.Items {
+			response.Items = append(response.Items, model.PaymentResponse{ID: "pay_" + item.ExternalID, ExternalID: item.ExternalID, Status: constants.PaymentProviderStatusCompleted})
+		}
+		return response, nil
+	}))
+	ctx := context.Background()
+
+	draft, err := payoutUseCase.Create(ctx, authFor(manager))
+	require.NoError(t, err)
+	require.Len(t, draft.Items, 2)
+
+	// the requester changes the account after the draft was built
+	require.NoError(t, db.Model(&entity.UserBankAccount{}).Where("user_id = ?", held.UserID).Update("is_verified", false).Error)
+
+	batch, err := payoutUseCase.Approve(ctx, authFor(manager), draft.ID)
+	require.NoError(t, err)
+	require.Len(t, sent, 1)
+	require.Equal(t, paid.ID.String(), sent[0].ExternalID)
+	for _, item := range batch.Items {
+		if item.ExpenseID == held.ID {
+			require.Equal(t, constants.PayoutItemStatusSkipped, item.Status)
+		}
+	}
+
+	requireTestExpenseStatus(t, db, held, constants.ExpenseStatusApproved)
+	history := latestTestHistory(t, db, held.ID)
+	require.Equal(t, constants.ExpenseStatusApproved, history.NewStatus)
+	require.Contains(t, history.Notes, "verified bank account")
+}
+
+func TestPayoutBatchStatus(t *testing.T) {
+	item := func(status string) entity.PayoutBatchItem {
+		return entity.PayoutBatchItem{Status: status}
+	}
+
+	tests := []struct {
+		name  string
+		items []entity.PayoutBatchItem
+		want  string
+	}{
+		{name: "pending", items: []entity.PayoutBatchItem{item(constants.PayoutItemStatusCompleted), item(constants.PayoutItemStatusPending)}, want: constants.PayoutBatchStatusProcessing},
+		{name: "completed", items: []entity.PayoutBatchItem{item(constants.PayoutItemStatusCompleted), item(constants.PayoutItemStatusSkipped)}, want: constants.PayoutBatchStatusCompleted},
+		{name: "partially-failed", items: []entity.PayoutBatchItem{item(constants.PayoutItemStatusCompleted), item(constants.PayoutItemStatusFailed)}, want: constants.PayoutBatchStatusPartiallyFailed},
+		{name: "failed", items: []entity.PayoutBatchItem{item(constants.PayoutItemStatusFailed), item(constants.PayoutItemStatusSkipped)}, want: constants.PayoutBatchStatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, usecase.PayoutBatchStatus(tt.items))
+		})
+	}
+}
+
+func TestNextDailyRun(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	hour, minute, err := background.ParseDailyTime("15:00")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{name: "before-run", now: time.Date(2024, time.August, 17, 9, 0, 0, 0, jakarta), want: time.Date(2024, time.August, 17, 15, 0, 0, 0, jakarta)},
+		{name: "at-run", now: time.Date(2024, time.August, 17, 15, 0, 0, 0, jakarta), want: time.Date(2024, time.August, 18, 15, 0, 0, 0, jakarta)},
+		{name: "month-end", now: time.Date(2024, time.August, 31, 16, 0, 0, 0, jakarta), want: time.Date(2024, time.September, 1, 15, 0, 0, 0, jakarta)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, background.NextDailyRun(tt.now, hour, minute))
+		})
+	}
+
+	_, _, err = background.ParseDailyTime("3pm")
+	require.Error(t, err)
+}
@@ -0,0 +1,67 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"go-expense-management-system/internal/constants"
+	"go-expense-management-system/internal/entity"
+	"go-expense-management-system/internal/repository"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportRepositoryTotalsAndGroups(t *testing.T) {
+	db := newTestDB(t)
+	alice := createTestUser(t, db, constants.RoleEmployee, nil)
+	bob := createTestUser(t, db, constants.RoleEmployee, nil)
+	category := createTestCategory(t, db, 1_000_000, nil)
+
+	base := time.Date(2024, time.August, 1, 9, 0, 0, 0, time.UTC)
+	seed := func(user *entity.User, category *entity.ExpenseCategory, amount int64, status string, day int) {
+		expense := &entity.Expense{UserID: user.ID, AmountIDR: amount, Description: "Expense", Status: status, Version: 1, SubmittedAt: base.AddDate(0, 0, day)}
+		if category != nil {
+			expense.CategoryID = &category.ID
+		}
+		require.NoError(t, db.Create(expense).Error)
+	}
+	seed(alice, category, 500_000, constants.ExpenseStatusCompleted, 0)
+	seed(alice, category, 1_500_000, constants.ExpenseStatusAwaitingApproval, 1)
+	seed(bob, nil, 250_000, constants.ExpenseStatusCompleted, 2)
+	seed(bob, category, 9_000_000, constants.ExpenseStatusRejected, 40)
+
+	// ByMonth and the turnaround averages rely on Postgres date functions
+	// that the SQLite test database does not have.
+	reports := repository.NewReportRepository(newTestLogger())
+	to := base.AddDate(0, 1, 0)
+	filter := repository.ReportFilter{From: &base, To: &to}
+
+	totals, err := reports.Totals(db, filter)
+	require.NoError(t, err)
+	require.Equal(t, repository.ReportTotals{Count: 3, TotalIDR: 2_250_000}, totals)
+
+	byStatus, err := reports.ByStatus(db, filter)
+	require.NoError(t, err)
+	require.Equal(t, []repository.ReportGroup{
+		{Key: constants.ExpenseStatusAwaitingApproval, Label: constants.ExpenseStatusAwaitingApproval, Count: 1, TotalIDR: 1_500_000},
+		{Key: constants.ExpenseStatusCompleted, Label: constants.ExpenseStatusCompleted, Count: 2, TotalIDR: 750_000},
+	}, byStatus)
+
+	byRequester, err := reports.ByRequester(db, filter)
+	require.NoError(t, err)
+	require.Equal(t, []repository.ReportGroup{
+		{Key: alice.ID.String(), Label: alice.Name, Count: 2, TotalIDR: 2_000_000},
+		{Key: bob.ID.String(), Label: bob.Name, Count: 1, TotalIDR: 250_000},
+	}, byRequester)
+
+	byCategory, err := reports.ByCategory(db, filter)
+	require.NoError(t, err)
+	require.Equal(t, []repository.ReportGroup{
+		{Key: category.ID.String(), Label: category.Name, Count: 2, TotalIDR: 2_000_000},
+		{Key: "", Label: "", Count: 1, TotalIDR: 250_000},
+	}, byCategory)
+
+	all, err := reports.Totals(db, repository.ReportFilter{})
+	require.NoError(t, err)
+	require.Equal(t, repository.ReportTotals{Count: 4, TotalIDR: 11_250_000}, all)
+}
@@ -0,0 +1,43 @@
+package test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"go-expense-management-system/internal/integration/storage"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorageRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	local, err := storage.NewLocalStorage(t.TempDir(), logrus.New())
+	require.NoError(t, err)
+
+	key := "receipts/expense/receipt.pdf"
+	require.NoError(t, local.Put(ctx, key, strings.NewReader("%PDF-1.4")))
+
+	file, err := local.Open(ctx, key)
+	require.NoError(t, err)
+	content, err := io.ReadAll(file)
+	require.NoError(t, file.Close())
+	require.NoError(t, err)
+	require.Equal(t, "%PDF-1.4", string(content))
+
+	require.NoError(t, local.Delete(ctx, key))
+	_, err = local.Open(ctx, key)
+	require.Error(t, err)
+	require.NoError(t, local.Delete(ctx, key))
+}
+
+func TestLocalStorageRejectsEscapingKeys(t *testing.T) {
+	local, err := storage.NewLocalStorage(t.TempDir(), logrus.New())
+	require.NoError(t, err)
+
+	for _, key := range []string{"../outside.pdf", "receipts/../../outside.pdf", ""} {
+		require.Error(t, local.Put(context.Background(), key, strings.NewReader("x")), key)
+	}
+}
@@ -2,9 +2,11 @@ package test
 
 import (
 	"testing"
+	"time"
 
 	"go-expense-management-system/internal/utils"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
@@ -80,9 +82,102 @@ func TestNewPageMetadata(t *testing.T) {
 		meta := utils.NewPageMetadata(tt.page, tt.size, tt.total)
 		require.Equal(t, tt.wantPage, meta.CurrentPage, tt.name)
 		require.Equal(t, tt.wantSize, meta.PageSize, tt.name)
-		require.Equal(t, tt.wantTotal, meta.TotalItem, tt.name)
-		require.Equal(t, tt.wantPages, meta.TotalPage, tt.name)
+		require.Equal(t, tt.wantTotal, *meta.TotalItem, tt.name)
+		require.Equal(t, tt.wantPages, *meta.TotalPage, tt.name)
 		require.Equal(t, tt.wantHasNext, meta.HasNext, tt.name)
 		require.Equal(t, tt.wantHasPrev, meta.HasPrevious, tt.name)
 	}
 }
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := utils.Cursor{
+		Time:     time.Date(2026, 3, 1, 8, 30, 0, 123000, time.UTC),
+		ID:       uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		Backward: true,
+	}
+
+	decoded, err := utils.DecodeCursor(utils.EncodeCursor(cursor))
+	require.NoError(t, err)
+	require.True(t, cursor.Time.Equal(decoded.Time))
+	require.Equal(t, cursor.ID, decoded.ID)
+	require.True(t, decoded.Backward)
+
+	empty, err := utils.DecodeCursor("")
+	require.NoError(t, err)
+	require.Nil(t, empty)
+
+	for _, raw := range []string{"not-base64!", "e30", "bm9wZQ"} {
+		_, err := utils.DecodeCursor(raw)
+		require.ErrorIs(t, err, utils.ErrInvalidCursor, raw)
+	}
+}
+
+func TestNewCursorPageMetadata(t *testing.T) {
+	first := &utils.Cursor{Time: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), ID: uuid.New()}
+	last := &utils.Cursor{Time: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), ID: uuid.New()}
+	forward := &utils.Cursor{Time: first.Time, ID: uuid.New()}
+	backward := &utils.Cursor{Time: first.Time, ID: uuid.New(), Backward: true}
+
+	tests := []struct {
+		name        string
+		cursor      *utils.Cursor
+		hasMore     bool
+		wantHasNext bool
+		wantHasPrev bool
+	}{
+		{name: "first page with more", cursor: nil, hasMore: true, wantHasNext: true, wantHasPrev: false},
+		{name: "single page", cursor: nil, hasMore: false, wantHasNext: false, wantHasPrev: false},
+		{name: "forward middle page", cursor: forward, hasMore: true, wantHasNext: true, wantHasPrev: true},
+		{name: "forward last page", cursor: forward, hasMore: false, wantHasNext: false, wantHasPrev: true},
+		{name: "backward middle page", cursor: backward, hasMore: true, wantHasNext: true, wantHasPrev: true},
+		{name: "backward reached start", cursor: backward, hasMore: false, wantHasNext: true, wantHasPrev: false},
+	}
+
+	for _, tt := range tests {
+		meta := utils.NewCursorPageMetadata(10, tt.cursor, tt.hasMore, first, last, nil)
+		require.Equal(t, tt.wantHasNext, meta.HasNext, tt.name)
+		require.Equal(t, tt.wantHasPrev, meta.HasPrevious, tt.name)
+		require.Equal(t, tt.wantHasNext, meta.NextCursor != "", tt.name)
+		require.Equal(t, tt.wantHasPrev, meta.PrevCursor != "", tt.name)
+		require.Nil(t, meta.TotalItem, tt.name)
+
+		if tt.wantHasNext {
+			next, err := utils.DecodeCursor(meta.NextCursor)
+			require.NoError(t, err, tt.name)
+			require.Equal(t, last.ID, next.ID, tt.name)
+			require.False(t, next.Backward, tt.name)
+		}
+		if tt.wantHasPrev {
+			prev, err := utils.DecodeCursor(meta.PrevCursor)
+			require.NoError(t, err, tt.name)
+			require.Equal(t, first.ID, prev.ID, tt.name)
+			require.True(t, prev.Backward, tt.name)
+		}
+	}
+}
+
+func TestNormalizeText(t *testing.T) {
+	require.Equal(t, "taxi to airport 2", utils.NormalizeText("  Taxi -- to AIRPORT (#2) "))
+	require.Equal(t, "", utils.NormalizeText("!!!"))
+}
+
+func TestTextSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		min  float64
+		max  float64
+	}{
+		{name: "identical-after-normalize", a: "Taxi to airport", b: "taxi  to AIRPORT!", min: 1, max: 1},
+		{name: "similar", a: "Team lunch client visit", b: "Team lunch - client visit part 2", min: 0.7, max: 0.99},
+		{name: "different", a: "Laptop charger", b: "Hotel Jakarta", min: 0, max: 0.2},
+		{name: "empty", a: "", b: "Hotel", min: 0, max: 0},
+	}
+
+	for _, tt := range tests {
+		got := utils.TextSimilarity(tt.a, tt.b)
+		require.GreaterOrEqual(t, got, tt.min, tt.name)
+		require.LessOrEqual(t, got, tt.max, tt.name)
+	}
+}
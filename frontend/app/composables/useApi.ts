@@ -22,8 +22,9 @@ export const useApi = () => {
       auth.init();
     }
 
+    const isFormData = options.body instanceof FormData;
     const headers: Record<string, string> = {
-      "Content-Type": "application/json",
+      ...(isFormData ? {} : { "Content-Type": "application/json" }),
       ...options.headers,
     };
 
@@ -34,7 +35,7 @@ export const useApi = () => {
     const response = await fetch(`${apiBase}${path}`, {
       method: options.method || "GET",
       headers,
-      body: options.body ? JSON.stringify(options.body) : undefined,
+      body: isFormData ? (options.body as FormData) : options.body ? JSON.stringify(options.body) : undefined,
     });
 
     const isJson = response.headers.get("content-type")?.includes("application/json") ?? false;